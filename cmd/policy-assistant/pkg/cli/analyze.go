@@ -7,19 +7,26 @@ import (
 	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
 	"strings"
 
+	"github.com/mattfenwick/collections/pkg/builtin"
 	"github.com/mattfenwick/collections/pkg/json"
+	"github.com/mattfenwick/collections/pkg/slice"
 	"github.com/mattfenwick/cyclonus/pkg/connectivity/probe"
 	"github.com/mattfenwick/cyclonus/pkg/generator"
 
 	"github.com/mattfenwick/cyclonus/pkg/kube"
 	"github.com/mattfenwick/cyclonus/pkg/matcher"
 	"github.com/mattfenwick/cyclonus/pkg/utils"
+	"github.com/olekukonko/tablewriter"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -28,14 +35,32 @@ const (
 	QueryTrafficMode = "query-traffic"
 	QueryTargetMode  = "query-target"
 	ProbeMode        = "probe"
+	DiffMode         = "diff"
+	LintMode         = "lint"
+	WhoCanReachMode  = "who-can-reach"
 )
 
+const (
+	TableOutput = "table"
+	JSONOutput  = "json"
+	YAMLOutput  = "yaml"
+)
+
+var AllOutputs = []string{
+	TableOutput,
+	JSONOutput,
+	YAMLOutput,
+}
+
 var AllModes = []string{
 	ParseMode,
 	ExplainMode,
+	LintMode,
 	QueryTrafficMode,
 	QueryTargetMode,
 	ProbeMode,
+	DiffMode,
+	WhoCanReachMode,
 }
 
 type AnalyzeArgs struct {
@@ -46,16 +71,27 @@ type AnalyzeArgs struct {
 	Context            string
 	SimplifyPolicies   bool
 
-	Modes []string
+	Modes  []string
+	Output string
 
 	// traffic
-	TrafficPath string
+	TrafficPath        string
+	DomainResolverPath string
+	ServicePath        string
 
 	// targets
 	TargetPodPath string
 
 	// synthetic probe
 	ProbePath string
+
+	// diff
+	NewPolicyPath string
+
+	// who can reach
+	To   string
+	From string
+	Port string
 }
 
 func SetupAnalyzeCommand() *cobra.Command {
@@ -78,21 +114,48 @@ func SetupAnalyzeCommand() *cobra.Command {
 	command.Flags().BoolVar(&args.SimplifyPolicies, "simplify-policies", true, "if true, reduce policies to simpler form while preserving semantics")
 
 	command.Flags().StringSliceVar(&args.Modes, "mode", []string{ExplainMode}, "analysis modes to run; allowed values are "+strings.Join(AllModes, ","))
+	command.Flags().StringVarP(&args.Output, "output", "o", TableOutput, "output format; allowed values are "+strings.Join(AllOutputs, ","))
 
 	command.Flags().StringVar(&args.TargetPodPath, "target-pod-path", "", "path to json target pod file -- json array of dicts")
 	command.Flags().StringVar(&args.TrafficPath, "traffic-path", "", "path to json traffic file, containing of a list of traffic objects")
+	command.Flags().StringVar(&args.DomainResolverPath, "domain-resolver-path", "", "path to yaml/json file mapping hostnames to lists of IPs; used to resolve traffic to hostnames")
+	command.Flags().StringVar(&args.ServicePath, "service-path", "", "may be a file or a directory; Services, EndpointSlices, Pods and Namespaces used to resolve traffic to Services")
 	command.Flags().StringVar(&args.ProbePath, "probe-path", "", "path to json model file for synthetic probe")
+	command.Flags().StringVar(&args.NewPolicyPath, "new-policy-path", "", "may be a file or a directory; in diff mode, policies from this path replace those from --policy-path")
+	command.Flags().StringVar(&args.To, "to", "", "in who-can-reach mode, find which pods can send traffic to this 'namespace/name' pod")
+	command.Flags().StringVar(&args.From, "from", "", "in who-can-reach mode, find which pods this 'namespace/name' pod can send traffic to")
+	command.Flags().StringVar(&args.Port, "port", "", "in who-can-reach mode, the port number or name, and protocol (TCP if not set) of the traffic, e.g. '443/TCP'")
 
 	return command
 }
 
+// AnalyzeOutput is the machine-readable output of analyze, for json and yaml output.  Only the fields for the
+// modes which were run are set.
+type AnalyzeOutput struct {
+	InvalidPolicies matcher.ValidationErrors `json:"invalidPolicies,omitempty"`
+	Parse           *kube.Policies           `json:"parse,omitempty"`
+	Explain         *matcher.PolicyOutput    `json:"explain,omitempty"`
+	QueryTarget     []*QueryTargetOutput     `json:"queryTarget,omitempty"`
+	QueryTraffic    []*QueryTrafficOutput    `json:"queryTraffic,omitempty"`
+	Probe           []*ProbeOutput           `json:"probe,omitempty"`
+	Diff            []*DiffOutput            `json:"diff,omitempty"`
+	Lint            matcher.LintFindings     `json:"lint,omitempty"`
+	WhoCanReach     []*WhoCanReachOutput     `json:"whoCanReach,omitempty"`
+}
+
 func RunAnalyzeCommand(args *AnalyzeArgs) {
+	if !slices.Contains(AllOutputs, args.Output) {
+		logrus.Fatalf("%+v", errors.Errorf("invalid output %s; allowed values are %s", args.Output, strings.Join(AllOutputs, ",")))
+	}
+	printTables := args.Output == TableOutput
+
 	// 1. read policies from kube
 	var kubePolicies []*networkingv1.NetworkPolicy
 	var kubeANPs []*v1alpha1.AdminNetworkPolicy
-	var kubeBANPs *v1alpha1.BaselineAdminNetworkPolicy
+	var kubeBANPs []*v1alpha1.BaselineAdminNetworkPolicy
 	var kubePods []v1.Pod
 	var kubeNamespaces []v1.Namespace
+	kubeServices := &kube.Services{}
 	if args.AllNamespaces || len(args.Namespaces) > 0 {
 		kubeClient, err := kube.NewKubernetesForContext(args.Context)
 		utils.DoOrDie(err)
@@ -103,41 +166,61 @@ func RunAnalyzeCommand(args *AnalyzeArgs) {
 			utils.DoOrDie(err)
 			kubeNamespaces = nsList.Items
 			namespaces = []string{v1.NamespaceAll}
+		} else {
+			kubeNamespaces = readKubeNamespaces(kubeClient, namespaces)
 		}
 		kubePolicies, err = kube.ReadNetworkPoliciesFromKube(kubeClient, namespaces)
 		if err != nil {
 			logrus.Errorf("unable to read network policies from kube, ns '%s': %+v", namespaces, err)
 		}
+		// ANPs and BANPs are cluster-scoped, so they're read regardless of which namespaces were requested
+		kubeANPs, err = kube.ReadAdminNetworkPoliciesFromKube(kubeClient)
+		if err != nil {
+			logrus.Errorf("unable to read admin network policies from kube: %+v", err)
+		}
+		kubeBANPs, err = kube.ReadBaselineAdminNetworkPoliciesFromKube(kubeClient)
+		if err != nil {
+			logrus.Errorf("unable to read baseline admin network policies from kube: %+v", err)
+		}
 		kubePods, err = kube.GetPodsInNamespaces(kubeClient, namespaces)
 		if err != nil {
 			logrus.Errorf("unable to read pods from kube, ns '%s': %+v", namespaces, err)
 		}
+		kubeServices, err = kube.ReadServicesFromKube(kubeClient, namespaces)
+		if err != nil {
+			logrus.Errorf("unable to read services from kube, ns '%s': %+v", namespaces, err)
+			kubeServices = &kube.Services{}
+		}
+		kubeServices.Pods = slice.Map(builtin.Reference[v1.Pod], kubePods)
+		kubeServices.Namespaces = slice.Map(builtin.Reference[v1.Namespace], kubeNamespaces)
 	}
-	// 2. read policies from file
-	if args.PolicyPath != "" {
-		policiesFromPath, err := kube.ReadNetworkPoliciesFromPath(args.PolicyPath)
-		utils.DoOrDie(err)
-		kubePolicies = append(kubePolicies, policiesFromPath...)
-	}
-	// 3. read example policies
+	fromKube := &kube.Policies{NetworkPolicies: kubePolicies, AdminNetworkPolicies: kubeANPs, BaselineAdminNetworkPolicies: kubeBANPs}
+	// 2. read example policies
+	fromExamples := &kube.Policies{}
 	if args.UseExamplePolicies {
-		kubePolicies = append(kubePolicies, netpol.AllExamples...)
-
-		kubeANPs = examples.CoreGressRulesCombinedANB
-		kubeBANPs = examples.CoreGressRulesCombinedBANB
+		fromExamples.NetworkPolicies = netpol.AllExamples
+		fromExamples.AdminNetworkPolicies = examples.CoreGressRulesCombinedANB
+		fromExamples.BaselineAdminNetworkPolicies = []*v1alpha1.BaselineAdminNetworkPolicy{examples.CoreGressRulesCombinedBANB}
 	}
-
-	logrus.Debugf("parsed policies:\n%s", json.MustMarshalToString(kubePolicies))
-	policies := matcher.BuildV1AndV2NetPols(args.SimplifyPolicies, kubePolicies, kubeANPs, kubeBANPs)
+	// 3. read policies from file
+	allPolicies := combinePolicies(fromKube, args.PolicyPath, fromExamples)
+	policies, invalid := buildPolicies(args.SimplifyPolicies, allPolicies, printTables)
+	output := &AnalyzeOutput{InvalidPolicies: invalid}
 
 	for _, mode := range args.Modes {
 		switch mode {
 		case ParseMode:
-			fmt.Println("parsed policies:")
-			ParsePolicies(kubePolicies)
+			if printTables {
+				fmt.Println("parsed policies:")
+				ParsePolicies(allPolicies.NetworkPolicies)
+			}
+			output.Parse = allPolicies
 		case ExplainMode:
-			fmt.Println("explained policies:")
-			ExplainPolicies(policies)
+			if printTables {
+				fmt.Println("explained policies:")
+				ExplainPolicies(policies)
+			}
+			output.Explain = policies.Output()
 		case QueryTargetMode:
 			pods := make([]*QueryTargetPod, len(kubePods))
 			for i, p := range kubePods {
@@ -146,18 +229,133 @@ func RunAnalyzeCommand(args *AnalyzeArgs) {
 					Labels:    p.Labels,
 				}
 			}
-			fmt.Println("query target:")
-			QueryTargets(policies, args.TargetPodPath, pods)
+			if printTables {
+				fmt.Println("query target:")
+			}
+			output.QueryTarget = QueryTargets(policies, args.TargetPodPath, pods, printTables)
 		case QueryTrafficMode:
-			fmt.Println("query traffic:")
-			QueryTraffic(policies, args.TrafficPath)
+			if printTables {
+				fmt.Println("query traffic:")
+			}
+			output.QueryTraffic = QueryTraffic(policies, args.TrafficPath, args.DomainResolverPath, combineServices(kubeServices, args.ServicePath), printTables)
 		case ProbeMode:
-			fmt.Println("probe:")
-			ProbeSyntheticConnectivity(policies, args.ProbePath, kubePods, kubeNamespaces)
+			if printTables {
+				fmt.Println("probe:")
+			}
+			output.Probe = ProbeSyntheticConnectivity(policies, args.ProbePath, kubePods, kubeNamespaces, printTables)
+		case DiffMode:
+			if args.NewPolicyPath == "" {
+				logrus.Fatalf("%+v", errors.Errorf("path to new policies required for diff mode"))
+			}
+			// kube and example policies are shared by both sides of the diff
+			newPolicies, newInvalid := buildPolicies(args.SimplifyPolicies, combinePolicies(fromKube, args.NewPolicyPath, fromExamples), printTables)
+			output.InvalidPolicies = append(output.InvalidPolicies, newInvalid...)
+			if printTables {
+				fmt.Println("diff:")
+			}
+			output.Diff = DiffSyntheticConnectivity(policies, newPolicies, args.ProbePath, kubePods, kubeNamespaces, printTables)
+		case LintMode:
+			if printTables {
+				fmt.Println("lint:")
+			}
+			output.Lint = LintPolicies(policies, allPolicies.AdminNetworkPolicies, args.ProbePath, kubePods, kubeNamespaces, printTables)
+		case WhoCanReachMode:
+			if printTables {
+				fmt.Println("who can reach:")
+			}
+			out, err := WhoCanReach(policies, args.To, args.From, args.Port, readPods(args.ProbePath, kubePods, kubeNamespaces), printTables)
+			if err != nil {
+				logrus.Fatalf("%+v", err)
+			}
+			output.WhoCanReach = out
 		default:
 			panic(errors.Errorf("unrecognized mode %s", mode))
 		}
 	}
+
+	switch args.Output {
+	case JSONOutput:
+		fmt.Println(json.MustMarshalToString(output))
+	case YAMLOutput:
+		fmt.Print(utils.YamlString(output))
+	}
+}
+
+// combineServices returns the services from kube, then from servicePath (if set)
+func combineServices(fromKube *kube.Services, servicePath string) *kube.Services {
+	if servicePath == "" {
+		return fromKube
+	}
+	fromFile, err := kube.ReadServicesFromPath(servicePath)
+	utils.DoOrDie(err)
+	return &kube.Services{
+		Services:       append(append([]*v1.Service{}, fromKube.Services...), fromFile.Services...),
+		EndpointSlices: append(append([]*discoveryv1.EndpointSlice{}, fromKube.EndpointSlices...), fromFile.EndpointSlices...),
+		Pods:           append(append([]*v1.Pod{}, fromKube.Pods...), fromFile.Pods...),
+		Namespaces:     append(append([]*v1.Namespace{}, fromKube.Namespaces...), fromFile.Namespaces...),
+	}
+}
+
+// readKubeNamespaces reads the namespaces named by --namespace, so that namespace selectors match their labels.
+// A namespace which can't be read gets only the 'kubernetes.io/metadata.name' label, which every namespace has.
+func readKubeNamespaces(kubeClient kube.IKubernetes, names []string) []v1.Namespace {
+	var namespaces []v1.Namespace
+	for _, name := range names {
+		ns, err := kubeClient.GetNamespace(name)
+		if err != nil {
+			logrus.Errorf("unable to read namespace %s from kube, using only its name label: %+v", name, err)
+			ns = &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{v1.LabelMetadataName: name}}}
+		}
+		namespaces = append(namespaces, *ns)
+	}
+	return namespaces
+}
+
+// combinePolicies returns the policies from kube, then from policyPath (if set), then from the examples
+func combinePolicies(fromKube *kube.Policies, policyPath string, fromExamples *kube.Policies) *kube.Policies {
+	sources := []*kube.Policies{fromKube}
+	if policyPath != "" {
+		fromPath, err := kube.ReadPoliciesFromPath(policyPath)
+		utils.DoOrDie(err)
+		sources = append(sources, fromPath)
+	}
+	sources = append(sources, fromExamples)
+
+	combined := &kube.Policies{}
+	for _, source := range sources {
+		combined.NetworkPolicies = append(combined.NetworkPolicies, source.NetworkPolicies...)
+		combined.AdminNetworkPolicies = append(combined.AdminNetworkPolicies, source.AdminNetworkPolicies...)
+		combined.BaselineAdminNetworkPolicies = append(combined.BaselineAdminNetworkPolicies, source.BaselineAdminNetworkPolicies...)
+	}
+	return combined
+}
+
+// buildPolicies returns invalid policies, printing them if printTables is set, and builds the rest
+func buildPolicies(simplify bool, kubePolicies *kube.Policies, printTables bool) (*matcher.Policy, matcher.ValidationErrors) {
+	// a cluster has at most one BANP
+	var kubeBANP *v1alpha1.BaselineAdminNetworkPolicy
+	if banps := kubePolicies.BaselineAdminNetworkPolicies; len(banps) > 0 {
+		kubeBANP = banps[0]
+		if len(banps) > 1 {
+			logrus.Errorf("found %d baseline admin network policies; at most 1 is allowed, so only analyzing %s", len(banps), kubeBANP.Name)
+		}
+	}
+
+	logrus.Debugf("parsed policies:\n%s", json.MustMarshalToString(kubePolicies.NetworkPolicies))
+	logrus.Debugf("parsed admin network policies:\n%s", json.MustMarshalToString(kubePolicies.AdminNetworkPolicies))
+	logrus.Debugf("parsed baseline admin network policies:\n%s", json.MustMarshalToString(kubePolicies.BaselineAdminNetworkPolicies))
+	policies, err := matcher.BuildV1AndV2NetPols(simplify, kubePolicies.NetworkPolicies, kubePolicies.AdminNetworkPolicies, kubeBANP)
+	if err != nil {
+		var validationErrors matcher.ValidationErrors
+		if !errors.As(err, &validationErrors) {
+			utils.DoOrDie(err)
+		}
+		if printTables {
+			fmt.Printf("invalid policies (%d), which will not be analyzed:\n%s\n", len(validationErrors.InvalidPolicies()), validationErrors.Table())
+		}
+		return policies, validationErrors
+	}
+	return policies, nil
 }
 
 func ParsePolicies(kubePolicies []*networkingv1.NetworkPolicy) {
@@ -172,25 +370,35 @@ func ExplainPolicies(explainedPolicies *matcher.Policy) {
 //
 //	label, therefore we match by exact namespace and by pod labels.
 type QueryTargetPod struct {
-	Namespace string
-	Labels    map[string]string
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels"`
 }
 
-func QueryTargets(explainedPolicies *matcher.Policy, podPath string, pods []*QueryTargetPod) {
+type QueryTargetOutput struct {
+	Pod             *QueryTargetPod       `json:"pod"`
+	MatchingTargets *matcher.PolicyOutput `json:"matchingTargets"`
+	CombinedRules   *matcher.PolicyOutput `json:"combinedRules"`
+}
+
+func QueryTargets(explainedPolicies *matcher.Policy, podPath string, pods []*QueryTargetPod, printTables bool) []*QueryTargetOutput {
 	if podPath != "" {
 		podsFromFile, err := json.ParseFile[[]*QueryTargetPod](podPath)
 		utils.DoOrDie(err)
 		pods = append(pods, *podsFromFile...)
 	}
 
+	var out []*QueryTargetOutput
 	for _, pod := range pods {
-		fmt.Printf("pod in ns %s with labels %+v:\n\n", pod.Namespace, pod.Labels)
-
 		targets, combinedRules := QueryTargetHelper(explainedPolicies, pod)
+		out = append(out, &QueryTargetOutput{Pod: pod, MatchingTargets: targets.Output(), CombinedRules: combinedRules.Output()})
 
-		fmt.Printf("Matching targets:\n%s\n", targets.ExplainTable())
-		fmt.Printf("Combined rules:\n%s\n\n\n", combinedRules.ExplainTable())
+		if printTables {
+			fmt.Printf("pod in ns %s with labels %+v:\n\n", pod.Namespace, pod.Labels)
+			fmt.Printf("Matching targets:\n%s\n", targets.ExplainTable())
+			fmt.Printf("Combined rules:\n%s\n\n\n", combinedRules.ExplainTable())
+		}
 	}
+	return out
 }
 
 func QueryTargetHelper(policies *matcher.Policy, pod *QueryTargetPod) (*matcher.Policy, *matcher.Policy) {
@@ -216,19 +424,55 @@ func QueryTargetHelper(policies *matcher.Policy, pod *QueryTargetPod) (*matcher.
 	return matcher.NewPolicyWithTargets(ingressTargets, egressTargets), matcher.NewPolicyWithTargets(combinedIngresses, combinedEgresses)
 }
 
-func QueryTraffic(explainedPolicies *matcher.Policy, trafficPath string) {
+type QueryTrafficOutput struct {
+	Traffic *matcher.Traffic `json:"traffic"`
+	// Error is set, and Result isn't, if the traffic was skipped
+	Error  string                       `json:"error,omitempty"`
+	Result *matcher.AllowedResultOutput `json:"result,omitempty"`
+}
+
+// QueryTraffic determines whether each traffic is allowed.  Traffic to a Service is resolved to each of the
+// Service's backends, each with its own result.
+func QueryTraffic(explainedPolicies *matcher.Policy, trafficPath string, domainResolverPath string, services *kube.Services, printTables bool) []*QueryTrafficOutput {
 	if trafficPath == "" {
 		logrus.Fatalf("%+v", errors.Errorf("path to traffic file required for QueryTraffic command"))
 	}
 	allTraffics, err := json.ParseFile[[]*matcher.Traffic](trafficPath)
 	utils.DoOrDie(err)
 
+	var resolver matcher.DomainResolver = matcher.NewStaticDomainResolver(nil)
+	if domainResolverPath != "" {
+		resolver, err = matcher.NewStaticDomainResolverFromFile(domainResolverPath)
+		utils.DoOrDie(err)
+	}
+
+	serviceResolver := matcher.NewServiceResolver(services)
+
+	var out []*QueryTrafficOutput
 	for _, traffic := range *allTraffics {
-		fmt.Printf("Traffic:\n%s\n", traffic.Table())
+		if err := traffic.ResolveDestination(resolver); err != nil {
+			logrus.Errorf("skipping traffic: %+v", err)
+			out = append(out, &QueryTrafficOutput{Traffic: traffic, Error: err.Error()})
+			continue
+		}
+		backendTraffics, err := traffic.ResolveService(serviceResolver)
+		if err != nil {
+			logrus.Errorf("skipping traffic: %+v", err)
+			out = append(out, &QueryTrafficOutput{Traffic: traffic, Error: err.Error()})
+			continue
+		}
 
-		result := explainedPolicies.IsTrafficAllowed(traffic)
-		fmt.Printf("Is traffic allowed?\n%s\n\n\n", result.Table())
+		for _, backendTraffic := range backendTraffics {
+			result := explainedPolicies.IsTrafficAllowed(backendTraffic)
+			out = append(out, &QueryTrafficOutput{Traffic: backendTraffic, Result: result.Output()})
+
+			if printTables {
+				fmt.Printf("Traffic:\n%s\n", backendTraffic.Table())
+				fmt.Printf("Is traffic allowed?\n%s\n\n\n", result.Table())
+			}
+		}
 	}
+	return out
 }
 
 type SyntheticProbeConnectivityConfig struct {
@@ -236,7 +480,16 @@ type SyntheticProbeConnectivityConfig struct {
 	Probes    []*generator.PortProtocol
 }
 
-func ProbeSyntheticConnectivity(explainedPolicies *matcher.Policy, modelPath string, kubePods []v1.Pod, kubeNamespaces []v1.Namespace) {
+// ProbeOutput is the result of a simulated probe.  Port and Protocol are only set for probes from a model file;
+// otherwise, all available ports and protocols were probed.
+type ProbeOutput struct {
+	Port     *intstr.IntOrString      `json:"port,omitempty"`
+	Protocol v1.Protocol              `json:"protocol,omitempty"`
+	Results  []*probe.JobResultOutput `json:"results"`
+}
+
+func ProbeSyntheticConnectivity(explainedPolicies *matcher.Policy, modelPath string, kubePods []v1.Pod, kubeNamespaces []v1.Namespace, printTables bool) []*ProbeOutput {
+	var out []*ProbeOutput
 	if modelPath != "" {
 		config, err := json.ParseFile[SyntheticProbeConnectivityConfig](modelPath)
 		utils.DoOrDie(err)
@@ -249,24 +502,347 @@ func ProbeSyntheticConnectivity(explainedPolicies *matcher.Policy, modelPath str
 				RunProbeForConfig(generator.NewProbeConfig(probeConfig.Port, probeConfig.Protocol, generator.ProbeModeServiceName), config.Resources)
 
 			logrus.Infof("probe on port %s, protocol %s", probeConfig.Port.String(), probeConfig.Protocol)
+			out = append(out, &ProbeOutput{Port: &probeConfig.Port, Protocol: probeConfig.Protocol, Results: probeResult.Output()})
+
+			if printTables {
+				printSimulatedProbe(probeResult)
+			}
+		}
+	}
+
+	resources := resourcesFromKube(kubePods, kubeNamespaces)
+	simRunner := probe.NewSimulatedRunner(explainedPolicies, &probe.JobBuilder{TimeoutSeconds: 10})
+	simulatedProbe := simRunner.RunProbeForConfig(generator.ProbeAllAvailable, resources)
+	out = append(out, &ProbeOutput{Results: simulatedProbe.Output()})
+	if printTables {
+		printSimulatedProbe(simulatedProbe)
+	}
+	return out
+}
 
-			fmt.Printf("Ingress:\n%s\n", probeResult.RenderIngress())
+func printSimulatedProbe(simulatedProbe *probe.Table) {
+	fmt.Printf("Ingress:\n%s\n", simulatedProbe.RenderIngress())
+	fmt.Printf("Egress:\n%s\n", simulatedProbe.RenderEgress())
+	fmt.Printf("Combined:\n%s\n\n\n", simulatedProbe.RenderTable())
+}
 
-			fmt.Printf("Egress:\n%s\n", probeResult.RenderEgress())
+// DiffSyntheticConnectivity runs a simulated probe against both the old and new policies over the same resources,
+// and returns the traffic whose verdict changed.
+func DiffSyntheticConnectivity(oldPolicies *matcher.Policy, newPolicies *matcher.Policy, modelPath string, kubePods []v1.Pod, kubeNamespaces []v1.Namespace, printTables bool) []*DiffOutput {
+	var out []*DiffOutput
+	jobBuilder := &probe.JobBuilder{TimeoutSeconds: 10}
+	if modelPath != "" {
+		config, err := json.ParseFile[SyntheticProbeConnectivityConfig](modelPath)
+		utils.DoOrDie(err)
 
-			fmt.Printf("Combined:\n%s\n\n\n", probeResult.RenderTable())
+		for _, probeConfig := range config.Probes {
+			logrus.Infof("probe on port %s, protocol %s", probeConfig.Port.String(), probeConfig.Protocol)
+			jobs := jobBuilder.GetJobsForProbeConfig(config.Resources, generator.NewProbeConfig(probeConfig.Port, probeConfig.Protocol, generator.ProbeModeServiceName))
+			diffs := probe.DiffPolicies(oldPolicies, newPolicies, jobs.Valid)
+			out = append(out, &DiffOutput{Port: &probeConfig.Port, Protocol: probeConfig.Protocol, Changes: diffs.Output()})
+			if printTables {
+				printPolicyDiffs(diffs)
+			}
 		}
 	}
 
-	resources := &probe.Resources{
-		Namespaces: map[string]map[string]string{},
-		Pods:       []*probe.Pod{},
+	if len(kubePods) == 0 {
+		if modelPath == "" {
+			logrus.Warnf("no pods to diff; read pods from kube with -A/-n, or pass --probe-path")
+		}
+		return out
+	}
+	jobs := jobBuilder.GetJobsForProbeConfig(resourcesFromKube(kubePods, kubeNamespaces), generator.ProbeAllAvailable)
+	diffs := probe.DiffPolicies(oldPolicies, newPolicies, jobs.Valid)
+	out = append(out, &DiffOutput{Changes: diffs.Output()})
+	if printTables {
+		printPolicyDiffs(diffs)
+	}
+	return out
+}
+
+// DiffOutput is the traffic whose verdict changed in a diff.  As with ProbeOutput, Port and Protocol
+// are only set for probes from a model file.
+type DiffOutput struct {
+	Port     *intstr.IntOrString       `json:"port,omitempty"`
+	Protocol v1.Protocol               `json:"protocol,omitempty"`
+	Changes  []*probe.PolicyDiffOutput `json:"changes"`
+}
+
+// LintPolicies finds ANP and BANP rules which never decide traffic, and colliding ANP priorities.  BANP rules are
+// checked against the pods of the model file at modelPath, if set, or else those from kube.
+func LintPolicies(policies *matcher.Policy, anps []*v1alpha1.AdminNetworkPolicy, modelPath string, kubePods []v1.Pod, kubeNamespaces []v1.Namespace, printTables bool) matcher.LintFindings {
+	pods := slice.Map(func(pod *analyzedPod) *matcher.InternalPeer { return pod.Peer.Internal }, readPods(modelPath, kubePods, kubeNamespaces))
+	findings := matcher.Lint(policies, anps, pods)
+	if printTables {
+		if len(findings) == 0 {
+			fmt.Printf("no problems found\n\n\n")
+		} else {
+			fmt.Printf("problems (%d):\n%s\n\n\n", len(findings), findings.Table())
+		}
+	}
+	return findings
+}
+
+// analyzedPod is a pod from kube or from a probe model file, along with the ports its containers serve
+type analyzedPod struct {
+	Name  string
+	Peer  *matcher.TrafficPeer
+	Ports []v1.ContainerPort
+}
+
+func (p *analyzedPod) Key() string {
+	return fmt.Sprintf("%s/%s", p.Peer.Internal.Namespace, p.Name)
+}
+
+// resolveNamedPort finds the number of the port which the pod serves under name on protocol
+func (p *analyzedPod) resolveNamedPort(name string, protocol v1.Protocol) (int, bool) {
+	for _, port := range p.Ports {
+		portProtocol := port.Protocol
+		if portProtocol == "" {
+			portProtocol = v1.ProtocolTCP
+		}
+		if port.Name == name && portProtocol == protocol {
+			return int(port.ContainerPort), true
+		}
+	}
+	return 0, false
+}
+
+// readPods returns the pods of the probe model file at modelPath, if set, or else those from kube.
+// Unlike probes, pods without ports are included.
+func readPods(modelPath string, kubePods []v1.Pod, kubeNamespaces []v1.Namespace) []*analyzedPod {
+	var pods []*analyzedPod
+	if modelPath != "" {
+		config, err := json.ParseFile[SyntheticProbeConnectivityConfig](modelPath)
+		utils.DoOrDie(err)
+		for _, pod := range config.Resources.Pods {
+			var ports []v1.ContainerPort
+			for _, cont := range pod.Containers {
+				ports = append(ports, v1.ContainerPort{Name: cont.PortName, ContainerPort: int32(cont.Port), Protocol: cont.Protocol})
+			}
+			pods = append(pods, &analyzedPod{Name: pod.Name, Peer: &matcher.TrafficPeer{
+				Internal: &matcher.InternalPeer{Namespace: pod.Namespace, NamespaceLabels: config.Resources.Namespaces[pod.Namespace], PodLabels: pod.Labels},
+				IP:       pod.IP,
+			}, Ports: ports})
+		}
+		return pods
+	}
+
+	namespaceLabels := kubeNamespaceLabels(kubePods, kubeNamespaces)
+	for _, pod := range kubePods {
+		var ports []v1.ContainerPort
+		for _, cont := range pod.Spec.Containers {
+			ports = append(ports, cont.Ports...)
+		}
+		pods = append(pods, &analyzedPod{Name: pod.Name, Peer: &matcher.TrafficPeer{
+			Internal: &matcher.InternalPeer{Namespace: pod.Namespace, NamespaceLabels: namespaceLabels[pod.Namespace], PodLabels: pod.Labels},
+			IP:       pod.Status.PodIP,
+		}, Ports: ports})
 	}
+	return pods
+}
 
-	nsMap := map[string]v1.Namespace{}
+// kubeNamespaceLabels returns the labels of the namespaces read from kube, and of the namespaces of kubePods.
+// Namespaces which weren't read from kube get only the 'kubernetes.io/metadata.name' label, as the service
+// resolver does.
+func kubeNamespaceLabels(kubePods []v1.Pod, kubeNamespaces []v1.Namespace) map[string]map[string]string {
+	namespaceLabels := map[string]map[string]string{}
 	for _, ns := range kubeNamespaces {
-		nsMap[ns.Name] = ns
-		resources.Namespaces[ns.Name] = ns.Labels
+		namespaceLabels[ns.Name] = ns.Labels
+	}
+	for _, pod := range kubePods {
+		if _, ok := namespaceLabels[pod.Namespace]; !ok {
+			namespaceLabels[pod.Namespace] = map[string]string{v1.LabelMetadataName: pod.Namespace}
+		}
+	}
+	return namespaceLabels
+}
+
+// WhoCanReachOutput is the result of traffic between a pod and every other pod, grouped by namespace
+type WhoCanReachOutput struct {
+	// To is set if the pod is the destination of the traffic, and From if it's the source
+	To         string                        `json:"to,omitempty"`
+	From       string                        `json:"from,omitempty"`
+	Port       string                        `json:"port"`
+	Namespaces []*WhoCanReachNamespaceOutput `json:"namespaces"`
+}
+
+type WhoCanReachNamespaceOutput struct {
+	Namespace string                  `json:"namespace"`
+	Allowed   int                     `json:"allowed"`
+	Pods      []*WhoCanReachPodOutput `json:"pods"`
+}
+
+// WhoCanReachPodOutput is the result of traffic with a single pod, with the effects which decided each direction.
+// Effects are left out if no policies applied.  Error is set instead if the traffic is to a named port which the
+// destination doesn't serve, in which case the traffic isn't allowed.
+type WhoCanReachPodOutput struct {
+	Pod           string                `json:"pod"`
+	Allowed       bool                  `json:"allowed"`
+	IngressEffect *matcher.EffectOutput `json:"ingressEffect,omitempty"`
+	EgressEffect  *matcher.EffectOutput `json:"egressEffect,omitempty"`
+	Error         string                `json:"error,omitempty"`
+}
+
+// WhoCanReach finds which pods can send traffic to the pod `to`, and which pods the pod `from` can send traffic
+// to, on port.  Pods are given as "namespace/name", and port as e.g. "443/TCP".  A named port is resolved to its
+// number on each destination pod, as kube does, so that rules on either the name or the number match it.
+func WhoCanReach(policies *matcher.Policy, to string, from string, port string, pods []*analyzedPod, printTables bool) ([]*WhoCanReachOutput, error) {
+	if to == "" && from == "" {
+		return nil, errors.Errorf("--to or --from pod required for who-can-reach mode")
+	}
+	portIntOrString, protocol, err := parsePortProtocol(port)
+	if err != nil {
+		return nil, err
+	}
+	podsByKey := map[string]*analyzedPod{}
+	for _, pod := range pods {
+		podsByKey[pod.Key()] = pod
+	}
+
+	var out []*WhoCanReachOutput
+	for _, query := range []struct {
+		Pod  string
+		IsTo bool
+	}{{to, true}, {from, false}} {
+		if query.Pod == "" {
+			continue
+		}
+		subject, ok := podsByKey[query.Pod]
+		if !ok {
+			return nil, errors.Errorf("pod %s not found; pods are read from --probe-path or kube, and given as 'namespace/name'", query.Pod)
+		}
+		if query.IsTo && portIntOrString.Type == intstr.String {
+			if _, ok := subject.resolveNamedPort(portIntOrString.StrVal, protocol); !ok {
+				return nil, errors.Errorf("pod %s has no port named %s on %s", query.Pod, portIntOrString.StrVal, protocol)
+			}
+		}
+
+		result := &WhoCanReachOutput{Port: fmt.Sprintf("%s/%s", portIntOrString.String(), protocol)}
+		if query.IsTo {
+			result.To = query.Pod
+		} else {
+			result.From = query.Pod
+		}
+		var rows [][]string
+		byNamespace := slice.GroupOn(func(pod *analyzedPod) string { return pod.Peer.Internal.Namespace }, pods)
+		for _, ns := range slice.Sort(maps.Keys(byNamespace)) {
+			nsResult := &WhoCanReachNamespaceOutput{Namespace: ns}
+			for _, pod := range slice.SortOn((*analyzedPod).Key, byNamespace[ns]) {
+				if pod == subject {
+					continue
+				}
+				source, destination := pod, subject
+				if !query.IsTo {
+					source, destination = subject, pod
+				}
+				traffic := &matcher.Traffic{Source: source.Peer, Destination: destination.Peer, Protocol: protocol}
+				if portIntOrString.Type == intstr.Int {
+					traffic.ResolvedPort = portIntOrString.IntValue()
+				} else {
+					portNumber, ok := destination.resolveNamedPort(portIntOrString.StrVal, protocol)
+					if !ok {
+						podResult := &WhoCanReachPodOutput{Pod: pod.Name, Error: fmt.Sprintf("no port named %s on %s", portIntOrString.StrVal, protocol)}
+						nsResult.Pods = append(nsResult.Pods, podResult)
+						rows = append(rows, []string{ns, pod.Name, "false", podResult.Error, ""})
+						continue
+					}
+					traffic.ResolvedPort = portNumber
+					traffic.ResolvedPortName = portIntOrString.StrVal
+				}
+
+				allowed := policies.IsTrafficAllowed(traffic)
+				podResult := &WhoCanReachPodOutput{Pod: pod.Name, Allowed: allowed.IsAllowed()}
+				row := []string{ns, pod.Name, fmt.Sprintf("%t", podResult.Allowed), "no policies", "no policies"}
+				if winner := allowed.Ingress.Winner(); winner != nil {
+					podResult.IngressEffect = winner.Output()
+					row[3] = winner.String()
+				}
+				if winner := allowed.Egress.Winner(); winner != nil {
+					podResult.EgressEffect = winner.Output()
+					row[4] = winner.String()
+				}
+				if podResult.Allowed {
+					nsResult.Allowed++
+				}
+				nsResult.Pods = append(nsResult.Pods, podResult)
+				rows = append(rows, row)
+			}
+			if len(nsResult.Pods) > 0 {
+				result.Namespaces = append(result.Namespaces, nsResult)
+			}
+		}
+		out = append(out, result)
+
+		if printTables {
+			printWhoCanReach(result, rows)
+		}
+	}
+	return out, nil
+}
+
+// parsePortProtocol parses e.g. "443/TCP", "http/TCP" or "53/UDP".  The protocol defaults to TCP.
+func parsePortProtocol(portProtocol string) (intstr.IntOrString, v1.Protocol, error) {
+	if portProtocol == "" {
+		return intstr.IntOrString{}, "", errors.Errorf("--port required for who-can-reach mode")
+	}
+	port, protocolString, hasProtocol := strings.Cut(portProtocol, "/")
+	protocol := v1.ProtocolTCP
+	if hasProtocol {
+		var err error
+		protocol, err = kube.ParseProtocol(protocolString)
+		if err != nil {
+			return intstr.IntOrString{}, "", err
+		}
+	}
+	if port == "" {
+		return intstr.IntOrString{}, "", errors.Errorf("missing port in --port %s", portProtocol)
+	}
+	parsed := intstr.Parse(port)
+	if parsed.Type == intstr.Int && (parsed.IntVal < 1 || parsed.IntVal > 65535) {
+		return intstr.IntOrString{}, "", errors.Errorf("port %s in --port %s must be between 1 and 65535", port, portProtocol)
+	}
+	return parsed, protocol, nil
+}
+
+// printWhoCanReach prints a row for each pod, with its namespace's pods grouped together
+func printWhoCanReach(result *WhoCanReachOutput, rows [][]string) {
+	if result.To != "" {
+		fmt.Printf("pods which can reach %s on %s:\n", result.To, result.Port)
+	} else {
+		fmt.Printf("pods which %s can reach on %s:\n", result.From, result.Port)
+	}
+
+	allowedCounts := map[string]string{}
+	for _, ns := range result.Namespaces {
+		allowedCounts[ns.Namespace] = fmt.Sprintf("%s (%d/%d allowed)", ns.Namespace, ns.Allowed, len(ns.Pods))
+	}
+	tableString := &strings.Builder{}
+	table := tablewriter.NewWriter(tableString)
+	table.SetAutoWrapText(false)
+	table.SetRowLine(true)
+	table.SetAutoMergeCellsByColumnIndex([]int{0})
+	table.SetHeader([]string{"Namespace", "Pod", "Allowed", "Ingress decided by", "Egress decided by"})
+	for _, row := range rows {
+		table.Append(append([]string{allowedCounts[row[0]]}, row[1:]...))
+	}
+	table.Render()
+	fmt.Printf("%s\n\n", tableString.String())
+}
+
+func printPolicyDiffs(diffs probe.PolicyDiffs) {
+	if len(diffs) == 0 {
+		fmt.Printf("no changes\n\n\n")
+		return
+	}
+	fmt.Printf("changed traffic (%d):\n%s\n\n\n", len(diffs), diffs.Table())
+}
+
+func resourcesFromKube(kubePods []v1.Pod, kubeNamespaces []v1.Namespace) *probe.Resources {
+	resources := &probe.Resources{
+		Namespaces: kubeNamespaceLabels(kubePods, kubeNamespaces),
+		Pods:       []*probe.Pod{},
 	}
 
 	for _, pod := range kubePods {
@@ -297,9 +873,5 @@ func ProbeSyntheticConnectivity(explainedPolicies *matcher.Policy, modelPath str
 		})
 	}
 
-	simRunner := probe.NewSimulatedRunner(explainedPolicies, &probe.JobBuilder{TimeoutSeconds: 10})
-	simulatedProbe := simRunner.RunProbeForConfig(generator.ProbeAllAvailable, resources)
-	fmt.Printf("Ingress:\n%s\n", simulatedProbe.RenderIngress())
-	fmt.Printf("Egress:\n%s\n", simulatedProbe.RenderEgress())
-	fmt.Printf("Combined:\n%s\n\n\n", simulatedProbe.RenderTable())
+	return resources
 }
@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"github.com/mattfenwick/collections/pkg/builtin"
+	"github.com/mattfenwick/collections/pkg/slice"
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	"github.com/mattfenwick/cyclonus/pkg/matcher"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
+)
+
+func RunAnalyzeTests() {
+	Describe("parsePortProtocol", func() {
+		It("should parse port numbers and names with a protocol", func() {
+			port, protocol, err := parsePortProtocol("53/UDP")
+			Expect(err).To(Succeed())
+			Expect(port).To(Equal(intstr.FromInt(53)))
+			Expect(protocol).To(Equal(v1.ProtocolUDP))
+
+			port, protocol, err = parsePortProtocol("http/sctp")
+			Expect(err).To(Succeed())
+			Expect(port).To(Equal(intstr.FromString("http")))
+			Expect(protocol).To(Equal(v1.ProtocolSCTP))
+		})
+
+		It("should default to TCP", func() {
+			port, protocol, err := parsePortProtocol("443")
+			Expect(err).To(Succeed())
+			Expect(port).To(Equal(intstr.FromInt(443)))
+			Expect(protocol).To(Equal(v1.ProtocolTCP))
+		})
+
+		It("should reject a missing or out of range port, or an unknown protocol", func() {
+			for _, portProtocol := range []string{"", "/TCP", "0/TCP", "-1", "65536/UDP", "80/ICMP"} {
+				_, _, err := parsePortProtocol(portProtocol)
+				Expect(err).To(HaveOccurred(), portProtocol)
+			}
+		})
+	})
+
+	Describe("WhoCanReach", func() {
+		policyYaml := `
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: allow-b-on-80
+  namespace: x
+spec:
+  podSelector:
+    matchLabels:
+      pod: a
+  policyTypes:
+  - Ingress
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels:
+          pod: b
+    ports:
+    - port: 80
+      protocol: TCP`
+		netpol, err := utils.ParseYamlStrict[networkingv1.NetworkPolicy]([]byte(policyYaml))
+		utils.DoOrDie(err)
+		policies, err := matcher.BuildNetworkPolicies(true, []*networkingv1.NetworkPolicy{netpol})
+		utils.DoOrDie(err)
+
+		newPod := func(namespace string, name string, ports ...v1.ContainerPort) *analyzedPod {
+			return &analyzedPod{
+				Name: name,
+				Peer: &matcher.TrafficPeer{
+					Internal: &matcher.InternalPeer{Namespace: namespace, NamespaceLabels: map[string]string{"ns": namespace}, PodLabels: map[string]string{"pod": name}},
+					IP:       "10.0.0.1",
+				},
+				Ports: ports,
+			}
+		}
+		http := v1.ContainerPort{Name: "http", ContainerPort: 80, Protocol: v1.ProtocolTCP}
+		pods := []*analyzedPod{newPod("x", "a", http), newPod("x", "b", http), newPod("x", "c"), newPod("y", "b")}
+
+		It("should split the pods which can reach a pod into allowed and denied", func() {
+			out, err := WhoCanReach(policies, "x/a", "", "80/TCP", pods, false)
+			Expect(err).To(Succeed())
+			Expect(out).To(HaveLen(1))
+			Expect(out[0].To).To(Equal("x/a"))
+			Expect(out[0].Port).To(Equal("80/TCP"))
+			Expect(out[0].Namespaces).To(HaveLen(2))
+
+			x := out[0].Namespaces[0]
+			Expect(x.Namespace).To(Equal("x"))
+			Expect(x.Allowed).To(Equal(1))
+			Expect(x.Pods).To(HaveLen(2))
+			Expect(x.Pods[0].Pod).To(Equal("b"))
+			Expect(x.Pods[0].Allowed).To(BeTrue())
+			Expect(x.Pods[0].IngressEffect.RulePath).To(Equal("ingress[0]"))
+			Expect(x.Pods[0].EgressEffect).To(BeNil())
+			Expect(x.Pods[1].Pod).To(Equal("c"))
+			Expect(x.Pods[1].Allowed).To(BeFalse())
+			Expect(x.Pods[1].IngressEffect.Verdict).To(Equal(matcher.None))
+
+			// the peer only selects pods in x
+			y := out[0].Namespaces[1]
+			Expect(y.Namespace).To(Equal("y"))
+			Expect(y.Allowed).To(Equal(0))
+		})
+
+		It("should resolve a named port to its number on the destination", func() {
+			out, err := WhoCanReach(policies, "x/a", "", "http/TCP", pods, false)
+			Expect(err).To(Succeed())
+			Expect(out[0].Namespaces[0].Pods[0].Pod).To(Equal("b"))
+			Expect(out[0].Namespaces[0].Pods[0].Allowed).To(BeTrue())
+		})
+
+		It("should report destinations which don't serve a named port", func() {
+			out, err := WhoCanReach(policies, "", "x/b", "http/TCP", pods, false)
+			Expect(err).To(Succeed())
+			Expect(out).To(HaveLen(1))
+			x := out[0].Namespaces[0]
+			Expect(x.Allowed).To(Equal(1))
+			Expect(x.Pods[0].Pod).To(Equal("a"))
+			Expect(x.Pods[0].Allowed).To(BeTrue())
+			Expect(x.Pods[1].Pod).To(Equal("c"))
+			Expect(x.Pods[1].Allowed).To(BeFalse())
+			Expect(x.Pods[1].Error).To(Equal("no port named http on TCP"))
+
+			_, err = WhoCanReach(policies, "x/c", "", "http/TCP", pods, false)
+			Expect(err).To(MatchError("pod x/c has no port named http on TCP"))
+		})
+
+		It("should reject bad arguments", func() {
+			_, err := WhoCanReach(policies, "", "", "80/TCP", pods, false)
+			Expect(err).To(HaveOccurred())
+			_, err = WhoCanReach(policies, "x/a", "", "80/ICMP", pods, false)
+			Expect(err).To(HaveOccurred())
+			_, err = WhoCanReach(policies, "x/missing", "", "80/TCP", pods, false)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("readKubeNamespaces", func() {
+		kubeClient := kube.NewMockKubernetes(1.0)
+		_, err := kubeClient.CreateNamespace(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "demo", Labels: map[string]string{"team": "web"}}})
+		utils.DoOrDie(err)
+
+		It("should read the labels of each namespace, and fall back to the name label", func() {
+			namespaces := readKubeNamespaces(kubeClient, []string{"demo", "missing"})
+			Expect(namespaces).To(HaveLen(2))
+			Expect(namespaces[0].Labels).To(Equal(map[string]string{"kubernetes.io/metadata.name": "demo", "team": "web"}))
+			Expect(namespaces[1].Labels).To(Equal(map[string]string{"kubernetes.io/metadata.name": "missing"}))
+		})
+
+		It("should let namespace selectors match the backends of services", func() {
+			anp, err := utils.ParseYamlStrict[v1alpha1.AdminNetworkPolicy]([]byte(`
+apiVersion: policy.networking.k8s.io/v1alpha1
+kind: AdminNetworkPolicy
+metadata:
+  name: deny-to-web-team
+spec:
+  priority: 10
+  subject:
+    namespaces:
+      matchLabels:
+        team: web
+  ingress:
+  - name: deny-all
+    action: Deny
+    from:
+    - namespaces: {}`))
+			utils.DoOrDie(err)
+			policies, err := matcher.BuildV1AndV2NetPols(true, nil, []*v1alpha1.AdminNetworkPolicy{anp}, nil)
+			utils.DoOrDie(err)
+
+			// as with --namespace, the services and pods are read from kube, but not the namespaces
+			services, err := kube.ReadServicesFromPath("../../examples/services.yaml")
+			utils.DoOrDie(err)
+			services.Namespaces = slice.Map(builtin.Reference[v1.Namespace], readKubeNamespaces(kubeClient, []string{"demo"}))
+
+			traffic := &matcher.Traffic{
+				Source: &matcher.TrafficPeer{
+					Internal: &matcher.InternalPeer{Namespace: "x", NamespaceLabels: map[string]string{"kubernetes.io/metadata.name": "x"}, PodLabels: map[string]string{"pod": "a"}},
+					IP:       "192.168.1.1",
+				},
+				Destination: &matcher.TrafficPeer{Service: &matcher.ServicePeer{Namespace: "demo", Name: "web", Port: intstr.FromString("http")}},
+				Protocol:    v1.ProtocolTCP,
+			}
+			traffics, err := traffic.ResolveService(matcher.NewServiceResolver(services))
+			Expect(err).To(Succeed())
+			Expect(traffics).ToNot(BeEmpty())
+			for _, t := range traffics {
+				Expect(policies.IsTrafficAllowed(t).IsAllowed()).To(BeFalse())
+			}
+		})
+	})
+
+	Describe("WhoCanReach with pods from kube", func() {
+		kubeClient := kube.NewMockKubernetes(1.0)
+		for name, labels := range map[string]map[string]string{"x": nil, "y": {"team": "web"}} {
+			_, err := kubeClient.CreateNamespace(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}})
+			utils.DoOrDie(err)
+		}
+		kubePod := func(namespace string, name string) v1.Pod {
+			return v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: map[string]string{"pod": name}},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 80, Protocol: v1.ProtocolTCP}}}}},
+			}
+		}
+		kubePods := []v1.Pod{kubePod("x", "a"), kubePod("y", "b"), kubePod("z", "c")}
+
+		netpol, err := utils.ParseYamlStrict[networkingv1.NetworkPolicy]([]byte(`
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: allow-from-web-team
+  namespace: x
+spec:
+  podSelector: {}
+  policyTypes:
+  - Ingress
+  ingress:
+  - from:
+    - namespaceSelector:
+        matchLabels:
+          team: web`))
+		utils.DoOrDie(err)
+		policies, err := matcher.BuildNetworkPolicies(true, []*networkingv1.NetworkPolicy{netpol})
+		utils.DoOrDie(err)
+
+		It("should match namespace selectors against the namespaces named by --namespace", func() {
+			// z isn't read from kube, so it only has its name label
+			pods := readPods("", kubePods, readKubeNamespaces(kubeClient, []string{"x", "y"}))
+			Expect(pods[2].Peer.Internal.NamespaceLabels).To(Equal(map[string]string{"kubernetes.io/metadata.name": "z"}))
+
+			out, err := WhoCanReach(policies, "x/a", "", "80/TCP", pods, false)
+			Expect(err).To(Succeed())
+			allowed := map[string]bool{}
+			for _, ns := range out[0].Namespaces {
+				for _, pod := range ns.Pods {
+					allowed[ns.Namespace+"/"+pod.Pod] = pod.Allowed
+				}
+			}
+			Expect(allowed).To(Equal(map[string]bool{"y/b": true, "z/c": false}))
+		})
+
+		It("should give simulated probes the same namespace labels", func() {
+			kubeNamespaces := readKubeNamespaces(kubeClient, []string{"x", "y"})
+			resources := resourcesFromKube(kubePods, kubeNamespaces)
+			for _, pod := range readPods("", kubePods, kubeNamespaces) {
+				Expect(resources.Namespaces[pod.Peer.Internal.Namespace]).To(Equal(pod.Peer.Internal.NamespaceLabels))
+			}
+		})
+	})
+}
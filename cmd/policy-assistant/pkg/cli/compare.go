@@ -1,14 +1,38 @@
 package cli
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/mattfenwick/cyclonus/pkg/connectivity"
+	"github.com/mattfenwick/cyclonus/pkg/connectivity/probe"
+	"github.com/mattfenwick/cyclonus/pkg/generator"
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	networkingv1 "k8s.io/api/networking/v1"
 )
 
 type CompareArgs struct {
 	Noisy                     bool
-	NetpolCreationWaitSeconds int
+	IgnoreLoopback            bool
+	AllowDNS                  bool
+	PerturbationWaitSeconds   int
+	PodCreationTimeoutSeconds int
+	JobTimeoutSeconds         int
+	Retries                   int
 	Contexts                  []string
+	ServerPorts               []int
+	ServerProtocols           []string
+	ServerNamespaces          []string
+	ServerPods                []string
+	ExternalTargets           []string
+	PolicyPath                string
+	Include                   []string
+	Exclude                   []string
+	CleanupNamespaces         bool
+	Mock                      bool
+	ImageRegistry             string
 }
 
 func SetupCompareCommand() *cobra.Command {
@@ -24,105 +48,144 @@ func SetupCompareCommand() *cobra.Command {
 		},
 	}
 
+	command.Flags().StringSliceVar(&args.Contexts, "context", []string{}, "kubernetes contexts to compare; if empty, uses default context")
+
+	command.Flags().StringSliceVar(&args.ServerProtocols, "server-protocol", []string{"TCP", "UDP", "SCTP"}, "protocols to run server on")
+	command.Flags().IntSliceVar(&args.ServerPorts, "server-port", []int{80, 81}, "ports to run server on")
+	command.Flags().StringSliceVar(&args.ServerNamespaces, "namespace", []string{"x", "y", "z"}, "namespaces to create/use pods in")
+	command.Flags().StringSliceVar(&args.ServerPods, "pod", []string{"a", "b", "c"}, "pods to create in namespaces")
+	command.Flags().StringSliceVar(&args.ExternalTargets, "external-target", []string{}, "external IPs to probe, as ip:port/protocol (e.g. 8.8.8.8:53/UDP), or as cidr:port/protocol to probe the first host address of a CIDR; these are probed from every pod, to test egress to destinations outside the cluster")
+
+	command.Flags().StringVar(&args.PolicyPath, "policy-path", "", "may be a file or a directory; if set, compares only these network policies instead of generated test cases")
+	command.Flags().StringSliceVar(&args.Include, "include", []string{}, "include generated tests with any of these tags; if empty, all tests will be included.  Valid tags:\n"+strings.Join(generator.TagSlice, "\n"))
+	command.Flags().StringSliceVar(&args.Exclude, "exclude", DefaultExcludeTags, "exclude generated tests with any of these tags.  See 'include' field for valid tags")
+	command.Flags().BoolVar(&args.AllowDNS, "allow-dns", true, "if using egress, allow tcp and udp over port 53 for DNS resolution")
+
+	command.Flags().IntVar(&args.Retries, "retries", 1, "number of kube probe retries to allow, if probe fails")
 	command.Flags().BoolVar(&args.Noisy, "noisy", false, "if true, print all results")
-	command.Flags().IntVar(&args.NetpolCreationWaitSeconds, "netpol-creation-wait-seconds", 5, "number of seconds to wait after creating a network policy before running probes, to give the CNI time to update the cluster state")
-	command.Flags().StringSliceVar(&args.Contexts, "context", []string{}, "kubernetes context to use; if empty, uses default context")
+	command.Flags().BoolVar(&args.IgnoreLoopback, "ignore-loopback", false, "if true, ignore loopback when comparing results")
+	command.Flags().IntVar(&args.PerturbationWaitSeconds, "perturbation-wait-seconds", 5, "number of seconds to wait after perturbing the cluster (i.e. create a network policy, modify a ns/pod label) before running probes, to give the CNI time to update the cluster state")
+	command.Flags().IntVar(&args.PodCreationTimeoutSeconds, "pod-creation-timeout-seconds", 60, "number of seconds to wait for pods to create, be running and have IP addresses")
+	command.Flags().IntVar(&args.JobTimeoutSeconds, "job-timeout-seconds", 10, "number of seconds to pass on to 'agnhost connect --timeout=%ds' flag")
+	command.Flags().BoolVar(&args.CleanupNamespaces, "cleanup-namespaces", false, "if true, clean up namespaces after completion")
+	command.Flags().BoolVar(&args.Mock, "mock", false, "if true, use mock kube runners (i.e. don't actually run tests against kubernetes; instead, produce fake results")
+	command.Flags().StringVar(&args.ImageRegistry, "image-registry", "registry.k8s.io", "Image registry for agnhost")
 
 	return command
 }
 
 func RunCompareCommand(args *CompareArgs) {
-	panic("TODO")
-	/*
-		namespaces := []string{"x", "y", "z"}
-		pods := []string{"a", "b", "c"}
-
-		protocol := v1.ProtocolTCP
-		probePort := intstr.FromInt(80)
+	contexts := args.Contexts
+	if len(contexts) == 0 {
+		contexts = []string{""}
+	}
 
-		serverPort := 80
+	serverProtocols := parseProtocols(args.ServerProtocols)
+	externalIPs, err := probe.ParseExternalIPs(args.ExternalTargets)
+	utils.DoOrDie(err)
+	interpreterConfig := &connectivity.InterpreterConfig{
+		ResetClusterBeforeTestCase:       true,
+		KubeProbeRetries:                 args.Retries,
+		PerturbationWaitSeconds:          args.PerturbationWaitSeconds,
+		VerifyClusterStateBeforeTestCase: true,
+		IgnoreLoopback:                   args.IgnoreLoopback,
+		JobTimeoutSeconds:                args.JobTimeoutSeconds,
+	}
 
-		kubeClients := map[string]*kube.Kubernetes{}
-		if len(args.Contexts) == 0 {
-			kubernetes, err := kube.NewKubernetesForContext("")
-			utils.DoOrDie(err)
-			kubeClients["default-context"] = kubernetes
-		} else {
-			for _, context := range args.Contexts {
-				kubernetes, err := kube.NewKubernetesForContext(context)
-				utils.DoOrDie(err)
-				kubeClients[context] = kubernetes
-			}
+	// 1. set up the same resources in each context
+	kubeClients := map[string]kube.IKubernetes{}
+	interpreters := map[string]*connectivity.Interpreter{}
+	var zcIP string
+	for i, context := range contexts {
+		contextName := context
+		if contextName == "" {
+			contextName = "default-context"
+		}
+		if _, ok := interpreters[contextName]; ok {
+			logrus.Fatalf("duplicate context %s", contextName)
 		}
 
-		kubeResources := map[string]*connectivitykube.Resources{}
-		var syntheticResources *synthetic.Resources
-		var zcIP string
-		for context, kubeClient := range kubeClients {
-			kubernetesResources, synth, err := connectivity.SetupClusterTODODelete(kubeClient, namespaces, pods, serverPort, protocol)
+		var kubernetes kube.IKubernetes
+		if args.Mock {
+			// give each mock a different pass rate, so that there's something to compare
+			kubernetes = kube.NewMockKubernetes(1.0 / float64(i+1))
+		} else {
+			kubeClient, err := kube.NewKubernetesForContext(context)
 			utils.DoOrDie(err)
-			// TODO this is a huge hack -- ips are going to be different from cluster to cluster, which means
-			//   that policies involving ips need to be different from cluster to cluster.  But here we're just
-			//   taking the first one and using it everywhere.
-			if syntheticResources == nil {
-				syntheticResources = synth
-
-				zcPod, err := kubeClient.GetPod("z", "c")
-				utils.DoOrDie(err)
-				if zcPod.Status.PodIP == "" {
-					panic(errors.Errorf("no ip found for pod z/c"))
-				}
-				zcIP = zcPod.Status.PodIP
-			}
-
-			kubeResources[context] = kubernetesResources
+			kubernetes = kubeClient
 		}
 
-		fragGenerator := generator.NewDefaultFragmentGenerator(true, namespaces, zcIP)
-		kubePolicySlices := packIntoSlices(fragGenerator.FragmentPolicies())
+		logrus.Infof("setting up resources in context %s", contextName)
+		resources, err := probe.NewDefaultResources(kubernetes, args.ServerNamespaces, args.ServerPods, args.ServerPorts, serverProtocols, externalIPs, args.PodCreationTimeoutSeconds, false, args.ImageRegistry)
+		utils.DoOrDie(err)
 
-		fmt.Printf("testing %d policies\n\n", len(kubePolicySlices))
-
-		contexts := args.Contexts
-		sort.Slice(contexts, func(i, j int) bool {
-			return contexts[i] < contexts[j]
-		})
-		tester := connectivity.NewMultipleContextTester()
-		printer := &connectivity.MultipleContextTestCasePrinter{
-			Noisy:    args.Noisy,
-			Contexts: contexts,
+		// TODO this is a hack -- ips are different from cluster to cluster, which means that policies
+		//   involving ips need to be different from cluster to cluster.  But here we're just
+		//   taking the first one and using it everywhere.
+		if zcIP == "" {
+			zcPod, err := resources.GetPod("z", "c")
+			utils.DoOrDie(err)
+			zcIP = zcPod.IP
 		}
 
-		for i, kubePolicy := range kubePolicySlices {
-			logrus.Infof("starting policy #%d", i)
-			testCase := &connectivity.MultipleContextTestCase{
-				KubePolicies:              kubePolicy,
-				NetpolCreationWaitSeconds: args.NetpolCreationWaitSeconds,
-				Port:                      probePort,
-				Protocol:                  protocol,
-				KubeClients:               kubeClients,
-				KubeResources:             kubeResources,
-				SyntheticResources:        syntheticResources,
-				NamespacesToClean:         namespaces,
-				Policy:                    matcher.BuildNetworkPolicies(kubePolicy),
+		kubeClients[contextName] = kubernetes
+		interpreters[contextName] = connectivity.NewInterpreter(kubernetes, resources, interpreterConfig)
+	}
+
+	// 2. choose the policies
+	var testCases []*generator.TestCase
+	if args.PolicyPath != "" {
+		policies, err := kube.ReadPoliciesFromPath(args.PolicyPath)
+		utils.DoOrDie(err)
+		var actions []*generator.Action
+		for _, kubePolicy := range policies.NetworkPolicies {
+			if len(kubePolicy.Spec.PolicyTypes) == 0 {
+				logrus.Fatalf("missing spec.policyTypes from network policy %s/%s", kubePolicy.Namespace, kubePolicy.Name)
 			}
-			result := tester.TestNetworkPolicy(testCase)
-			if len(result.Errors) > 0 {
-				panic(result.Errors[0])
+			actions = append(actions, generator.CreatePolicy(kubePolicy))
+		}
+		for _, anp := range policies.AdminNetworkPolicies {
+			actions = append(actions, generator.CreateANP(anp))
+		}
+		for _, banp := range policies.BaselineAdminNetworkPolicies {
+			actions = append(actions, generator.CreateBANP(banp))
+		}
+		testCases = append(testCases, generator.NewSingleStepTestCase("policies from "+args.PolicyPath, generator.NewStringSet(), generator.ProbeAllAvailable, actions...))
+	} else {
+		utils.DoOrDie(generator.ValidateTags(append(args.Include, args.Exclude...)))
+		testCases = generator.NewTestCaseGenerator(args.AllowDNS, zcIP, args.ServerNamespaces, args.Include, args.Exclude).GenerateTestCases()
+	}
+	fmt.Printf("testing %d cases\n\n", len(testCases))
+
+	if args.CleanupNamespaces {
+		defer func() {
+			for contextName, kubernetes := range kubeClients {
+				for _, ns := range args.ServerNamespaces {
+					logrus.Infof("cleaning up namespace %s in context %s", ns, contextName)
+					if err := kubernetes.DeleteNamespace(ns); err != nil {
+						logrus.Warnf("%+v", err)
+					}
+				}
 			}
+		}()
+	}
 
-			printer.PrintTestCaseResult(result)
-			logrus.Infof("finished policy #%d", i)
-		}
+	// 3. run each test case in all contexts in parallel, and compare
+	tester := connectivity.NewMultipleContextTester(interpreters)
+	printer := &connectivity.MultipleContextPrinter{
+		Noisy:          args.Noisy,
+		IgnoreLoopback: args.IgnoreLoopback,
+	}
+	for i, testCase := range testCases {
+		fmt.Printf("starting test case #%d\n", i+1)
 
-		printer.PrintFinish()
-	*/
-}
+		// a context which fails (e.g. an unreachable cluster) is reported with the test case, but doesn't stop
+		//   the other contexts from running the remaining test cases
+		result := tester.ExecuteTestCase(testCase)
 
-func packIntoSlices(netpols []*networkingv1.NetworkPolicy) [][]*networkingv1.NetworkPolicy {
-	var sos [][]*networkingv1.NetworkPolicy
-	for _, np := range netpols {
-		sos = append(sos, []*networkingv1.NetworkPolicy{np})
+		printer.PrintTestCaseResult(result)
+		fmt.Printf("finished test case #%d\n", i+1)
 	}
-	return sos
+
+	printer.PrintSummary()
 }
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mattfenwick/collections/pkg/file"
+	"github.com/mattfenwick/cyclonus/pkg/conformance"
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	conformancemanifests "sigs.k8s.io/network-policy-api/conformance"
+)
+
+type ConformanceArgs struct {
+	PolicyPath        string
+	BaseManifestsPath string
+	CheckPath         string
+	OutputPath        string
+}
+
+func SetupConformanceCommand() *cobra.Command {
+	args := &ConformanceArgs{}
+
+	command := &cobra.Command{
+		Use:   "conformance",
+		Short: "generate or check the expected connectivity of a conformance test, by simulating its policies without a cluster",
+		Args:  cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, as []string) {
+			RunConformanceCommand(args)
+		},
+	}
+
+	command.Flags().StringVar(&args.PolicyPath, "policy-path", "", "may be a file or a directory; reads the policies of a conformance test's manifests from the path")
+	utils.DoOrDie(command.MarkFlagRequired("policy-path"))
+	command.Flags().StringVar(&args.BaseManifestsPath, "base-manifests-path", "", "namespaces and pods which the conformance tests run against; if empty, uses the conformance suite's "+conformance.BaseManifestsPath)
+	command.Flags().StringVar(&args.CheckPath, "check", "", "connectivity matrix to check against the simulated connectivity; if empty, a matrix of the simulated connectivity is generated instead")
+	command.Flags().StringVarP(&args.OutputPath, "output", "o", "", "file to write the generated connectivity matrix to; if empty, prints it")
+
+	return command
+}
+
+func RunConformanceCommand(args *ConformanceArgs) {
+	var baseManifests []byte
+	var err error
+	if args.BaseManifestsPath == "" {
+		baseManifests, err = conformancemanifests.Manifests.ReadFile(conformance.BaseManifestsPath)
+	} else {
+		baseManifests, err = file.Read(args.BaseManifestsPath)
+	}
+	utils.DoOrDie(err)
+	pods, err := conformance.ReadBaseResources(baseManifests)
+	utils.DoOrDie(err)
+
+	policies, err := kube.ReadPoliciesFromPath(args.PolicyPath)
+	utils.DoOrDie(err)
+	policy, err := conformance.BuildPolicy(policies)
+	utils.DoOrDie(err)
+
+	if args.CheckPath == "" {
+		matrix := utils.YamlString(conformance.Simulate(policy, pods))
+		if args.OutputPath == "" {
+			fmt.Print(matrix)
+			return
+		}
+		utils.DoOrDie(errors.Wrapf(os.WriteFile(args.OutputPath, []byte(matrix), 0644), "unable to write connectivity matrix to %s", args.OutputPath))
+		return
+	}
+
+	matrixBytes, err := file.Read(args.CheckPath)
+	utils.DoOrDie(err)
+	matrix, err := conformance.ParseMatrix(matrixBytes)
+	utils.DoOrDie(errors.WithMessagef(err, "unable to parse connectivity matrix %s", args.CheckPath))
+	mismatches, err := conformance.Check(policy, pods, matrix)
+	utils.DoOrDie(err)
+	if len(mismatches) == 0 {
+		fmt.Printf("all %d cells of %s agree with the simulator\n", len(matrix.Connectivity), args.CheckPath)
+		return
+	}
+	fmt.Printf("%s\n", conformance.MismatchesTable(mismatches))
+	logrus.Fatalf("%d of %d cells of %s disagree with the simulator", len(mismatches), len(matrix.Connectivity), args.CheckPath)
+}
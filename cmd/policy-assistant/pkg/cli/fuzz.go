@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattfenwick/collections/pkg/json"
+	"github.com/mattfenwick/cyclonus/pkg/connectivity"
+	"github.com/mattfenwick/cyclonus/pkg/connectivity/probe"
+	"github.com/mattfenwick/cyclonus/pkg/generator"
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type FuzzArgs struct {
+	Seed                      int64
+	Cases                     int
+	Shrink                    bool
+	MaxNetworkPolicies        int
+	MaxANPs                   int
+	AdminPolicies             bool
+	LabelChanges              bool
+	Noisy                     bool
+	IgnoreLoopback            bool
+	PerturbationWaitSeconds   int
+	PodCreationTimeoutSeconds int
+	JobTimeoutSeconds         int
+	Retries                   int
+	Context                   string
+	ServerPorts               []int
+	ServerProtocols           []string
+	ServerNamespaces          []string
+	ServerPods                []string
+	CleanupNamespaces         bool
+	FailFast                  bool
+	Mock                      bool
+	JunitResultsFile          string
+	ImageRegistry             string
+}
+
+func SetupFuzzCommand() *cobra.Command {
+	args := &FuzzArgs{}
+
+	command := &cobra.Command{
+		Use:   "fuzz",
+		Short: "generate random network policies",
+		Long:  "generate random network policies and label changes from a seed, probe them against kubernetes, compare to expected results, and shrink failures to minimal test cases",
+		Args:  cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, as []string) {
+			RunFuzzCommand(args)
+		},
+	}
+
+	command.Flags().Int64Var(&args.Seed, "seed", 0, "seed of the first test case; test case i uses seed+i.  If 0, a seed is chosen from the current time")
+	command.Flags().IntVar(&args.Cases, "cases", 10, "number of test cases to generate")
+	command.Flags().BoolVar(&args.Shrink, "shrink", true, "if true, shrink each failing test case to a minimal test case which still fails")
+	command.Flags().IntVar(&args.MaxNetworkPolicies, "max-network-policies", 3, "maximum number of network policies per test case")
+	command.Flags().IntVar(&args.MaxANPs, "max-anps", 2, "maximum number of admin network policies per test case, if admin policies are enabled")
+	command.Flags().BoolVar(&args.AdminPolicies, "admin-policies", false, "if true, also generate admin and baseline admin network policies; requires the ANP and BANP CRDs, and a CNI which implements them")
+	command.Flags().BoolVar(&args.LabelChanges, "label-changes", true, "if true, change pod and namespace labels in a second step of each test case")
+
+	command.Flags().StringSliceVar(&args.ServerProtocols, "server-protocol", []string{"TCP", "UDP", "SCTP"}, "protocols to run server on")
+	command.Flags().IntSliceVar(&args.ServerPorts, "server-port", []int{80, 81}, "ports to run server on")
+	command.Flags().StringSliceVar(&args.ServerNamespaces, "namespace", []string{"x", "y", "z"}, "namespaces to create/use pods in")
+	command.Flags().StringSliceVar(&args.ServerPods, "pod", []string{"a", "b", "c"}, "pods to create in namespaces")
+
+	command.Flags().IntVar(&args.Retries, "retries", 1, "number of kube probe retries to allow, if probe fails")
+	command.Flags().BoolVar(&args.Noisy, "noisy", false, "if true, print all results")
+	command.Flags().BoolVar(&args.IgnoreLoopback, "ignore-loopback", false, "if true, ignore loopback for truthtable correctness verification")
+	command.Flags().IntVar(&args.PerturbationWaitSeconds, "perturbation-wait-seconds", 5, "number of seconds to wait after perturbing the cluster (i.e. create a network policy, modify a ns/pod label) before running probes, to give the CNI time to update the cluster state")
+	command.Flags().IntVar(&args.PodCreationTimeoutSeconds, "pod-creation-timeout-seconds", 60, "number of seconds to wait for pods to create, be running and have IP addresses")
+	command.Flags().IntVar(&args.JobTimeoutSeconds, "job-timeout-seconds", 10, "number of seconds to pass on to 'agnhost connect --timeout=%ds' flag")
+	command.Flags().StringVar(&args.Context, "context", "", "kubernetes context to use; if empty, uses default context")
+	command.Flags().BoolVar(&args.CleanupNamespaces, "cleanup-namespaces", false, "if true, clean up namespaces after completion")
+	command.Flags().BoolVar(&args.FailFast, "fail-fast", false, "if true, stop running tests after the first failure")
+	command.Flags().BoolVar(&args.Mock, "mock", false, "if true, use a mock kube runner (i.e. don't actually run tests against kubernetes; instead, product fake results")
+	command.Flags().StringVar(&args.JunitResultsFile, "junit-results-file", "", "output junit results to the specified file")
+	command.Flags().StringVar(&args.ImageRegistry, "image-registry", "registry.k8s.io", "Image registry for agnhost")
+
+	return command
+}
+
+func RunFuzzCommand(args *FuzzArgs) {
+	if args.MaxANPs < 0 || args.MaxANPs > generator.MaxFuzzANPs {
+		utils.DoOrDie(errors.Errorf("--max-anps must be between 0 and %d, got %d", generator.MaxFuzzANPs, args.MaxANPs))
+	}
+	if args.Seed == 0 {
+		args.Seed = time.Now().UnixNano()
+	}
+	fmt.Printf("args: \n%s\n", json.MustMarshalToString(args))
+
+	RunVersionCommand()
+
+	var kubernetes kube.IKubernetes
+	if args.Mock {
+		kubernetes = kube.NewMockKubernetes(1.0)
+	} else {
+		kubeClient, err := kube.NewKubernetesForContext(args.Context)
+		utils.DoOrDie(err)
+		kubernetes = kubeClient
+	}
+
+	serverProtocols := parseProtocols(args.ServerProtocols)
+
+	resources, err := probe.NewDefaultResources(kubernetes, args.ServerNamespaces, args.ServerPods, args.ServerPorts, serverProtocols, nil, args.PodCreationTimeoutSeconds, false, args.ImageRegistry)
+	utils.DoOrDie(err)
+
+	interpreter := connectivity.NewInterpreter(kubernetes, resources, &connectivity.InterpreterConfig{
+		ResetClusterBeforeTestCase:       true,
+		KubeProbeRetries:                 args.Retries,
+		PerturbationWaitSeconds:          args.PerturbationWaitSeconds,
+		VerifyClusterStateBeforeTestCase: true,
+		IgnoreLoopback:                   args.IgnoreLoopback,
+		JobTimeoutSeconds:                args.JobTimeoutSeconds,
+		FailFast:                         args.FailFast,
+	})
+	printer := &connectivity.Printer{
+		Noisy:            args.Noisy,
+		IgnoreLoopback:   args.IgnoreLoopback,
+		JunitResultsFile: args.JunitResultsFile,
+	}
+
+	fuzzer := generator.NewFuzzer(&generator.FuzzConfig{
+		Namespaces:         args.ServerNamespaces,
+		Pods:               args.ServerPods,
+		Ports:              args.ServerPorts,
+		Protocols:          serverProtocols,
+		MaxNetworkPolicies: args.MaxNetworkPolicies,
+		MaxANPs:            args.MaxANPs,
+		AdminPolicies:      args.AdminPolicies,
+		LabelChanges:       args.LabelChanges,
+	})
+
+	var failedSeeds []int64
+	for i := 0; i < args.Cases; i++ {
+		seed := args.Seed + int64(i)
+		fmt.Printf("starting test case #%d, seed %d\n", i+1, seed)
+
+		result := interpreter.ExecuteTestCase(fuzzer.TestCase(seed))
+		utils.DoOrDie(result.Err)
+
+		printer.PrintTestCaseResult(result)
+		fmt.Printf("finished test case #%d\n", i+1)
+
+		if result.Passed(args.IgnoreLoopback) {
+			continue
+		}
+		failedSeeds = append(failedSeeds, seed)
+		fmt.Printf("test case with seed %d failed; to reproduce, rerun with '--seed %d --cases 1'\n", seed, seed)
+
+		if args.Shrink {
+			shrinkTestCase(interpreter, result.TestCase, args)
+		}
+		if args.FailFast {
+			logrus.Warn("failing fast due to failure")
+			break
+		}
+	}
+
+	printer.PrintSummary()
+	fmt.Printf("failed seeds: %+v\n", failedSeeds)
+
+	if args.CleanupNamespaces {
+		for _, ns := range args.ServerNamespaces {
+			logrus.Infof("cleaning up namespace %s", ns)
+			err = kubernetes.DeleteNamespace(ns)
+			if err != nil {
+				logrus.Warnf("%+v", err)
+			}
+		}
+	}
+}
+
+// shrinkTestCase reruns smaller and smaller versions of a failing test case, and prints the smallest one which
+// still fails.  Its results aren't included in the summary.
+func shrinkTestCase(interpreter *connectivity.Interpreter, testCase *generator.TestCase, args *FuzzArgs) {
+	fmt.Printf("shrinking %s\n", testCase.Description)
+	attempts := 0
+	shrunk := generator.Shrink(testCase, func(candidate *generator.TestCase) bool {
+		attempts++
+		result := interpreter.ExecuteTestCase(candidate)
+		if result.Err != nil {
+			logrus.Debugf("unable to execute shrunk test case: %+v", result.Err)
+			return false
+		}
+		return !result.Passed(args.IgnoreLoopback)
+	})
+	fmt.Printf("shrunk %s after %d attempts\n", testCase.Description, attempts)
+
+	shrunkPrinter := &connectivity.Printer{Noisy: args.Noisy, IgnoreLoopback: args.IgnoreLoopback}
+	shrunkPrinter.PrintTestCaseResult(interpreter.ExecuteTestCase(generator.NewTestCase(testCase.Description+" (shrunk)", shrunk.Tags, shrunk.Steps...)))
+}
@@ -20,7 +20,9 @@ var (
 		generator.TagUpstreamE2E,
 		generator.TagExample,
 		generator.TagEndPort,
-		generator.TagNamespacesByDefaultLabel}
+		generator.TagNamespacesByDefaultLabel,
+		// admin network policies require the ANP and BANP CRDs, and a CNI which implements them
+		generator.TagAdminPolicy}
 )
 
 type GenerateArgs struct {
@@ -35,6 +37,7 @@ type GenerateArgs struct {
 	ServerProtocols           []string
 	ServerNamespaces          []string
 	ServerPods                []string
+	ExternalTargets           []string
 	CleanupNamespaces         bool
 	FailFast                  bool
 	Include                   []string
@@ -44,6 +47,8 @@ type GenerateArgs struct {
 	DryRun                    bool
 	JobTimeoutSeconds         int
 	JunitResultsFile          string
+	ResultsFile               string
+	HTMLReportFile            string
 	ImageRegistry             string
 	//BatchJobs                 bool
 }
@@ -65,6 +70,7 @@ func SetupGenerateCommand() *cobra.Command {
 	command.Flags().IntSliceVar(&args.ServerPorts, "server-port", []int{80, 81}, "ports to run server on")
 	command.Flags().StringSliceVar(&args.ServerNamespaces, "namespace", []string{"x", "y", "z"}, "namespaces to create/use pods in")
 	command.Flags().StringSliceVar(&args.ServerPods, "pod", []string{"a", "b", "c"}, "pods to create in namespaces")
+	command.Flags().StringSliceVar(&args.ExternalTargets, "external-target", []string{}, "external IPs to probe, as ip:port/protocol (e.g. 8.8.8.8:53/UDP), or as cidr:port/protocol to probe the first host address of a CIDR; these are probed from every pod, to test egress to destinations outside the cluster")
 
 	//command.Flags().BoolVar(&args.BatchJobs, "batch-jobs", false, "if true, run jobs in batches to avoid saturating the Kube APIServer with too many exec requests")
 	command.Flags().IntVar(&args.Retries, "retries", 1, "number of kube probe retries to allow, if probe fails")
@@ -86,6 +92,8 @@ func SetupGenerateCommand() *cobra.Command {
 	command.Flags().BoolVar(&args.DryRun, "dry-run", false, "if true, don't actually do anything: just print out what would be done")
 
 	command.Flags().StringVar(&args.JunitResultsFile, "junit-results-file", "", "output junit results to the specified file")
+	command.Flags().StringVar(&args.ResultsFile, "results-file", "", "save every test case, step, and simulated and kube probe result to the specified file as json, to be read by the 'report' command")
+	command.Flags().StringVar(&args.HTMLReportFile, "html-report-file", "", "output an html report, with the kube and simulated results of each test case, to the specified file")
 	command.Flags().StringVar(&args.ImageRegistry, "image-registry", "registry.k8s.io", "Image registry for agnhost")
 
 	return command
@@ -98,7 +106,8 @@ func RunGenerateCommand(args *GenerateArgs) {
 
 	utils.DoOrDie(generator.ValidateTags(append(args.Include, args.Exclude...)))
 
-	externalIPs := []string{} // "http://www.google.com"} // TODO make these be IPs?  or not?
+	externalIPs, err := probe.ParseExternalIPs(args.ExternalTargets)
+	utils.DoOrDie(err)
 
 	var kubernetes kube.IKubernetes
 	if args.Mock {
@@ -133,6 +142,7 @@ func RunGenerateCommand(args *GenerateArgs) {
 		Noisy:            args.Noisy,
 		IgnoreLoopback:   args.IgnoreLoopback,
 		JunitResultsFile: args.JunitResultsFile,
+		HTMLReportFile:   args.HTMLReportFile,
 	}
 
 	zcPod, err := resources.GetPod("z", "c")
@@ -181,6 +191,10 @@ func RunGenerateCommand(args *GenerateArgs) {
 
 	printer.PrintSummary()
 
+	if args.ResultsFile != "" {
+		utils.DoOrDie(connectivity.WriteResultsFile(args.ResultsFile, printer.Results))
+	}
+
 	if args.CleanupNamespaces {
 		for _, ns := range args.ServerNamespaces {
 			logrus.Infof("cleaning up namespace %s", ns)
@@ -24,6 +24,7 @@ type ProbeArgs struct {
 	PolicyPath                string
 	ProbeMode                 string
 	JobTimeoutSeconds         int
+	HTMLReportFile            string
 
 	// what to probe on
 	ProbeAllAvailable bool
@@ -35,6 +36,7 @@ type ProbeArgs struct {
 	ServerPorts      []int
 	ServerNamespaces []string
 	ServerPods       []string
+	ExternalTargets  []string
 	ImageRegistry    string
 }
 
@@ -52,6 +54,7 @@ func SetupProbeCommand() *cobra.Command {
 
 	command.Flags().StringSliceVarP(&args.ServerNamespaces, "server-namespace", "n", []string{"x", "y", "z"}, "namespaces to create/use pods in")
 	command.Flags().StringSliceVar(&args.ServerPods, "server-pod", []string{"a", "b", "c"}, "pods to create in namespaces")
+	command.Flags().StringSliceVar(&args.ExternalTargets, "external-target", []string{}, "external IPs to probe, as ip:port/protocol (e.g. 8.8.8.8:53/UDP), or as cidr:port/protocol to probe the first host address of a CIDR; these are probed from every pod, to test egress to destinations outside the cluster")
 	command.Flags().IntSliceVar(&args.ServerPorts, "server-port", []int{80, 81}, "ports to run server on")
 	command.Flags().StringSliceVar(&args.ServerProtocols, "server-protocol", []string{"TCP", "UDP", "SCTP"}, "protocols to run server on")
 
@@ -68,13 +71,13 @@ func SetupProbeCommand() *cobra.Command {
 	command.Flags().IntVar(&args.PerturbationWaitSeconds, "perturbation-wait-seconds", 5, "number of seconds to wait after perturbing the cluster (i.e. create a network policy, modify a ns/pod label) before running probes, to give the CNI time to update the cluster state")
 	command.Flags().IntVar(&args.PodCreationTimeoutSeconds, "pod-creation-timeout-seconds", 60, "number of seconds to wait for pods to create, be running and have IP addresses")
 	command.Flags().StringVar(&args.PolicyPath, "policy-path", "", "path to yaml network policy to create in kube; if empty, will not create any policies")
+	command.Flags().StringVar(&args.HTMLReportFile, "html-report-file", "", "output an html report, with the kube and simulated results of each probe, to the specified file")
 	command.Flags().StringVar(&args.ImageRegistry, "image-registry", "registry.k8s.io", "Image registry for agnhost")
 
 	return command
 }
 
 func RunProbeCommand(args *ProbeArgs) {
-	externalIPs := []string{"http://www.google.com"} // TODO make these be IPs?  or not?
 	if len(args.ServerNamespaces) == 0 || len(args.ServerPods) == 0 {
 		panic(errors.Errorf("found 0 namespaces or pods, must have at least 1 of each"))
 	}
@@ -84,6 +87,8 @@ func RunProbeCommand(args *ProbeArgs) {
 
 	protocols := parseProtocols(args.Protocols)
 	serverProtocols := parseProtocols(args.ServerProtocols)
+	externalIPs, err := probe.ParseExternalIPs(args.ExternalTargets)
+	utils.DoOrDie(err)
 
 	resources, err := probe.NewDefaultResources(kubernetes, args.ServerNamespaces, args.ServerPods, args.ServerPorts, serverProtocols, externalIPs, args.PodCreationTimeoutSeconds, false, args.ImageRegistry)
 	utils.DoOrDie(err)
@@ -128,6 +133,8 @@ func RunProbeCommand(args *ProbeArgs) {
 			}
 		}
 	}
+
+	utils.DoOrDie(connectivity.WriteHTMLReport(args.HTMLReportFile, printer.Results, args.IgnoreLoopback))
 }
 
 func parseProtocols(strs []string) []v1.Protocol {
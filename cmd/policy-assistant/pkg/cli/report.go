@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattfenwick/cyclonus/pkg/connectivity"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	ReportOutputText     = "text"
+	ReportOutputSummary  = "summary"
+	ReportOutputMarkdown = "markdown"
+)
+
+var ReportOutputs = []string{ReportOutputText, ReportOutputSummary, ReportOutputMarkdown}
+
+type ReportArgs struct {
+	ResultsFile      string
+	CompareTo        string
+	Output           string
+	Noisy            bool
+	IgnoreLoopback   bool
+	JunitResultsFile string
+	HTMLReportFile   string
+}
+
+func SetupReportCommand() *cobra.Command {
+	args := &ReportArgs{}
+
+	command := &cobra.Command{
+		Use:   "report",
+		Short: "report on saved test runs",
+		Long:  "re-print the results of a test run saved by 'generate --results-file', or compare it cell-by-cell to another saved test run",
+		Args:  cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, as []string) {
+			RunReportCommand(args)
+		},
+	}
+
+	command.Flags().StringVar(&args.ResultsFile, "results-file", "", "results file saved by 'generate --results-file'")
+	utils.DoOrDie(command.MarkFlagRequired("results-file"))
+	command.Flags().StringVar(&args.CompareTo, "compare-to", "", "if set, compare the kube results of --results-file to those of this earlier results file, instead of printing them")
+	command.Flags().StringVarP(&args.Output, "output", "o", ReportOutputText, "output format; one of "+strings.Join(ReportOutputs, ", ")+".  'text' prints each test case and the summary, as 'generate' does")
+
+	command.Flags().BoolVar(&args.Noisy, "noisy", false, "if true, print all results")
+	command.Flags().BoolVar(&args.IgnoreLoopback, "ignore-loopback", false, "if true, ignore loopback for truthtable correctness verification")
+	command.Flags().StringVar(&args.JunitResultsFile, "junit-results-file", "", "output junit results to the specified file")
+	command.Flags().StringVar(&args.HTMLReportFile, "html-report-file", "", "output an html report, with the kube and simulated results of each test case, to the specified file")
+
+	return command
+}
+
+func RunReportCommand(args *ReportArgs) {
+	results, err := connectivity.ReadResultsFile(args.ResultsFile)
+	utils.DoOrDie(err)
+
+	if args.CompareTo != "" {
+		utils.DoOrDie(compareResultsFiles(args, results))
+		return
+	}
+
+	printer := &connectivity.Printer{
+		Noisy:            args.Noisy,
+		IgnoreLoopback:   args.IgnoreLoopback,
+		JunitResultsFile: args.JunitResultsFile,
+		HTMLReportFile:   args.HTMLReportFile,
+	}
+	switch args.Output {
+	case ReportOutputText:
+		for _, result := range results {
+			printer.PrintTestCaseResult(result)
+		}
+		printer.PrintSummary()
+	case ReportOutputSummary:
+		printer.Results = results
+		printer.PrintSummary()
+	case ReportOutputMarkdown:
+		printer.Results = results
+		printer.PrintMarkdownSummary()
+		utils.DoOrDie(connectivity.PrintJUnitResults(args.JunitResultsFile, results, args.IgnoreLoopback))
+		utils.DoOrDie(connectivity.WriteHTMLReport(args.HTMLReportFile, results, args.IgnoreLoopback))
+	default:
+		utils.DoOrDie(errors.Errorf("invalid output format %s; expected one of %+v", args.Output, ReportOutputs))
+	}
+}
+
+func compareResultsFiles(args *ReportArgs, results []*connectivity.Result) error {
+	if args.CompareTo == args.ResultsFile {
+		return errors.Errorf("unable to compare results file %s to itself", args.ResultsFile)
+	}
+	earlierResults, err := connectivity.ReadResultsFile(args.CompareTo)
+	if err != nil {
+		return err
+	}
+
+	diff := connectivity.DiffResults(args.CompareTo, earlierResults, args.ResultsFile, results)
+	printer := &connectivity.MultipleContextPrinter{Noisy: args.Noisy, IgnoreLoopback: args.IgnoreLoopback}
+	for _, pair := range diff.Pairs {
+		printer.PrintTestCaseResult(pair)
+	}
+	printer.PrintSummary()
+
+	if len(diff.Unpaired) > 0 {
+		fmt.Printf("%d test cases not compared, as they weren't run in both runs, or probed different pods:\n", len(diff.Unpaired))
+		for _, testCase := range diff.Unpaired {
+			fmt.Printf("- %s\n", testCase.Description)
+		}
+	}
+	return nil
+}
@@ -34,9 +34,13 @@ func SetupRootCommand() *cobra.Command {
 	command.PersistentFlags().StringVarP(&flags.Verbosity, "verbosity", "v", "info", "log level; one of [info, debug, trace, warn, error, fatal, panic]")
 
 	command.AddCommand(SetupAnalyzeCommand())
-	//command.AddCommand(SetupCompareCommand())
+	command.AddCommand(SetupCompareCommand())
+	command.AddCommand(SetupConformanceCommand())
+	command.AddCommand(SetupFuzzCommand())
 	command.AddCommand(SetupGenerateCommand())
 	command.AddCommand(SetupProbeCommand())
+	command.AddCommand(SetupReportCommand())
+	command.AddCommand(SetupValidateCommand())
 	command.AddCommand(SetupVersionCommand())
 
 	return command
@@ -0,0 +1,14 @@
+package cli
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCli(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunAnalyzeTests()
+	RunSpecs(t, "cli suite")
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	"github.com/mattfenwick/cyclonus/pkg/validation"
+	"github.com/olekukonko/tablewriter"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+type ValidateArgs struct {
+	PolicyPath string
+}
+
+func SetupValidateCommand() *cobra.Command {
+	args := &ValidateArgs{}
+
+	command := &cobra.Command{
+		Use:   "validate",
+		Short: "validate admin network policies against the CRD rules, without a cluster",
+		Args:  cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, as []string) {
+			RunValidateCommand(args)
+		},
+	}
+
+	command.Flags().StringVar(&args.PolicyPath, "policy-path", "", "may be a file or a directory; reads AdminNetworkPolicies and BaselineAdminNetworkPolicies from the path")
+	utils.DoOrDie(command.MarkFlagRequired("policy-path"))
+
+	return command
+}
+
+func RunValidateCommand(args *ValidateArgs) {
+	policies, err := kube.ReadPoliciesFromPath(args.PolicyPath)
+	utils.DoOrDie(err)
+
+	tableString := &strings.Builder{}
+	table := tablewriter.NewWriter(tableString)
+	table.SetAutoWrapText(false)
+	table.SetRowLine(true)
+	table.SetAutoMergeCells(true)
+	table.SetHeader([]string{"Policy", "Field", "Error"})
+
+	invalid := 0
+	addErrors := func(policy string, errs field.ErrorList) {
+		if len(errs) > 0 {
+			invalid++
+		}
+		for _, e := range errs {
+			table.Append([]string{policy, e.Field, e.ErrorBody()})
+		}
+	}
+	for _, anp := range policies.AdminNetworkPolicies {
+		addErrors("[ANP] "+anp.Name, validation.ValidateAdminNetworkPolicy(anp))
+	}
+	for _, banp := range policies.BaselineAdminNetworkPolicies {
+		addErrors("[BANP] "+banp.Name, validation.ValidateBaselineAdminNetworkPolicy(banp))
+	}
+
+	total := len(policies.AdminNetworkPolicies) + len(policies.BaselineAdminNetworkPolicies)
+	if invalid == 0 {
+		fmt.Printf("all %d admin network policies are valid\n", total)
+		return
+	}
+
+	table.Render()
+	fmt.Printf("%s\n", tableString.String())
+	logrus.Fatalf("%d of %d admin network policies are invalid", invalid, total)
+}
@@ -0,0 +1,91 @@
+package conformance
+
+import (
+	"io/fs"
+	"strings"
+
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	conformancemanifests "sigs.k8s.io/network-policy-api/conformance"
+)
+
+func RunConformanceTests() {
+	Describe("Conformance expectations", func() {
+		baseManifests, err := conformancemanifests.Manifests.ReadFile(BaseManifestsPath)
+		Expect(err).To(Succeed())
+		pods, err := ReadBaseResources(baseManifests)
+		Expect(err).To(Succeed())
+
+		It("Should read the pods of the base manifests, and the ports they serve", func() {
+			Expect(pods).To(HaveLen(8))
+			harry := pods[0]
+			Expect(harry.Key()).To(Equal("network-policy-conformance-gryffindor/harry-potter-0"))
+			Expect(harry.Labels).To(Equal(map[string]string{"conformance-house": "gryffindor"}))
+			Expect(harry.NamespaceLabels).To(Equal(map[string]string{
+				"conformance-house":           "gryffindor",
+				"kubernetes.io/metadata.name": "network-policy-conformance-gryffindor",
+			}))
+			Expect(harry.Ports).To(Equal([]*ServedPort{
+				{Port: 80, Name: "web", Protocol: v1.ProtocolTCP},
+				{Port: 8080, Protocol: v1.ProtocolTCP},
+				{Port: 5353, Protocol: v1.ProtocolUDP},
+				{Port: 53, Name: "dns", Protocol: v1.ProtocolUDP},
+				{Port: 9003, Protocol: v1.ProtocolSCTP},
+				{Port: 9005, Protocol: v1.ProtocolSCTP},
+			}))
+		})
+
+		It("Should agree with the connectivity matrices of the conformance tests", func() {
+			matrices := 0
+			err := fs.WalkDir(conformancemanifests.Manifests, "base", func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() || !strings.HasSuffix(path, MatrixSuffix) {
+					return err
+				}
+				matrices++
+				matrixBytes, err := conformancemanifests.Manifests.ReadFile(path)
+				Expect(err).To(Succeed())
+				matrix, err := ParseMatrix(matrixBytes)
+				Expect(err).To(Succeed())
+
+				manifests, err := conformancemanifests.Manifests.ReadFile(ManifestsPathForMatrix(path))
+				Expect(err).To(Succeed())
+				policies, err := kube.ReadPoliciesFromBytes(manifests)
+				Expect(err).To(Succeed())
+				policy, err := BuildPolicy(policies)
+				Expect(err).To(Succeed())
+
+				mismatches, err := Check(policy, pods, matrix)
+				Expect(err).To(Succeed())
+				Expect(mismatches).To(BeEmpty(), "%s disagrees with the simulator:\n%s", path, MismatchesTable(mismatches))
+				return nil
+			})
+			Expect(err).To(Succeed())
+			Expect(matrices).To(BeNumerically(">", 0))
+		})
+
+		It("Should simulate every pair of pods on every served port", func() {
+			manifests, err := conformancemanifests.Manifests.ReadFile("base/admin_network_policy/core-ingress-tcp-rules.yaml")
+			Expect(err).To(Succeed())
+			policies, err := kube.ReadPoliciesFromBytes(manifests)
+			Expect(err).To(Succeed())
+			policy, err := BuildPolicy(policies)
+			Expect(err).To(Succeed())
+
+			matrix := Simulate(policy, pods)
+			Expect(matrix.Connectivity).To(HaveLen(8 * 7 * 6))
+			Expect(matrix.Connectivity[0]).To(Equal(&Cell{
+				Name:     "network-policy-conformance-gryffindor/harry-potter-0 to network-policy-conformance-gryffindor/harry-potter-1 on 80/TCP",
+				Client:   "network-policy-conformance-gryffindor/harry-potter-0",
+				Server:   "network-policy-conformance-gryffindor/harry-potter-1",
+				Protocol: v1.ProtocolTCP,
+				Port:     80,
+				Verdict:  VerdictAllow,
+			}))
+			mismatches, err := Check(policy, pods, matrix)
+			Expect(err).To(Succeed())
+			Expect(mismatches).To(BeEmpty())
+		})
+	})
+}
@@ -0,0 +1,168 @@
+package conformance
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	"github.com/mattfenwick/cyclonus/pkg/matcher"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
+)
+
+const (
+	// BaseManifestsPath is the location of the base namespaces and pods in the conformance manifests
+	BaseManifestsPath = "base/manifests.yaml"
+	// MatrixSuffix ends the file name of a connectivity matrix, which sits next to the manifests it's for
+	MatrixSuffix = "-connectivity.yaml"
+)
+
+// ManifestsPathForMatrix finds the manifests which a connectivity matrix is for,
+// e.g. "core-ingress-tcp-rules.yaml" for "core-ingress-tcp-rules-connectivity.yaml"
+func ManifestsPathForMatrix(matrixPath string) string {
+	return strings.TrimSuffix(matrixPath, MatrixSuffix) + ".yaml"
+}
+
+type Verdict string
+
+const (
+	VerdictAllow Verdict = "Allow"
+	VerdictDeny  Verdict = "Deny"
+)
+
+// Matrix is the expected connectivity between pods, in the format of the conformance suite's ConnectivityMatrix
+type Matrix struct {
+	Connectivity []*Cell `json:"connectivity"`
+}
+
+// Cell is the expected verdict for connections from a client pod to a server pod, where pods are given as "namespace/name"
+type Cell struct {
+	Name     string      `json:"name"`
+	Client   string      `json:"client"`
+	Server   string      `json:"server"`
+	Protocol v1.Protocol `json:"protocol"`
+	Port     int32       `json:"port"`
+	Verdict  Verdict     `json:"verdict"`
+}
+
+func ParseMatrix(bs []byte) (*Matrix, error) {
+	return utils.ParseYamlStrict[Matrix](bs)
+}
+
+// BuildPolicy builds the policies of a conformance test's manifests.  Invalid policies are an error, since
+// conformance tests expect all their policies to be applied.
+func BuildPolicy(policies *kube.Policies) (*matcher.Policy, error) {
+	var banp *v1alpha1.BaselineAdminNetworkPolicy
+	if banps := policies.BaselineAdminNetworkPolicies; len(banps) > 1 {
+		return nil, errors.Errorf("found %d baseline admin network policies; at most 1 is allowed", len(banps))
+	} else if len(banps) == 1 {
+		banp = banps[0]
+	}
+	return matcher.BuildV1AndV2NetPols(false, policies.NetworkPolicies, policies.AdminNetworkPolicies, banp)
+}
+
+// Simulate finds the verdict for connections from every pod to every port of every other pod
+func Simulate(policy *matcher.Policy, pods []*Pod) *Matrix {
+	matrix := &Matrix{}
+	for _, client := range pods {
+		for _, server := range pods {
+			if client == server {
+				continue
+			}
+			for _, port := range server.Ports {
+				matrix.Connectivity = append(matrix.Connectivity, &Cell{
+					Name:     fmt.Sprintf("%s to %s on %s", client.Key(), server.Key(), port),
+					Client:   client.Key(),
+					Server:   server.Key(),
+					Protocol: port.Protocol,
+					Port:     int32(port.Port),
+					Verdict:  simulate(policy, client, server, port).Verdict,
+				})
+			}
+		}
+	}
+	return matrix
+}
+
+// Mismatch is a cell whose verdict disagrees with the simulator's, along with the effects which decided the
+// simulated verdict
+type Mismatch struct {
+	Cell          *Cell
+	Simulated     Verdict
+	IngressEffect string
+	EgressEffect  string
+}
+
+// Check finds the cells of matrix whose verdict disagrees with the simulator's
+func Check(policy *matcher.Policy, pods []*Pod, matrix *Matrix) ([]*Mismatch, error) {
+	podsByKey := map[string]*Pod{}
+	for _, pod := range pods {
+		podsByKey[pod.Key()] = pod
+	}
+	var mismatches []*Mismatch
+	for _, cell := range matrix.Connectivity {
+		client, ok := podsByKey[cell.Client]
+		if !ok {
+			return nil, errors.Errorf("cell '%s': client pod %s not found", cell.Name, cell.Client)
+		}
+		server, ok := podsByKey[cell.Server]
+		if !ok {
+			return nil, errors.Errorf("cell '%s': server pod %s not found", cell.Name, cell.Server)
+		}
+		port := &ServedPort{Port: int(cell.Port), Protocol: cell.Protocol}
+		for _, p := range server.Ports {
+			if p.Port == port.Port && p.Protocol == port.Protocol {
+				port = p
+			}
+		}
+		result := simulate(policy, client, server, port)
+		if result.Verdict != cell.Verdict {
+			mismatches = append(mismatches, &Mismatch{Cell: cell, Simulated: result.Verdict, IngressEffect: result.IngressEffect, EgressEffect: result.EgressEffect})
+		}
+	}
+	return mismatches, nil
+}
+
+// simulated is the verdict for a connection, and the effects which decided it
+type simulated struct {
+	Verdict       Verdict
+	IngressEffect string
+	EgressEffect  string
+}
+
+func simulate(policy *matcher.Policy, client *Pod, server *Pod, port *ServedPort) *simulated {
+	allowed := policy.IsTrafficAllowed(&matcher.Traffic{
+		Source:           client.TrafficPeer(),
+		Destination:      server.TrafficPeer(),
+		ResolvedPort:     port.Port,
+		ResolvedPortName: port.Name,
+		Protocol:         port.Protocol,
+	})
+	result := &simulated{Verdict: VerdictDeny, IngressEffect: "no policies", EgressEffect: "no policies"}
+	if allowed.IsAllowed() {
+		result.Verdict = VerdictAllow
+	}
+	if winner := allowed.Ingress.Winner(); winner != nil {
+		result.IngressEffect = winner.String()
+	}
+	if winner := allowed.Egress.Winner(); winner != nil {
+		result.EgressEffect = winner.String()
+	}
+	return result
+}
+
+func MismatchesTable(mismatches []*Mismatch) string {
+	tableString := &strings.Builder{}
+	table := tablewriter.NewWriter(tableString)
+	table.SetAutoWrapText(false)
+	table.SetRowLine(true)
+	table.SetHeader([]string{"Cell", "Expected", "Simulated", "Ingress decided by", "Egress decided by"})
+	for _, m := range mismatches {
+		table.Append([]string{m.Cell.Name, string(m.Cell.Verdict), string(m.Simulated), m.IngressEffect, m.EgressEffect})
+	}
+	table.Render()
+	return tableString.String()
+}
@@ -0,0 +1,176 @@
+package conformance
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mattfenwick/collections/pkg/slice"
+	"github.com/mattfenwick/cyclonus/pkg/matcher"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Pod is a pod of the conformance base resources, along with the ports its containers serve
+type Pod struct {
+	Namespace       string
+	Name            string
+	Labels          map[string]string
+	NamespaceLabels map[string]string
+	IP              string
+	Ports           []*ServedPort
+}
+
+func (p *Pod) Key() string {
+	return fmt.Sprintf("%s/%s", p.Namespace, p.Name)
+}
+
+func (p *Pod) TrafficPeer() *matcher.TrafficPeer {
+	return &matcher.TrafficPeer{
+		Internal: &matcher.InternalPeer{Namespace: p.Namespace, NamespaceLabels: p.NamespaceLabels, PodLabels: p.Labels},
+		IP:       p.IP,
+	}
+}
+
+// ServedPort is a port which a container listens on.  Name is set if the container declares the port.
+type ServedPort struct {
+	Port     int
+	Name     string
+	Protocol v1.Protocol
+}
+
+func (s *ServedPort) String() string {
+	return fmt.Sprintf("%d/%s", s.Port, s.Protocol)
+}
+
+var (
+	// agnhost serve-hostname listens on --port, using TCP unless --udp or --sctp is given
+	servePortRegex = regexp.MustCompile(`--port[= ](\d+)`)
+	// agnhost netexec listens on --udp-port
+	udpPortRegex = regexp.MustCompile(`--udp-port[= ](\d+)`)
+	// agnhost porter listens on the port of each SERVE_<PROTOCOL>_PORT_<port> environment variable
+	porterEnvRegex = regexp.MustCompile(`^SERVE_(TCP|UDP|SCTP)_PORT_(\d+)$`)
+)
+
+// ReadBaseResources reads the pods of the StatefulSets from yaml documents, such as the conformance base manifests,
+// along with the labels of their Namespaces; other kinds are ignored.
+// Since there's no cluster, pods are given made-up IPs, and host-networked pods are left out, as the
+// simulator doesn't model them.
+func ReadBaseResources(bs []byte) ([]*Pod, error) {
+	namespaceLabels := map[string]map[string]string{}
+	var statefulSets []*appsv1.StatefulSet
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(bs)))
+	for {
+		document, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "unable to split yaml documents")
+		}
+		typeMeta, err := utils.ParseYaml[metav1.TypeMeta](document)
+		if err != nil {
+			return nil, err
+		}
+		switch typeMeta.Kind {
+		case "Namespace":
+			ns, err := utils.ParseYamlStrict[v1.Namespace](document)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "unable to parse Namespace")
+			}
+			labels := map[string]string{}
+			for k, v := range ns.Labels {
+				labels[k] = v
+			}
+			// set by the API server
+			labels[v1.LabelMetadataName] = ns.Name
+			namespaceLabels[ns.Name] = labels
+		case "StatefulSet":
+			statefulSet, err := utils.ParseYamlStrict[appsv1.StatefulSet](document)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "unable to parse StatefulSet")
+			}
+			statefulSets = append(statefulSets, statefulSet)
+		}
+	}
+
+	var pods []*Pod
+	for _, statefulSet := range statefulSets {
+		if statefulSet.Spec.Template.Spec.HostNetwork {
+			continue
+		}
+		labels, ok := namespaceLabels[statefulSet.Namespace]
+		if !ok {
+			return nil, errors.Errorf("namespace %s of StatefulSet %s not found", statefulSet.Namespace, statefulSet.Name)
+		}
+		replicas := 1
+		if statefulSet.Spec.Replicas != nil {
+			replicas = int(*statefulSet.Spec.Replicas)
+		}
+		var ports []*ServedPort
+		for _, container := range statefulSet.Spec.Template.Spec.Containers {
+			ports = append(ports, servedPorts(container)...)
+		}
+		for i := 0; i < replicas; i++ {
+			pods = append(pods, &Pod{
+				Namespace:       statefulSet.Namespace,
+				Name:            fmt.Sprintf("%s-%d", statefulSet.Name, i),
+				Labels:          statefulSet.Spec.Template.Labels,
+				NamespaceLabels: labels,
+				IP:              fmt.Sprintf("10.0.%d.%d", len(pods)/250, len(pods)%250+1),
+				Ports:           ports,
+			})
+		}
+	}
+	return slice.SortOn((*Pod).Key, pods), nil
+}
+
+// servedPorts finds the ports a container declares, and the ports its agnhost command listens on
+func servedPorts(container v1.Container) []*ServedPort {
+	var ports []*ServedPort
+	add := func(port int, name string, protocol v1.Protocol) {
+		if protocol == "" {
+			protocol = v1.ProtocolTCP
+		}
+		for _, p := range ports {
+			if p.Port == port && p.Protocol == protocol {
+				return
+			}
+		}
+		ports = append(ports, &ServedPort{Port: port, Name: name, Protocol: protocol})
+	}
+
+	for _, port := range container.Ports {
+		add(int(port.ContainerPort), port.Name, port.Protocol)
+	}
+
+	command := strings.Join(append(append([]string{}, container.Command...), container.Args...), " ")
+	if match := servePortRegex.FindStringSubmatch(command); match != nil {
+		protocol := v1.ProtocolTCP
+		if strings.Contains(command, "--udp") {
+			protocol = v1.ProtocolUDP
+		} else if strings.Contains(command, "--sctp") {
+			protocol = v1.ProtocolSCTP
+		}
+		port, _ := strconv.Atoi(match[1])
+		add(port, "", protocol)
+	}
+	if match := udpPortRegex.FindStringSubmatch(command); match != nil {
+		port, _ := strconv.Atoi(match[1])
+		add(port, "", v1.ProtocolUDP)
+	}
+	for _, env := range container.Env {
+		if match := porterEnvRegex.FindStringSubmatch(env.Name); match != nil {
+			port, _ := strconv.Atoi(match[2])
+			add(port, "", v1.Protocol(match[1]))
+		}
+	}
+	return ports
+}
@@ -0,0 +1,14 @@
+package conformance
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestModel(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunConformanceTests()
+	RunSpecs(t, "conformance expectations suite")
+}
@@ -1,8 +1,12 @@
 package connectivity
 
 import (
+	"fmt"
+
+	"github.com/mattfenwick/collections/pkg/slice"
 	"github.com/mattfenwick/cyclonus/pkg/connectivity/probe"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
 	v1 "k8s.io/api/core/v1"
 )
 
@@ -39,8 +43,8 @@ type ComparisonTable struct {
 	Wrapped *probe.TruthTable
 }
 
-func NewComparisonTable(items []string) *ComparisonTable {
-	return &ComparisonTable{Wrapped: probe.NewTruthTableFromItems(items, nil)}
+func NewComparisonTable(froms []string, tos []string) *ComparisonTable {
+	return &ComparisonTable{Wrapped: probe.NewTruthTable(froms, tos, nil)}
 }
 
 func NewComparisonTableFrom(kubeProbe *probe.Table, simulatedProbe *probe.Table) *ComparisonTable {
@@ -58,7 +62,7 @@ func NewComparisonTableFrom(kubeProbe *probe.Table, simulatedProbe *probe.Table)
 		}
 	}
 
-	table := NewComparisonTable(kubeProbe.Wrapped.Froms)
+	table := NewComparisonTable(kubeProbe.Wrapped.Froms, kubeProbe.Wrapped.Tos)
 	for _, key := range kubeProbe.Wrapped.Keys() {
 		table.Set(key.From, key.To, &Item{Kube: kubeProbe.Get(key.From, key.To), Simulated: simulatedProbe.Get(key.From, key.To)})
 	}
@@ -133,6 +137,30 @@ func (c *ComparisonTable) RenderSuccessTable() string {
 	})
 }
 
+// Mismatches describes each port/protocol on which kube and the simulation disagree, along with the effects
+// which decided the simulated result, e.g.
+// "y/a -> x/a TCP/80: kube ., simulated X (ingress: [NPv1] x/deny-all: None; egress: no policies)"
+func (c *ComparisonTable) Mismatches(ignoreLoopback bool) []string {
+	var lines []string
+	for _, key := range c.Wrapped.Keys() {
+		if ignoreLoopback && key.From == key.To {
+			continue
+		}
+		item := c.Get(key.From, key.To)
+		for _, portProtocol := range slice.Sort(maps.Keys(item.Kube.JobResults)) {
+			kubeResult := item.Kube.JobResults[portProtocol]
+			simulated, ok := item.Simulated.JobResults[portProtocol]
+			if !ok {
+				lines = append(lines, fmt.Sprintf("%s -> %s %s: kube %s, not simulated", key.From, key.To, portProtocol, kubeResult.Combined.ShortString()))
+			} else if simulated.Combined != kubeResult.Combined {
+				lines = append(lines, fmt.Sprintf("%s -> %s %s: kube %s, simulated %s (%s)",
+					key.From, key.To, portProtocol, kubeResult.Combined.ShortString(), simulated.Combined.ShortString(), simulated.Explanation()))
+			}
+		}
+	}
+	return lines
+}
+
 type Comparison string
 
 const (
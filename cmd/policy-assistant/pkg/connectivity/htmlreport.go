@@ -0,0 +1,276 @@
+package connectivity
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"strings"
+
+	"github.com/mattfenwick/collections/pkg/slice"
+	"github.com/mattfenwick/cyclonus/pkg/connectivity/probe"
+	"github.com/mattfenwick/cyclonus/pkg/generator"
+	"github.com/mattfenwick/cyclonus/pkg/matcher"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
+)
+
+// WriteHTMLReport writes a self-contained HTML page, for sharing results with people who don't use the CLI:
+// pass rates by feature and tag, and for each test case, the policies and the kube vs. simulated results of each step.
+func WriteHTMLReport(filename string, results []*Result, ignoreLoopback bool) error {
+	if filename == "" {
+		return nil
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create file %s for html report", filename)
+	}
+	defer f.Close()
+
+	return errors.Wrapf(htmlReportTemplate.Execute(f, newHTMLReport(results, ignoreLoopback)), "unable to write html report")
+}
+
+type htmlReport struct {
+	Passed      int
+	Failed      int
+	FeatureRows []*htmlFeatureRow
+	TagRows     []*htmlFeatureRow
+	Tests       []*htmlTest
+}
+
+type htmlFeatureRow struct {
+	*markdownRow
+	Percent float64
+}
+
+type htmlTest struct {
+	Number      int
+	Description string
+	Tags        []string
+	Passed      bool
+	Err         string
+	Steps       []*htmlStep
+}
+
+type htmlStep struct {
+	Number   int
+	Probe    string
+	Policies []string
+	Wrong    int
+	Right    int
+	Ignored  int
+	Tos      []string
+	Rows     []*htmlRow
+}
+
+type htmlRow struct {
+	From  string
+	Cells []*htmlCell
+}
+
+type htmlCell struct {
+	Class   string
+	Results []*htmlCellResult
+}
+
+// htmlCellResult is the result on a single port/protocol.  Only mismatches are explained.
+type htmlCellResult struct {
+	Key         string
+	Kube        string
+	Simulated   string
+	Class       string
+	Explanation string
+}
+
+const (
+	htmlClassAllowed  = "allowed"
+	htmlClassBlocked  = "blocked"
+	htmlClassMismatch = "mismatch"
+	htmlClassIgnored  = "ignored"
+)
+
+func newHTMLReport(results []*Result, ignoreLoopback bool) *htmlReport {
+	summary := NewSummaryTableFromResults(ignoreLoopback, results)
+	report := &htmlReport{
+		FeatureRows: newHTMLFeatureRows(summary.FeaturePrimaryCounts, summary.FeatureCounts),
+		TagRows:     newHTMLFeatureRows(summary.TagPrimaryCounts, summary.TagCounts),
+	}
+	for i, result := range results {
+		test := &htmlTest{
+			Number:      i + 1,
+			Description: result.TestCase.Description,
+			Tags:        slice.Sort(result.TestCase.Tags.Keys()),
+			Passed:      result.Err == nil && result.Passed(ignoreLoopback),
+		}
+		if result.Err != nil {
+			test.Err = fmt.Sprintf("%+v", result.Err)
+		}
+		for j, stepResult := range result.Steps {
+			test.Steps = append(test.Steps, newHTMLStep(j+1, result.TestCase.Steps[j], stepResult, ignoreLoopback))
+		}
+		if test.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		report.Tests = append(report.Tests, test)
+	}
+	return report
+}
+
+func newHTMLFeatureRows(primaryCounts map[string]map[bool]int, counts map[string]map[string]map[bool]int) []*htmlFeatureRow {
+	return slice.Map(func(row *markdownRow) *htmlFeatureRow {
+		return &htmlFeatureRow{markdownRow: row, Percent: percentage(row.Pass, row.Pass+row.Fail)}
+	}, featureRows(primaryCounts, counts))
+}
+
+func newHTMLStep(number int, step *generator.TestStep, stepResult *StepResult, ignoreLoopback bool) *htmlStep {
+	htmlStep := &htmlStep{Number: number, Probe: "all available ports/protocols"}
+	if step.Probe.PortProtocol != nil {
+		htmlStep.Probe = fmt.Sprintf("port %s, protocol %s", step.Probe.PortProtocol.Port.String(), step.Probe.PortProtocol.Protocol)
+	}
+	for _, policy := range stepResult.KubePolicies {
+		htmlStep.Policies = append(htmlStep.Policies, PrintNetworkPolicy(policy))
+	}
+	for _, anp := range stepResult.ANPs {
+		htmlStep.Policies = append(htmlStep.Policies, utils.YamlString(anp))
+	}
+	if stepResult.BANP != nil {
+		htmlStep.Policies = append(htmlStep.Policies, utils.YamlString(stepResult.BANP))
+	}
+	if len(stepResult.KubeProbes) == 0 {
+		return htmlStep
+	}
+
+	counts := stepResult.LastComparison().ValueCounts(ignoreLoopback)
+	htmlStep.Wrong, htmlStep.Right, htmlStep.Ignored = counts[DifferentComparison], counts[SameComparison], counts[IgnoredComparison]
+
+	kubeProbe, simulated := stepResult.LastKubeProbe(), stepResult.SimulatedProbe
+	htmlStep.Tos = kubeProbe.Wrapped.Tos
+	for _, from := range kubeProbe.Wrapped.Froms {
+		row := &htmlRow{From: from}
+		for _, to := range kubeProbe.Wrapped.Tos {
+			row.Cells = append(row.Cells, newHTMLCell(stepResult.Policy, kubeProbe.Get(from, to), simulated.Get(from, to), ignoreLoopback && from == to))
+		}
+		htmlStep.Rows = append(htmlStep.Rows, row)
+	}
+	return htmlStep
+}
+
+// newHTMLCell's class is the most interesting of its results' classes: mismatch, then blocked, then allowed
+func newHTMLCell(policy *matcher.Policy, kube *probe.Item, simulated *probe.Item, isIgnored bool) *htmlCell {
+	cell := &htmlCell{Class: htmlClassAllowed}
+	if isIgnored {
+		cell.Class = htmlClassIgnored
+	}
+	for _, key := range slice.Sort(maps.Keys(kube.JobResults)) {
+		kubeResult := kube.JobResults[key]
+		result := &htmlCellResult{Key: key, Kube: kubeResult.Combined.ShortString(), Simulated: "-", Class: htmlClassAllowed}
+		simulatedResult, ok := simulated.JobResults[key]
+		if ok {
+			result.Simulated = simulatedResult.Combined.ShortString()
+		}
+		switch {
+		case isIgnored:
+			result.Class = htmlClassIgnored
+		case !ok || simulatedResult.Combined != kubeResult.Combined:
+			result.Class = htmlClassMismatch
+			if ok {
+				result.Explanation = explainJobResult(policy, simulatedResult)
+			}
+		case kubeResult.Combined != probe.ConnectivityAllowed:
+			result.Class = htmlClassBlocked
+		}
+		if result.Class == htmlClassMismatch || (result.Class == htmlClassBlocked && cell.Class == htmlClassAllowed) {
+			cell.Class = result.Class
+		}
+		cell.Results = append(cell.Results, result)
+	}
+	return cell
+}
+
+// explainJobResult explains a simulated result: the flow through ANPs, network policies and the BANP,
+// and the effect of each rule which applied to the traffic, in each direction
+func explainJobResult(policy *matcher.Policy, simulated *probe.JobResult) string {
+	// jobs read from a results file only identify their cell, without the labels needed to rerun them,
+	// so only the effects which decided them are known
+	if policy == nil || simulated.Job.FromNamespace == "" {
+		return simulated.Explanation()
+	}
+	allowed := policy.IsTrafficAllowed(simulated.Job.Traffic())
+	var lines []string
+	for _, direction := range []struct {
+		Name   string
+		Result matcher.DirectionResult
+	}{{"ingress", allowed.Ingress}, {"egress", allowed.Egress}} {
+		flow := direction.Result.Flow()
+		if flow == "" {
+			flow = "no policies"
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s (allowed: %t)", direction.Name, flow, direction.Result.IsAllowed()))
+		for _, effect := range direction.Result {
+			lines = append(lines, "  - "+effect.String())
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>cyclonus results</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin: 0.5em 0; }
+th, td { border: 1px solid #bbb; padding: 0.2em 0.5em; vertical-align: top; }
+td.allowed { background: #d4edda; }
+td.blocked { background: #e2e3e5; }
+td.mismatch { background: #f8d7da; font-weight: bold; }
+td.ignored { background: #fff; color: #999; }
+tr.primary { font-weight: bold; }
+.passed { color: #155724; }
+.failed { color: #721c24; }
+details.test { border: 1px solid #ddd; margin: 0.5em 0; padding: 0.5em; }
+pre { background: #f6f8fa; padding: 0.5em; font-weight: normal; }
+</style>
+</head>
+<body>
+<h1>cyclonus results</h1>
+<p><span class="passed">{{.Passed}} passed</span>, <span class="failed">{{.Failed}} failed</span></p>
+<p>Cells: <code>kube (simulated)</code> on each port/protocol, where <code>.</code> is allowed and <code>X</code> is blocked.
+Green cells are allowed, grey cells are blocked, and red cells don't match the simulated results.</p>
+
+{{define "features"}}
+<table>
+<tr><th>Name</th><th>Passed</th><th>Failed</th><th>Passed %</th></tr>
+{{range .}}<tr{{if .IsPrimary}} class="primary"{{end}}><td>{{.GetName}}</td><td>{{.Pass}}</td><td>{{.Fail}}</td><td>{{printf "%.0f" .Percent}}</td></tr>
+{{end}}</table>
+{{end}}
+<h2>Features</h2>
+{{template "features" .FeatureRows}}
+<h2>Tags</h2>
+{{template "features" .TagRows}}
+
+<h2>Test cases</h2>
+{{range .Tests}}
+<details class="test"{{if not .Passed}} open{{end}}>
+<summary>{{.Number}}: {{.Description}} &mdash; {{if .Passed}}<span class="passed">passed</span>{{else}}<span class="failed">failed</span>{{end}}</summary>
+<p>Tags: {{range $i, $tag := .Tags}}{{if $i}}, {{end}}{{$tag}}{{end}}</p>
+{{if .Err}}<pre>{{.Err}}</pre>{{end}}
+{{range .Steps}}
+<h3>Step {{.Number}}, on {{.Probe}}: {{.Wrong}} wrong, {{.Ignored}} ignored, {{.Right}} correct</h3>
+<details><summary>{{len .Policies}} policies</summary>
+{{range .Policies}}<pre>{{.}}</pre>{{end}}
+</details>
+<table>
+<tr><th></th>{{range .Tos}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr><th>{{.From}}</th>{{range .Cells}}<td class="{{.Class}}">{{range .Results}}<div>{{.Key}}: {{.Kube}} ({{.Simulated}})
+{{if .Explanation}}<details><summary>explain</summary><pre>{{.Explanation}}</pre></details>{{end}}</div>{{end}}</td>{{end}}</tr>
+{{end}}</table>
+{{end}}
+</details>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,62 @@
+package connectivity
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/mattfenwick/cyclonus/pkg/connectivity/probe"
+	"github.com/mattfenwick/cyclonus/pkg/generator"
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func RunHTMLReportTests() {
+	Describe("HTML reports", func() {
+		var results []*Result
+		BeforeEach(func() {
+			kubernetes := kube.NewMockKubernetes(1)
+			resources, err := probe.NewDefaultResources(kubernetes, []string{"x", "y"}, []string{"a", "b"}, []int{80}, []v1.Protocol{v1.ProtocolTCP}, nil, 5, false, "registry.k8s.io")
+			utils.DoOrDie(err)
+			interpreter := NewInterpreter(kubernetes, resources, &InterpreterConfig{ResetClusterBeforeTestCase: true, VerifyClusterStateBeforeTestCase: true})
+
+			// SCTP isn't served, so this denies all ingress to x/a
+			sctp := v1.ProtocolSCTP
+			allowSCTPToXA := (&generator.Netpol{
+				Name:    "allow-sctp",
+				Target:  generator.NewNetpolTarget("x", map[string]string{"pod": "a"}, nil),
+				Ingress: &generator.NetpolPeers{Rules: []*generator.Rule{{Ports: []networkingv1.NetworkPolicyPort{{Protocol: &sctp}}}}},
+			}).NetworkPolicy()
+			results = []*Result{
+				interpreter.ExecuteTestCase(generator.NewSingleStepTestCase("allow sctp to x/a", generator.NewStringSet(generator.TagDenyAll), generator.ProbeAllAvailable,
+					generator.CreatePolicy(allowSCTPToXA))),
+				interpreter.ExecuteTestCase(generator.NewSingleStepTestCase("no policies", generator.NewStringSet(generator.TagAllowAll), generator.ProbeAllAvailable)),
+			}
+		})
+
+		It("Should show each test case, its policies, and explain mismatched cells", func() {
+			report := newHTMLReport(results, false)
+			Expect(report.Passed).To(Equal(1))
+			Expect(report.Failed).To(Equal(1))
+			Expect(report.Tests[0].Steps[0].Policies).To(HaveLen(1))
+
+			// the mock kube allows everything, which the policy doesn't
+			cell := report.Tests[0].Steps[0].Rows[2].Cells[0]
+			Expect(report.Tests[0].Steps[0].Rows[2].From).To(Equal("y/a"))
+			Expect(cell.Class).To(Equal(htmlClassMismatch))
+			Expect(cell.Results[0].Explanation).To(ContainSubstring("ingress: [NPv1] Dropped (allowed: false)"))
+			Expect(report.Tests[1].Steps[0].Rows[0].Cells[0].Class).To(Equal(htmlClassAllowed))
+
+			path := filepath.Join(GinkgoT().TempDir(), "report.html")
+			Expect(WriteHTMLReport(path, results, false)).To(Succeed())
+			bytes, err := os.ReadFile(path)
+			Expect(err).To(Succeed())
+			Expect(string(bytes)).To(ContainSubstring("allow sctp to x/a"))
+			Expect(string(bytes)).To(ContainSubstring(`class="mismatch"`))
+			Expect(string(bytes)).To(ContainSubstring("<summary>explain</summary>"))
+		})
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
 )
 
 const (
@@ -103,6 +104,18 @@ func (t *Interpreter) ExecuteTestCase(testCase *generator.TestCase) *Result {
 				err = testCaseState.UpdatePolicy(action.UpdatePolicy.Policy)
 			} else if action.DeletePolicy != nil {
 				err = testCaseState.DeletePolicy(action.DeletePolicy.Namespace, action.DeletePolicy.Name)
+			} else if action.CreateANP != nil {
+				err = testCaseState.CreateANP(action.CreateANP.Policy)
+			} else if action.UpdateANP != nil {
+				err = testCaseState.UpdateANP(action.UpdateANP.Policy)
+			} else if action.DeleteANP != nil {
+				err = testCaseState.DeleteANP(action.DeleteANP.Name)
+			} else if action.CreateBANP != nil {
+				err = testCaseState.CreateBANP(action.CreateBANP.Policy)
+			} else if action.UpdateBANP != nil {
+				err = testCaseState.UpdateBANP(action.UpdateBANP.Policy)
+			} else if action.DeleteBANP != nil {
+				err = testCaseState.DeleteBANP(action.DeleteBANP.Name)
 			} else if action.CreateNamespace != nil {
 				err = testCaseState.CreateNamespace(action.CreateNamespace.Namespace, action.CreateNamespace.Labels)
 			} else if action.SetNamespaceLabels != nil {
@@ -142,7 +155,10 @@ func (t *Interpreter) ExecuteTestCase(testCase *generator.TestCase) *Result {
 }
 
 func (t *Interpreter) runProbe(testCaseState *TestCaseState, probeConfig *generator.ProbeConfig) *StepResult {
-	parsedPolicy := matcher.BuildNetworkPolicies(true, testCaseState.Policies)
+	parsedPolicy, err := matcher.BuildV1AndV2NetPols(true, testCaseState.Policies, testCaseState.ANPs, testCaseState.BANP)
+	if err != nil {
+		logrus.Errorf("unable to build some policies; simulating the valid policies only:\n%+v", err)
+	}
 
 	logrus.Infof("running probe %+v", probeConfig)
 	logrus.Debugf("with resources:\n%s", testCaseState.Resources.RenderTable())
@@ -153,6 +169,8 @@ func (t *Interpreter) runProbe(testCaseState *TestCaseState, probeConfig *genera
 		simRunner.RunProbeForConfig(probeConfig, testCaseState.Resources),
 		parsedPolicy,
 		append([]*networkingv1.NetworkPolicy{}, testCaseState.Policies...)) // this looks weird, but just making a new copy to avoid accidentally mutating it elsewhere
+	stepResult.ANPs = append([]*v1alpha1.AdminNetworkPolicy{}, testCaseState.ANPs...)
+	stepResult.BANP = testCaseState.BANP
 
 	for i := 0; i <= t.Config.KubeProbeRetries; i++ {
 		logrus.Infof("running kube probe on try %d", i+1)
@@ -1,248 +1,214 @@
 package connectivity
 
-/*
-TODO
 import (
 	"fmt"
-	connectivitykube "github.com/mattfenwick/cyclonus/pkg/connectivity/kube"
-	"github.com/mattfenwick/cyclonus/pkg/connectivity/synthetic"
-	"github.com/mattfenwick/cyclonus/pkg/explainer"
-	"github.com/mattfenwick/cyclonus/pkg/kube"
-	"github.com/mattfenwick/cyclonus/pkg/matcher"
-	"github.com/mattfenwick/cyclonus/pkg/utils"
-	"github.com/olekukonko/tablewriter"
-	"github.com/sirupsen/logrus"
-	v1 "k8s.io/api/core/v1"
-	networkingv1 "k8s.io/api/networking/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
-	"os"
-	"sigs.k8s.io/yaml"
 	"strings"
-	"time"
+
+	"github.com/mattfenwick/collections/pkg/slice"
+	"github.com/mattfenwick/cyclonus/pkg/connectivity/probe"
+	"github.com/mattfenwick/cyclonus/pkg/generator"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
 )
 
-type MultipleContextTestCase struct {
-	KubePolicies              []*networkingv1.NetworkPolicy
-	NetpolCreationWaitSeconds int
-	Port                      intstr.IntOrString
-	Protocol                  v1.Protocol
-	KubeClients               map[string]*kube.Kubernetes
-	KubeResources             map[string]*connectivitykube.Resources
-	SyntheticResources        *synthetic.Resources
-	NamespacesToClean         []string
-	Policy                    *matcher.Policy
+// MultipleContextTester runs the same test cases against multiple kube contexts -- for example,
+// clusters running different CNIs -- in parallel, so that their connectivity can be compared.
+type MultipleContextTester struct {
+	// Contexts are sorted by name
+	Contexts     []string
+	Interpreters map[string]*Interpreter
 }
 
-type MultipleContextTestCaseResult struct {
-	TestCase        *MultipleContextTestCase
-	SyntheticResult *synthetic.Result
-	KubeResults     map[string]*connectivitykube.Results
-	Errors          []error
+func NewMultipleContextTester(interpreters map[string]*Interpreter) *MultipleContextTester {
+	return &MultipleContextTester{
+		Contexts:     slice.Sort(maps.Keys(interpreters)),
+		Interpreters: interpreters,
+	}
 }
 
-type MultipleContextTester struct{}
-
-func NewMultipleContextTester() *MultipleContextTester {
-	return &MultipleContextTester{}
+type MultipleContextResult struct {
+	TestCase *generator.TestCase
+	Contexts []string
+	Results  map[string]*Result
 }
 
-type workerResult struct {
-	ContextName string
-	Results     *connectivitykube.Results
-	Err         error
+// Err returns the first error from any context
+func (m *MultipleContextResult) Err() error {
+	for _, context := range m.Contexts {
+		if err := m.Results[context].Err; err != nil {
+			return errors.WithMessagef(err, "context %s", context)
+		}
+	}
+	return nil
 }
 
-func (t *MultipleContextTester) TestNetworkPolicy(testCase *MultipleContextTestCase) *MultipleContextTestCaseResult {
-	result := &MultipleContextTestCaseResult{
-		TestCase: testCase,
-		SyntheticResult: synthetic.RunSyntheticProbe(&synthetic.Request{
-			Protocol:  testCase.Protocol,
-			Port:      testCase.Port,
-			Resources: testCase.SyntheticResources,
-			Policies:  testCase.Policy,
-		}),
-		KubeResults: map[string]*connectivitykube.Results{},
+// SucceededContexts returns the contexts which ran the test case without an error; only these are compared
+func (m *MultipleContextResult) SucceededContexts() []string {
+	var contexts []string
+	for _, context := range m.Contexts {
+		if m.Results[context].Err == nil {
+			contexts = append(contexts, context)
+		}
 	}
+	return contexts
+}
 
-	resultChan := make(chan *workerResult, len(testCase.KubeClients))
-	for contextName := range testCase.KubeClients {
+type contextResult struct {
+	Context string
+	Result  *Result
+}
+
+func (t *MultipleContextTester) ExecuteTestCase(testCase *generator.TestCase) *MultipleContextResult {
+	resultChan := make(chan *contextResult, len(t.Contexts))
+	for _, context := range t.Contexts {
 		go func(context string) {
-			results, err := t.testWorker(testCase, context)
-			resultChan <- &workerResult{
-				ContextName: context,
-				Results:     results,
-				Err:         err,
-			}
-		}(contextName)
+			resultChan <- &contextResult{Context: context, Result: t.Interpreters[context].ExecuteTestCase(testCase)}
+		}(context)
 	}
 
-	for i := 0; i < len(testCase.KubeClients); i++ {
-		results := <-resultChan
-		if results.Err == nil {
-			result.KubeResults[results.ContextName] = results.Results
-		} else {
-			result.Errors = append(result.Errors, results.Err)
-		}
+	result := &MultipleContextResult{TestCase: testCase, Contexts: t.Contexts, Results: map[string]*Result{}}
+	for range t.Contexts {
+		r := <-resultChan
+		result.Results[r.Context] = r.Result
 	}
-
 	return result
 }
 
-func (t *MultipleContextTester) testWorker(testCase *MultipleContextTestCase, contextName string) (*connectivitykube.Results, error) {
-	kubeClient := testCase.KubeClients[contextName]
-	utils.DoOrDie(kubeClient.DeleteAllNetworkPoliciesInNamespaces(testCase.NamespacesToClean))
-
-	for _, kubePolicy := range testCase.KubePolicies {
-		_, err := kubeClient.CreateNetworkPolicy(kubePolicy)
-		if err != nil {
-			return nil, err
+// StepCount is the number of steps run in every context without an error; contexts may stop early when
+// failing fast
+func (m *MultipleContextResult) StepCount() int {
+	count := 0
+	for i, context := range m.SucceededContexts() {
+		if steps := len(m.Results[context].Steps); i == 0 || steps < count {
+			count = steps
 		}
 	}
-
-	logrus.Infof("waiting %d seconds for network policy to create and become active", testCase.NetpolCreationWaitSeconds)
-	time.Sleep(time.Duration(testCase.NetpolCreationWaitSeconds) * time.Second)
-
-	logrus.Infof("running probe on context %s", contextName)
-
-	return connectivitykube.RunKubeProbe(kubeClient, &connectivitykube.Request{
-		Resources:       testCase.KubeResources[contextName],
-		Port:            testCase.Port,
-		Protocol:        testCase.Protocol,
-		NumberOfWorkers: 5,
-	}), nil
+	return count
 }
 
-type MultipleContextTestCasePrinter struct {
-	Noisy            bool
-	IgnoreLoopback   bool
-	Contexts         []string
-	DifferenceCounts [][]int
+// Disagreements returns, for each cell of a step, the port/protocols on which the contexts' final kube probes
+// don't all agree.  Contexts which failed with an error are left out.
+func (m *MultipleContextResult) Disagreements(stepIndex int, ignoreLoopback bool) *probe.TruthTable {
+	contexts := m.SucceededContexts()
+	first := m.Results[contexts[0]].Steps[stepIndex].LastKubeProbe()
+	return probe.NewTruthTable(first.Wrapped.Froms, first.Wrapped.Tos, func(fr, to string) interface{} {
+		if ignoreLoopback && fr == to {
+			return []string{}
+		}
+		var keys []string
+		for _, key := range slice.Sort(maps.Keys(first.Get(fr, to).JobResults)) {
+			expected := first.Get(fr, to).JobResults[key].Combined
+			for _, context := range contexts[1:] {
+				jr, ok := m.Results[context].Steps[stepIndex].LastKubeProbe().Get(fr, to).JobResults[key]
+				if !ok || jr.Combined != expected {
+					keys = append(keys, key)
+					break
+				}
+			}
+		}
+		return keys
+	})
 }
 
-func (t *MultipleContextTestCasePrinter) PrintTestCaseResult(result *MultipleContextTestCaseResult) {
-	policy := result.TestCase.Policy
-
-	policyBytes, err := yaml.Marshal(result.TestCase.KubePolicies)
-	utils.DoOrDie(err)
-	fmt.Printf("Network policy:\n\n%s\n", policyBytes)
-
-	fmt.Printf("Policy explained:\n%s\n", explainer.TableExplainer(policy))
+func (m *MultipleContextResult) DisagreementCount(stepIndex int, ignoreLoopback bool) int {
+	disagreements := m.Disagreements(stepIndex, ignoreLoopback)
+	count := 0
+	for _, key := range disagreements.Keys() {
+		count += len(disagreements.GetKey(key).([]string))
+	}
+	return count
+}
 
-	//fmt.Println("expected results:")
-	//fmt.Println("Expected ingress:")
-	//result.SyntheticResult.Ingress.Table().Render()
-	//
-	//fmt.Println("Expected egress:")
-	//result.SyntheticResult.Egress.Table().Render()
+// RenderDisagreements renders a table with a cell for each from/to pair of a step: '.' if all contexts agree,
+// otherwise each port/protocol with a disagreement, followed by the result from each context
+// and (in parentheses) the simulated result.
+func (m *MultipleContextResult) RenderDisagreements(stepIndex int, ignoreLoopback bool) string {
+	disagreements := m.Disagreements(stepIndex, ignoreLoopback)
+	contexts := m.SucceededContexts()
+	simulated := m.Results[contexts[0]].Steps[stepIndex].SimulatedProbe
+	schema := fmt.Sprintf("%s\n(simulated)", strings.Join(contexts, " "))
+	return disagreements.Table(schema, true, func(fr, to string, i interface{}) string {
+		keys := i.([]string)
+		if len(keys) == 0 {
+			return "."
+		}
+		var lines []string
+		for _, key := range keys {
+			var results []string
+			for _, context := range contexts {
+				jr, ok := m.Results[context].Steps[stepIndex].LastKubeProbe().Get(fr, to).JobResults[key]
+				if ok {
+					results = append(results, jr.Combined.ShortString())
+				} else {
+					results = append(results, "-")
+				}
+			}
+			expected := "-"
+			if jr, ok := simulated.Get(fr, to).JobResults[key]; ok {
+				expected = jr.Combined.ShortString()
+			}
+			lines = append(lines, fmt.Sprintf("%s: %s (%s)", key, strings.Join(results, " "), expected))
+		}
+		return strings.Join(lines, "\n")
+	})
+}
 
-	fmt.Println("Expected connectivity:")
-	fmt.Println(result.SyntheticResult.Combined.Table())
+type MultipleContextPrinter struct {
+	Noisy          bool
+	IgnoreLoopback bool
+	Results        []*MultipleContextResult
+}
 
-	foundDiscrepancy := false
+func (p *MultipleContextPrinter) PrintTestCaseResult(result *MultipleContextResult) {
+	p.Results = append(p.Results, result)
 
-	var falseCounts []int
-	for _, contextName := range t.Contexts {
-		kubeResults := result.KubeResults[contextName]
-		comparison := result.SyntheticResult.Combined.Compare(kubeResults.TruthTable())
-		counts := comparison.ValueCounts(t.IgnoreLoopback)
-		if counts.False > 0 {
-			fmt.Printf("results for context %s:\n", contextName)
-			fmt.Printf("found %d true, %d false, %d no value, %d ignored from %d total\n", counts.True, counts.False, counts.NoValue, counts.Ignored, counts.Total)
-			foundDiscrepancy = true
+	fmt.Printf("test case: %s\n", result.TestCase.Description)
+	for _, context := range result.Contexts {
+		if err := result.Results[context].Err; err != nil {
+			fmt.Printf("context %s failed to execute test case: %+v\n", context, err)
 		}
-		falseCounts = append(falseCounts, counts.False)
 	}
-	t.DifferenceCounts = append(t.DifferenceCounts, falseCounts)
-
-	if foundDiscrepancy {
-		for _, contextName := range t.Contexts {
-			fmt.Printf("results for context %s:\n", contextName)
-			kubeResults := result.KubeResults[contextName]
-			fmt.Println(kubeResults.TruthTable().Table())
+	for i := 0; i < result.StepCount(); i++ {
+		count := result.DisagreementCount(i, p.IgnoreLoopback)
+		if count == 0 && !p.Noisy {
+			fmt.Printf("step %d: all contexts agree\n", i+1)
+			continue
 		}
-	} else {
-		fmt.Println("no differences found for policy")
+		fmt.Printf("step %d: %d disagreements between contexts:\n%s\n", i+1, count, result.RenderDisagreements(i, p.IgnoreLoopback))
 	}
+	fmt.Println()
 }
 
-func (t *MultipleContextTestCasePrinter) PrintFinish() {
-	csv := []string{strings.Join(t.Contexts, ",")}
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader(t.Contexts)
-	for _, falseCounts := range t.DifferenceCounts {
-		var row []string
-		for _, falses := range falseCounts {
-			row = append(row, fmt.Sprintf("%d", falses))
+// PrintSummary prints, for each test case, the number of disagreements between contexts, and whether each
+// context matched the simulated results or failed with an error.
+func (p *MultipleContextPrinter) PrintSummary() {
+	if len(p.Results) == 0 {
+		return
+	}
+	contexts := p.Results[0].Contexts
+
+	tableString := &strings.Builder{}
+	table := tablewriter.NewWriter(tableString)
+	table.SetRowLine(true)
+	table.SetHeader(append([]string{"Test", "Disagreements"}, contexts...))
+	for i, result := range p.Results {
+		count := 0
+		for step := 0; step < result.StepCount(); step++ {
+			count += result.DisagreementCount(step, p.IgnoreLoopback)
+		}
+		row := []string{fmt.Sprintf("%d: %s", i+1, result.TestCase.Description), fmt.Sprintf("%d", count)}
+		for _, context := range contexts {
+			if result.Results[context].Err != nil {
+				row = append(row, "error")
+			} else if result.Results[context].Passed(p.IgnoreLoopback) {
+				row = append(row, passSymbol)
+			} else {
+				row = append(row, failSymbol)
+			}
 		}
-		csv = append(csv, strings.Join(row, ","))
 		table.Append(row)
 	}
 	table.Render()
-
-	fmt.Printf("\n\n%s\n\n", strings.Join(csv, "\n"))
+	fmt.Printf("Summary (%s: context matches simulated results):\n%s\n", passSymbol, tableString.String())
 }
-*/
-
-//func SetupClusterTODODelete(kubernetes *kube.Kubernetes, namespaces []string, pods []string, port int, protocol v1.Protocol) (*connectivitykube.Resources, *synthetic.Resources, error) {
-//	kubeResources := connectivitykube.NewDefaultResources(namespaces, pods, []int{port}, []v1.Protocol{protocol})
-//
-//	err := kubeResources.CreateResourcesInKube(kubernetes)
-//	if err != nil {
-//		return nil, nil, err
-//	}
-//
-//	err = waitForPodsReadyTODODelete(kubernetes, namespaces, pods, 60)
-//	if err != nil {
-//		return nil, nil, err
-//	}
-//
-//	podList, err := kubernetes.GetPodsInNamespaces(namespaces)
-//	if err != nil {
-//		return nil, nil, err
-//	}
-//	var syntheticPods []*synthetic.Pod
-//	for _, pod := range podList {
-//		ip := pod.Status.PodIP
-//		if ip == "" {
-//			return nil, nil, errors.Errorf("no ip found for pod %s/%s", pod.Namespace, pod.Name)
-//		}
-//		syntheticPods = append(syntheticPods, &synthetic.Pod{
-//			Namespace: pod.Namespace,
-//			Name:      pod.Name,
-//			Labels:    pod.Labels,
-//			IP:        ip,
-//		})
-//		log.Infof("ip for pod %s/%s: %s", pod.Namespace, pod.Name, ip)
-//	}
-//
-//	resources, err := synthetic.NewResources(kubeResources.Namespaces, syntheticPods)
-//	if err != nil {
-//		return nil, nil, err
-//	}
-//
-//	return kubeResources, resources, nil
-//}
-
-//func waitForPodsReadyTODODelete(kubernetes *kube.Kubernetes, namespaces []string, pods []string, timeoutSeconds int) error {
-//	sleep := 5
-//	for i := 0; i < timeoutSeconds; i += sleep {
-//		podList, err := kubernetes.GetPodsInNamespaces(namespaces)
-//		if err != nil {
-//			return err
-//		}
-//
-//		ready := 0
-//		for _, pod := range podList {
-//			if pod.Status.Phase == "Running" && pod.Status.PodIP != "" {
-//				ready++
-//			}
-//		}
-//		if ready == len(namespaces)*len(pods) {
-//			return nil
-//		}
-//
-//		log.Infof("waiting for pods to be running and have IP addresses")
-//		time.Sleep(time.Duration(sleep) * time.Second)
-//	}
-//	return errors.Errorf("pods not ready")
-//}
@@ -0,0 +1,57 @@
+package connectivity
+
+import (
+	"github.com/mattfenwick/cyclonus/pkg/connectivity/probe"
+	"github.com/mattfenwick/cyclonus/pkg/generator"
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+)
+
+func RunMultipleContextTesterTests() {
+	Describe("MultipleContextTester", func() {
+		newInterpreter := func(passRate float64) *Interpreter {
+			kubernetes := kube.NewMockKubernetes(passRate)
+			resources, err := probe.NewDefaultResources(kubernetes, []string{"x", "y"}, []string{"a"}, []int{80}, []v1.Protocol{v1.ProtocolTCP}, nil, 5, false, "registry.k8s.io")
+			utils.DoOrDie(err)
+			return NewInterpreter(kubernetes, resources, &InterpreterConfig{ResetClusterBeforeTestCase: true, VerifyClusterStateBeforeTestCase: true})
+		}
+		testCase := generator.NewSingleStepTestCase("no policies", generator.NewStringSet(), generator.ProbeAllAvailable)
+
+		It("Should find no disagreements between identical contexts", func() {
+			tester := NewMultipleContextTester(map[string]*Interpreter{"b": newInterpreter(1), "a": newInterpreter(1)})
+			Expect(tester.Contexts).To(Equal([]string{"a", "b"}))
+
+			result := tester.ExecuteTestCase(testCase)
+			Expect(result.Err()).To(BeNil())
+			Expect(result.StepCount()).To(Equal(1))
+			Expect(result.DisagreementCount(0, false)).To(Equal(0))
+		})
+
+		It("Should find a disagreement in every cell between contexts which allow and block everything", func() {
+			tester := NewMultipleContextTester(map[string]*Interpreter{"allow": newInterpreter(1), "block": newInterpreter(0)})
+
+			result := tester.ExecuteTestCase(testCase)
+			Expect(result.Err()).To(BeNil())
+			Expect(result.DisagreementCount(0, false)).To(Equal(4))
+			Expect(result.DisagreementCount(0, true)).To(Equal(2))
+			Expect(result.Disagreements(0, false).Get("x/a", "y/a")).To(Equal([]string{"TCP/80"}))
+			Expect(result.RenderDisagreements(0, false)).To(ContainSubstring("TCP/80: . X (.)"))
+		})
+
+		It("Should leave out a context which fails, and compare the others", func() {
+			broken := newInterpreter(0)
+			utils.DoOrDie(broken.kubernetes.DeleteNamespace("y"))
+			tester := NewMultipleContextTester(map[string]*Interpreter{"allow": newInterpreter(1), "block": newInterpreter(0), "broken": broken})
+
+			result := tester.ExecuteTestCase(testCase)
+			Expect(result.Err()).To(HaveOccurred())
+			Expect(result.SucceededContexts()).To(Equal([]string{"allow", "block"}))
+			Expect(result.StepCount()).To(Equal(1))
+			Expect(result.DisagreementCount(0, false)).To(Equal(4))
+			Expect(result.RenderDisagreements(0, false)).To(ContainSubstring("TCP/80: . X (.)"))
+		})
+	})
+}
@@ -23,6 +23,7 @@ type Printer struct {
 	Noisy            bool
 	IgnoreLoopback   bool
 	JunitResultsFile string
+	HTMLReportFile   string
 	Results          []*Result
 }
 
@@ -40,6 +41,27 @@ func (t *Printer) PrintSummary() {
 	if err := PrintJUnitResults(t.JunitResultsFile, t.Results, t.IgnoreLoopback); err != nil {
 		logrus.Errorf("unable to dump JUnit test results: %+v", err)
 	}
+	if err := WriteHTMLReport(t.HTMLReportFile, t.Results, t.IgnoreLoopback); err != nil {
+		logrus.Errorf("unable to write html report: %+v", err)
+	}
+}
+
+// PrintMarkdownSummary prints whether each test passed, and the feature and tag results, as markdown
+func (t *Printer) PrintMarkdownSummary() {
+	summary := NewSummaryTableFromResults(t.IgnoreLoopback, t.Results)
+
+	lines := []string{"| Test | Result |", "| --- | --- |"}
+	for i, result := range t.Results {
+		symbol := passSymbol
+		if !result.Passed(t.IgnoreLoopback) {
+			symbol = failSymbol
+		}
+		lines = append(lines, fmt.Sprintf("| %d: %s | %s |", i+1, result.TestCase.Description, symbol))
+	}
+	fmt.Printf("Test results:\n%s\n\n", strings.Join(lines, "\n"))
+
+	fmt.Printf("Feature results:\n%s\n\n", t.printMarkdownFeatureTable(summary.FeaturePrimaryCounts, summary.FeatureCounts))
+	fmt.Printf("Tag results:\n%s\n", t.printMarkdownFeatureTable(summary.TagPrimaryCounts, summary.TagCounts))
 }
 
 const (
@@ -74,6 +96,16 @@ func (m *markdownRow) GetResult() string {
 }
 
 func (t *Printer) printMarkdownFeatureTable(primaryCounts map[string]map[bool]int, tagCounts map[string]map[string]map[bool]int) string {
+	lines := []string{"| Tag | Result |", "| --- | --- |"}
+	for _, row := range featureRows(primaryCounts, tagCounts) {
+		lines = append(lines, fmt.Sprintf("| %s | %s |", row.GetName(), row.GetResult()))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// featureRows returns a row for each primary feature or tag, followed by a row for each of its subordinates
+func featureRows(primaryCounts map[string]map[bool]int, tagCounts map[string]map[string]map[bool]int) []*markdownRow {
 	primaries := slice.Sort(maps.Keys(tagCounts))
 
 	var rows []*markdownRow
@@ -91,12 +123,7 @@ func (t *Printer) printMarkdownFeatureTable(primaryCounts map[string]map[bool]in
 		}
 	}
 
-	lines := []string{"| Tag | Result |", "| --- | --- |"}
-	for _, row := range rows {
-		lines = append(lines, fmt.Sprintf("| %s | %s |", row.GetName(), row.GetResult()))
-	}
-
-	return strings.Join(lines, "\n")
+	return rows
 }
 
 func (t *Printer) printTestSummary(rows [][]string) {
@@ -233,6 +260,12 @@ func (t *Printer) PrintStep(i int, step *generator.TestStep, stepResult *StepRes
 	} else {
 		fmt.Println("no network policies")
 	}
+	for _, anp := range stepResult.ANPs {
+		fmt.Printf("Admin network policy:\n\n%s\n", utils.YamlString(anp))
+	}
+	if stepResult.BANP != nil {
+		fmt.Printf("Baseline admin network policy:\n\n%s\n", utils.YamlString(stepResult.BANP))
+	}
 
 	if len(stepResult.KubeProbes) == 0 {
 		panic(errors.Errorf("found 0 KubeResults for step, expected 1 or more"))
@@ -257,6 +290,14 @@ func (t *Printer) PrintStep(i int, step *generator.TestStep, stepResult *StepRes
 		}
 
 		fmt.Printf("\nActual vs expected (last round):\n%s\n", comparison.RenderSuccessTable())
+
+		if mismatches := comparison.Mismatches(t.IgnoreLoopback); len(mismatches) > 0 {
+			fmt.Printf("Mismatches, with the policy rules which decided the expected results:\n")
+			for _, mismatch := range mismatches {
+				fmt.Printf("- %s\n", mismatch)
+			}
+			fmt.Println()
+		}
 	} else {
 		fmt.Printf("%s\n", stepResult.LastKubeProbe().RenderTable())
 	}
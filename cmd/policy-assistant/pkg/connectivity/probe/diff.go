@@ -0,0 +1,92 @@
+package probe
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattfenwick/cyclonus/pkg/matcher"
+	"github.com/olekukonko/tablewriter"
+)
+
+// PolicyDiff is a job whose simulated ingress or egress verdict differs between two sets of policies.
+type PolicyDiff struct {
+	Job    *Job
+	Before *matcher.AllowedResult
+	After  *matcher.AllowedResult
+}
+
+func (d *PolicyDiff) IngressChanged() bool {
+	return d.Before.Ingress.IsAllowed() != d.After.Ingress.IsAllowed()
+}
+
+func (d *PolicyDiff) EgressChanged() bool {
+	return d.Before.Egress.IsAllowed() != d.After.Egress.IsAllowed()
+}
+
+func (d *PolicyDiff) CombinedChanged() bool {
+	return d.Before.IsAllowed() != d.After.IsAllowed()
+}
+
+// DiffPolicies simulates each job against both sets of policies, and returns
+// only the jobs whose ingress or egress verdict changed, in job order.
+// Changes in the flow which don't change a verdict (e.g. "[NPv1] Allow" to "[ANP] Allow") are not reported.
+func DiffPolicies(before *matcher.Policy, after *matcher.Policy, jobs []*Job) PolicyDiffs {
+	var diffs PolicyDiffs
+	for _, job := range jobs {
+		diff := &PolicyDiff{
+			Job:    job,
+			Before: before.IsTrafficAllowed(job.Traffic()),
+			After:  after.IsTrafficAllowed(job.Traffic()),
+		}
+		if diff.IngressChanged() || diff.EgressChanged() {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs
+}
+
+type PolicyDiffs []*PolicyDiff
+
+func (p PolicyDiffs) Table() string {
+	tableString := &strings.Builder{}
+	table := tablewriter.NewWriter(tableString)
+	table.SetAutoWrapText(false)
+	table.SetRowLine(true)
+	table.SetAutoMergeCells(true)
+	table.SetHeader([]string{"From", "To", "Port/Protocol", "Direction", "Before", "After"})
+	for _, d := range p {
+		portProtocol := fmt.Sprintf("%d/%s", d.Job.ResolvedPort, d.Job.Protocol)
+		if d.Job.ResolvedPortName != "" {
+			portProtocol = fmt.Sprintf("%d (%s)/%s", d.Job.ResolvedPort, d.Job.ResolvedPortName, d.Job.Protocol)
+		}
+		row := func(direction string, before string, after string) {
+			table.Append([]string{d.Job.FromKey, d.Job.ToKey, portProtocol, direction, before, after})
+		}
+		if d.IngressChanged() {
+			row("Ingress", flowString(d.Before.Ingress), flowString(d.After.Ingress))
+		}
+		if d.EgressChanged() {
+			row("Egress", flowString(d.Before.Egress), flowString(d.After.Egress))
+		}
+		if d.CombinedChanged() {
+			row("Combined", allowedString(d.Before.IsAllowed()), allowedString(d.After.IsAllowed()))
+		}
+	}
+	table.Render()
+	return tableString.String()
+}
+
+func flowString(d matcher.DirectionResult) string {
+	if flow := d.Flow(); flow != "" {
+		return flow
+	}
+	// no policies select the traffic
+	return "no policies"
+}
+
+func allowedString(isAllowed bool) string {
+	if isAllowed {
+		return "allowed"
+	}
+	return "blocked"
+}
@@ -0,0 +1,75 @@
+package probe
+
+import (
+	"github.com/mattfenwick/cyclonus/pkg/generator"
+	"github.com/mattfenwick/cyclonus/pkg/matcher"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func RunDiffTests() {
+	Describe("DiffPolicies", func() {
+		resources := &Resources{
+			Namespaces: map[string]map[string]string{
+				"x": {"ns": "x"},
+				"z": {"ns": "z"},
+			},
+			Pods: []*Pod{
+				{Namespace: "x", Name: "a", Labels: map[string]string{"pod": "a"}, IP: "192.168.1.1",
+					Containers: []*Container{{Name: "cont-80", Port: 80, PortName: "serve-80-tcp", Protocol: v1.ProtocolTCP}}},
+				{Namespace: "z", Name: "b", Labels: map[string]string{"pod": "b"}, IP: "192.168.1.2",
+					Containers: []*Container{{Name: "cont-80", Port: 80, PortName: "serve-80-tcp", Protocol: v1.ProtocolTCP}}},
+			},
+		}
+		jobs := (&JobBuilder{TimeoutSeconds: 1}).GetJobsForProbeConfig(resources,
+			generator.NewProbeConfig(intstr.FromInt(80), v1.ProtocolTCP, generator.ProbeModeServiceName)).Valid
+
+		allowIngressWithinX, err := utils.ParseYaml[networkingv1.NetworkPolicy]([]byte(`
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: allow-ingress-within-namespace
+  namespace: x
+spec:
+  podSelector: {}
+  ingress:
+  - from:
+    - podSelector: {}
+  policyTypes:
+  - Ingress`))
+		utils.DoOrDie(err)
+
+		before, err := matcher.BuildNetworkPolicies(true, nil)
+		utils.DoOrDie(err)
+		after, err := matcher.BuildNetworkPolicies(true, []*networkingv1.NetworkPolicy{allowIngressWithinX})
+		utils.DoOrDie(err)
+
+		It("Should report nothing for identical policies", func() {
+			Expect(DiffPolicies(after, after, jobs)).To(BeEmpty())
+		})
+
+		It("Should report only the traffic whose verdict changed", func() {
+			diffs := DiffPolicies(before, after, jobs)
+			Expect(diffs).To(HaveLen(1))
+			diff := diffs[0]
+			Expect(diff.Job.FromKey).To(Equal("z/b"))
+			Expect(diff.Job.ToKey).To(Equal("x/a"))
+			Expect(diff.IngressChanged()).To(BeTrue())
+			Expect(diff.EgressChanged()).To(BeFalse())
+			Expect(diff.CombinedChanged()).To(BeTrue())
+			Expect(diff.Before.Ingress.Flow()).To(Equal(""))
+			Expect(diff.After.Ingress.Flow()).To(Equal("[NPv1] Dropped"))
+			Expect(diffs.Table()).To(ContainSubstring("[NPv1] Dropped"))
+		})
+
+		It("Should report the reverse of a change", func() {
+			diffs := DiffPolicies(after, before, jobs)
+			Expect(diffs).To(HaveLen(1))
+			Expect(diffs[0].After.IsAllowed()).To(BeTrue())
+		})
+	})
+}
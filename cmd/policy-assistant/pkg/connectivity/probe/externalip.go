@@ -0,0 +1,94 @@
+package probe
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+)
+
+// ExternalKeyPrefix distinguishes external IP keys from "namespace/pod" and node keys in probe tables
+const ExternalKeyPrefix = "external:"
+
+// ExternalIP is a destination outside the cluster, e.g. to check egress to the internet.
+// Only egress rules -- ipBlock peers and ANP/BANP networks peers -- apply to traffic to it.
+type ExternalIP struct {
+	IP string
+	// CIDR is set if the target was given as a CIDR, in which case IP is the address in it which is
+	// probed and simulated -- so policies on the CIDR, or on part of it, are tested at that address
+	CIDR string
+	// Ports are the ports served on the IP, such as 53/UDP for a DNS server
+	Ports []*ServedPort
+}
+
+func (e *ExternalIP) Key() string {
+	return ExternalKeyPrefix + e.IP
+}
+
+func (e *ExternalIP) IsServingPortProtocol(port int, protocol v1.Protocol) bool {
+	return isServingPortProtocol(e.Ports, port, protocol)
+}
+
+// ParseExternalIPs parses targets of the form "ip:port/protocol", such as "8.8.8.8:53/UDP" or "[2001:db8::1]:443/TCP",
+// or "cidr:port/protocol", such as "203.0.113.0/24:443/TCP", to probe the first host address of the CIDR.
+// Targets with the same IP are combined, in the order they're first seen.
+func ParseExternalIPs(targets []string) ([]*ExternalIP, error) {
+	var externalIPs []*ExternalIP
+	byIP := map[string]*ExternalIP{}
+	for _, target := range targets {
+		// a CIDR has a '/' too, so the protocol is after the last one
+		slash := strings.LastIndex(target, "/")
+		if slash < 0 {
+			return nil, errors.Errorf("invalid external target %s: expected ip:port/protocol or cidr:port/protocol", target)
+		}
+		hostPort, protocolString := target[:slash], target[slash+1:]
+		host, portString, err := net.SplitHostPort(hostPort)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid external target %s", target)
+		}
+		ip, cidr := host, ""
+		if strings.Contains(host, "/") {
+			_, ipNet, err := net.ParseCIDR(host)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid external target %s", target)
+			}
+			ip, cidr = firstHostIP(ipNet).String(), ipNet.String()
+			logrus.Infof("probing external target %s at %s", target, ip)
+		} else if net.ParseIP(host) == nil {
+			return nil, errors.Errorf("invalid external target %s: %s is not an IP or a CIDR", target, host)
+		}
+		port, err := strconv.Atoi(portString)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid external target %s", target)
+		}
+		protocol, err := kube.ParseProtocol(protocolString)
+		if err != nil {
+			return nil, err
+		}
+
+		externalIP, ok := byIP[ip]
+		if !ok {
+			externalIP = &ExternalIP{IP: ip, CIDR: cidr}
+			byIP[ip] = externalIP
+			externalIPs = append(externalIPs, externalIP)
+		}
+		externalIP.Ports = append(externalIP.Ports, &ServedPort{Port: port, Protocol: protocol})
+	}
+	return externalIPs, nil
+}
+
+// firstHostIP returns the address after the network address, or the network address itself
+// if the CIDR has no more than 2 addresses, such as a /32 or a /31
+func firstHostIP(ipNet *net.IPNet) net.IP {
+	ip := append(net.IP{}, ipNet.IP...)
+	ones, bits := ipNet.Mask.Size()
+	if bits-ones > 1 {
+		// the network address ends in at least 2 zero bits, so this doesn't carry
+		ip[len(ip)-1]++
+	}
+	return ip
+}
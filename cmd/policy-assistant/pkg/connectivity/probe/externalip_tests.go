@@ -0,0 +1,126 @@
+package probe
+
+import (
+	"github.com/mattfenwick/cyclonus/pkg/generator"
+	"github.com/mattfenwick/cyclonus/pkg/matcher"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
+)
+
+func RunExternalIPTests() {
+	Describe("ParseExternalIPs", func() {
+		It("Should combine targets by IP", func() {
+			externalIPs, err := ParseExternalIPs([]string{"8.8.8.8:53/UDP", "1.1.1.1:443/tcp", "8.8.8.8:53/TCP", "[2001:db8::1]:80/TCP"})
+			Expect(err).To(Succeed())
+			Expect(externalIPs).To(Equal([]*ExternalIP{
+				{IP: "8.8.8.8", Ports: []*ServedPort{{Port: 53, Protocol: v1.ProtocolUDP}, {Port: 53, Protocol: v1.ProtocolTCP}}},
+				{IP: "1.1.1.1", Ports: []*ServedPort{{Port: 443, Protocol: v1.ProtocolTCP}}},
+				{IP: "2001:db8::1", Ports: []*ServedPort{{Port: 80, Protocol: v1.ProtocolTCP}}},
+			}))
+		})
+
+		It("Should probe the first host address of a CIDR", func() {
+			externalIPs, err := ParseExternalIPs([]string{"203.0.113.0/24:443/TCP", "198.51.100.7/32:80/TCP", "[2001:db8::/64]:53/UDP", "203.0.113.1:80/TCP"})
+			Expect(err).To(Succeed())
+			Expect(externalIPs).To(Equal([]*ExternalIP{
+				{IP: "203.0.113.1", CIDR: "203.0.113.0/24", Ports: []*ServedPort{{Port: 443, Protocol: v1.ProtocolTCP}, {Port: 80, Protocol: v1.ProtocolTCP}}},
+				{IP: "198.51.100.7", CIDR: "198.51.100.7/32", Ports: []*ServedPort{{Port: 80, Protocol: v1.ProtocolTCP}}},
+				{IP: "2001:db8::1", CIDR: "2001:db8::/64", Ports: []*ServedPort{{Port: 53, Protocol: v1.ProtocolUDP}}},
+			}))
+		})
+
+		It("Should reject invalid targets", func() {
+			for _, target := range []string{"8.8.8.8:53", "8.8.8.8/UDP", "www.google.com:443/TCP", "8.8.8.8:dns/UDP", "8.8.8.8:53/ICMP", "8.8.8.0/33:53/UDP", "8.8.8.0/24/UDP"} {
+				_, err := ParseExternalIPs([]string{target})
+				Expect(err).ToNot(Succeed(), target)
+			}
+		})
+	})
+
+	Describe("External IPs in simulated probes", func() {
+		resources := &Resources{
+			Namespaces: map[string]map[string]string{"x": {"ns": "x"}},
+			Pods: []*Pod{
+				{Namespace: "x", Name: "a", Labels: map[string]string{"pod": "a"}, IP: "192.168.1.1",
+					Containers: []*Container{{Name: "cont-80", Port: 80, PortName: "serve-80-tcp", Protocol: v1.ProtocolTCP}}},
+			},
+			ExternalIPs: []*ExternalIP{
+				{IP: "8.8.8.8", Ports: []*ServedPort{{Port: 53, Protocol: v1.ProtocolUDP}}},
+				{IP: "1.1.1.1", Ports: []*ServedPort{{Port: 443, Protocol: v1.ProtocolTCP}}},
+			},
+		}
+
+		denyEgressToGoogleDNS, err := utils.ParseYaml[v1alpha1.AdminNetworkPolicy]([]byte(`
+apiVersion: policy.networking.k8s.io/v1alpha1
+kind: AdminNetworkPolicy
+metadata:
+  name: deny-egress-to-google-dns
+spec:
+  priority: 10
+  subject:
+    namespaces: {}
+  egress:
+  - name: deny-to-google-dns
+    action: Deny
+    to:
+    - networks:
+      - 8.8.8.0/24`))
+		utils.DoOrDie(err)
+		policies, err := matcher.BuildV1AndV2NetPols(true, nil, []*v1alpha1.AdminNetworkPolicy{denyEgressToGoogleDNS}, nil)
+		utils.DoOrDie(err)
+		runner := NewSimulatedRunner(policies, &JobBuilder{TimeoutSeconds: 1})
+
+		It("Should add a column for each external IP", func() {
+			Expect(resources.SortedDestinationNames()).To(Equal([]string{"x/a", "external:1.1.1.1", "external:8.8.8.8"}))
+		})
+
+		It("Should probe external IPs on all their ports", func() {
+			table := runner.RunProbeForConfig(generator.ProbeAllAvailable, resources)
+			Expect(table.Get("x/a", "external:8.8.8.8").JobResults["UDP/53"].Combined).To(Equal(ConnectivityBlocked))
+			Expect(table.Get("x/a", "external:1.1.1.1").JobResults["TCP/443"].Combined).To(Equal(ConnectivityAllowed))
+		})
+
+		It("Should only probe external IPs on ports they serve", func() {
+			jobs := (&JobBuilder{TimeoutSeconds: 1}).GetJobsForProbeConfig(resources, generator.NewProbeConfig(intstr.FromInt(443), v1.ProtocolTCP, generator.ProbeModeServiceName))
+			Expect(toKeys(jobs.Valid)).To(ConsistOf("external:1.1.1.1"))
+			Expect(toKeys(jobs.BadPortProtocol)).To(ConsistOf("x/a", "external:8.8.8.8"))
+		})
+
+		It("Should apply ipBlock egress rules to external IPs", func() {
+			allowEgressToCloudflare, err := utils.ParseYaml[networkingv1.NetworkPolicy]([]byte(`
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: allow-egress-to-cloudflare
+  namespace: x
+spec:
+  podSelector: {}
+  egress:
+  - to:
+    - ipBlock:
+        cidr: 1.1.1.0/24
+  policyTypes:
+  - Egress`))
+			utils.DoOrDie(err)
+			v1Policies, err := matcher.BuildNetworkPolicies(true, []*networkingv1.NetworkPolicy{allowEgressToCloudflare})
+			utils.DoOrDie(err)
+
+			table := NewSimulatedRunner(v1Policies, &JobBuilder{TimeoutSeconds: 1}).RunProbeForConfig(generator.ProbeAllAvailable, resources)
+			Expect(table.Get("x/a", "external:8.8.8.8").JobResults["UDP/53"].Combined).To(Equal(ConnectivityBlocked))
+			Expect(table.Get("x/a", "external:1.1.1.1").JobResults["TCP/443"].Combined).To(Equal(ConnectivityAllowed))
+		})
+	})
+}
+
+func toKeys(jobs []*Job) []string {
+	var keys []string
+	for _, job := range jobs {
+		keys = append(keys, job.ToKey)
+	}
+	return keys
+}
@@ -20,12 +20,29 @@ type JobResult struct {
 	Ingress  *Connectivity
 	Egress   *Connectivity
 	Combined Connectivity
+	// IngressEffect and EgressEffect are only set for simulated results: they're the effects which decided
+	// each direction, or nil if no policies applied
+	IngressEffect *matcher.Effect
+	EgressEffect  *matcher.Effect
 }
 
 func (jr *JobResult) Key() string {
 	return fmt.Sprintf("%s/%d", jr.Job.Protocol, jr.Job.ResolvedPort)
 }
 
+// Explanation describes the effects which decided a simulated result, e.g.
+// "ingress: [ANP] team-a rule 'deny-to-db' priority 3: Deny; egress: no policies"
+func (jr *JobResult) Explanation() string {
+	return fmt.Sprintf("ingress: %s; egress: %s", effectString(jr.IngressEffect), effectString(jr.EgressEffect))
+}
+
+func effectString(effect *matcher.Effect) string {
+	if effect == nil {
+		return "no policies"
+	}
+	return effect.String()
+}
+
 type Job struct {
 	FromKey             string
 	FromNamespace       string
@@ -42,6 +59,11 @@ type Job struct {
 	ToPodLabels       map[string]string
 	ToContainer       string
 	ToIP              string
+	// ToNode is only set for jobs targeting a node
+	ToNode       string
+	ToNodeLabels map[string]string
+	// ToExternal is only set for jobs targeting an external IP
+	ToExternal bool
 
 	ResolvedPort     int
 	ResolvedPortName string
@@ -76,15 +98,33 @@ func (j *Job) KubeExecCommand() []string {
 }
 
 func (j *Job) Traffic() *matcher.Traffic {
-	return &matcher.Traffic{
-		Source: &matcher.TrafficPeer{
-			Internal: &matcher.InternalPeer{
-				PodLabels:       j.FromPodLabels,
-				NamespaceLabels: j.FromNamespaceLabels,
-				Namespace:       j.FromNamespace,
+	if j.ToExternal {
+		return &matcher.Traffic{
+			Source:           j.sourceTrafficPeer(),
+			Destination:      &matcher.TrafficPeer{IP: j.ToIP},
+			ResolvedPort:     j.ResolvedPort,
+			ResolvedPortName: j.ResolvedPortName,
+			Protocol:         j.Protocol,
+		}
+	}
+	if j.ToNode != "" {
+		return &matcher.Traffic{
+			Source: j.sourceTrafficPeer(),
+			Destination: &matcher.TrafficPeer{
+				Node: &matcher.NodePeer{
+					Name:   j.ToNode,
+					Labels: j.ToNodeLabels,
+					IPs:    []string{j.ToIP},
+				},
+				IP: j.ToIP,
 			},
-			IP: j.FromIP,
-		},
+			ResolvedPort:     j.ResolvedPort,
+			ResolvedPortName: j.ResolvedPortName,
+			Protocol:         j.Protocol,
+		}
+	}
+	return &matcher.Traffic{
+		Source: j.sourceTrafficPeer(),
 		Destination: &matcher.TrafficPeer{
 			Internal: &matcher.InternalPeer{
 				PodLabels:       j.ToPodLabels,
@@ -98,3 +138,14 @@ func (j *Job) Traffic() *matcher.Traffic {
 		Protocol:         j.Protocol,
 	}
 }
+
+func (j *Job) sourceTrafficPeer() *matcher.TrafficPeer {
+	return &matcher.TrafficPeer{
+		Internal: &matcher.InternalPeer{
+			PodLabels:       j.FromPodLabels,
+			NamespaceLabels: j.FromNamespaceLabels,
+			Namespace:       j.FromNamespace,
+		},
+		IP: j.FromIP,
+	}
+}
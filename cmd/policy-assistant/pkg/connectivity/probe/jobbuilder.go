@@ -70,6 +70,36 @@ func (j *JobBuilder) GetJobsForNamedPortProtocol(resources *Resources, port ints
 
 			jobs.Valid = append(jobs.Valid, job)
 		}
+		for _, nodeTo := range resources.Nodes {
+			job := j.newNodeJob(resources, podFrom, nodeTo, -1, protocol)
+			// nodes don't have named ports
+			if port.Type == intstr.String {
+				job.ResolvedPortName = port.StrVal
+				jobs.BadNamedPort = append(jobs.BadNamedPort, job)
+				continue
+			}
+			job.ResolvedPort = int(port.IntVal)
+			if !nodeTo.IsServingPortProtocol(job.ResolvedPort, protocol) {
+				jobs.BadPortProtocol = append(jobs.BadPortProtocol, job)
+				continue
+			}
+			jobs.Valid = append(jobs.Valid, job)
+		}
+		for _, externalTo := range resources.ExternalIPs {
+			job := j.newExternalIPJob(resources, podFrom, externalTo, -1, protocol)
+			// external IPs don't have named ports
+			if port.Type == intstr.String {
+				job.ResolvedPortName = port.StrVal
+				jobs.BadNamedPort = append(jobs.BadNamedPort, job)
+				continue
+			}
+			job.ResolvedPort = int(port.IntVal)
+			if !externalTo.IsServingPortProtocol(job.ResolvedPort, protocol) {
+				jobs.BadPortProtocol = append(jobs.BadPortProtocol, job)
+				continue
+			}
+			jobs.Valid = append(jobs.Valid, job)
+		}
 	}
 	return jobs
 }
@@ -101,6 +131,59 @@ func (j *JobBuilder) GetJobsAllAvailableServers(resources *Resources, mode gener
 				})
 			}
 		}
+		for _, nodeTo := range resources.Nodes {
+			for _, nodePort := range nodeTo.Ports {
+				jobs = append(jobs, j.newNodeJob(resources, podFrom, nodeTo, nodePort.Port, nodePort.Protocol))
+			}
+		}
+		for _, externalTo := range resources.ExternalIPs {
+			for _, externalPort := range externalTo.Ports {
+				jobs = append(jobs, j.newExternalIPJob(resources, podFrom, externalTo, externalPort.Port, externalPort.Protocol))
+			}
+		}
 	}
 	return &Jobs{Valid: jobs}
 }
+
+// newNodeJob creates a job from a pod to a node.  Nodes are always probed by IP.
+func (j *JobBuilder) newNodeJob(resources *Resources, podFrom *Pod, nodeTo *Node, port int, protocol v1.Protocol) *Job {
+	return &Job{
+		FromKey:             podFrom.PodString().String(),
+		FromNamespace:       podFrom.Namespace,
+		FromNamespaceLabels: resources.Namespaces[podFrom.Namespace],
+		FromPod:             podFrom.Name,
+		FromPodLabels:       podFrom.Labels,
+		FromContainer:       podFrom.Containers[0].Name,
+		FromIP:              podFrom.IP,
+		ToKey:               nodeTo.Key(),
+		ToHost:              nodeTo.IP,
+		ToIP:                nodeTo.IP,
+		ToNode:              nodeTo.Name,
+		ToNodeLabels:        nodeTo.Labels,
+		ResolvedPort:        port,
+		ResolvedPortName:    "",
+		Protocol:            protocol,
+		TimeoutSeconds:      j.TimeoutSeconds,
+	}
+}
+
+// newExternalIPJob creates a job from a pod to an external IP.
+func (j *JobBuilder) newExternalIPJob(resources *Resources, podFrom *Pod, externalTo *ExternalIP, port int, protocol v1.Protocol) *Job {
+	return &Job{
+		FromKey:             podFrom.PodString().String(),
+		FromNamespace:       podFrom.Namespace,
+		FromNamespaceLabels: resources.Namespaces[podFrom.Namespace],
+		FromPod:             podFrom.Name,
+		FromPodLabels:       podFrom.Labels,
+		FromContainer:       podFrom.Containers[0].Name,
+		FromIP:              podFrom.IP,
+		ToKey:               externalTo.Key(),
+		ToHost:              externalTo.IP,
+		ToIP:                externalTo.IP,
+		ToExternal:          true,
+		ResolvedPort:        port,
+		ResolvedPortName:    "",
+		Protocol:            protocol,
+		TimeoutSeconds:      j.TimeoutSeconds,
+	}
+}
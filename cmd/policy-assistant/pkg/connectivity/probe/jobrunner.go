@@ -92,7 +92,14 @@ func (s *SimulatedJobRunner) RunJob(job *Job) *JobResult {
 		combined = ConnectivityAllowed
 	}
 
-	return &JobResult{Job: job, Ingress: &ingress, Egress: &egress, Combined: combined}
+	return &JobResult{
+		Job:           job,
+		Ingress:       &ingress,
+		Egress:        &egress,
+		Combined:      combined,
+		IngressEffect: allowed.Ingress.Winner(),
+		EgressEffect:  allowed.Egress.Winner(),
+	}
 }
 
 type KubeJobRunner struct {
@@ -0,0 +1,25 @@
+package probe
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// NodeKeyPrefix distinguishes node keys from "namespace/pod" keys in probe tables
+const NodeKeyPrefix = "node:"
+
+// Node is a node which can be probed as a destination, e.g. to check access to its kubelet port.
+type Node struct {
+	Name   string
+	Labels map[string]string
+	IP     string
+	// Ports are the ports served on the node's IP, such as 10250/TCP for the kubelet
+	Ports []*ServedPort
+}
+
+func (n *Node) Key() string {
+	return NodeKeyPrefix + n.Name
+}
+
+func (n *Node) IsServingPortProtocol(port int, protocol v1.Protocol) bool {
+	return isServingPortProtocol(n.Ports, port, protocol)
+}
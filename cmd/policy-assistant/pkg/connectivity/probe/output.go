@@ -0,0 +1,81 @@
+package probe
+
+import (
+	"github.com/mattfenwick/collections/pkg/slice"
+	"github.com/mattfenwick/cyclonus/pkg/matcher"
+	"golang.org/x/exp/maps"
+	v1 "k8s.io/api/core/v1"
+)
+
+// JobResultOutput is the stable, machine-readable form of a JobResult
+type JobResultOutput struct {
+	From     string      `json:"from"`
+	To       string      `json:"to"`
+	Port     int         `json:"port"`
+	PortName string      `json:"portName,omitempty"`
+	Protocol v1.Protocol `json:"protocol"`
+	// Ingress and Egress are only set if they were probed separately, as in a simulation
+	Ingress  *Connectivity `json:"ingress,omitempty"`
+	Egress   *Connectivity `json:"egress,omitempty"`
+	Combined Connectivity  `json:"combined"`
+	// IngressEffect and EgressEffect are only set for simulated results which policies applied to
+	IngressEffect *matcher.EffectOutput `json:"ingressEffect,omitempty"`
+	EgressEffect  *matcher.EffectOutput `json:"egressEffect,omitempty"`
+}
+
+func (jr *JobResult) Output() *JobResultOutput {
+	out := &JobResultOutput{
+		From:     jr.Job.FromKey,
+		To:       jr.Job.ToKey,
+		Port:     jr.Job.ResolvedPort,
+		PortName: jr.Job.ResolvedPortName,
+		Protocol: jr.Job.Protocol,
+		Ingress:  jr.Ingress,
+		Egress:   jr.Egress,
+		Combined: jr.Combined,
+	}
+	if jr.IngressEffect != nil {
+		out.IngressEffect = jr.IngressEffect.Output()
+	}
+	if jr.EgressEffect != nil {
+		out.EgressEffect = jr.EgressEffect.Output()
+	}
+	return out
+}
+
+// Output returns the results of every from/to pair, ordered by from, to, then protocol/port
+func (t *Table) Output() []*JobResultOutput {
+	var out []*JobResultOutput
+	for _, key := range t.Wrapped.Keys() {
+		jobResults := t.Get(key.From, key.To).JobResults
+		for _, portProtocol := range slice.Sort(maps.Keys(jobResults)) {
+			out = append(out, jobResults[portProtocol].Output())
+		}
+	}
+	return out
+}
+
+// PolicyDiffOutput is the stable, machine-readable form of a PolicyDiff
+type PolicyDiffOutput struct {
+	From     string                       `json:"from"`
+	To       string                       `json:"to"`
+	Port     int                          `json:"port"`
+	PortName string                       `json:"portName,omitempty"`
+	Protocol v1.Protocol                  `json:"protocol"`
+	Before   *matcher.AllowedResultOutput `json:"before"`
+	After    *matcher.AllowedResultOutput `json:"after"`
+}
+
+func (p PolicyDiffs) Output() []*PolicyDiffOutput {
+	return slice.Map(func(d *PolicyDiff) *PolicyDiffOutput {
+		return &PolicyDiffOutput{
+			From:     d.Job.FromKey,
+			To:       d.Job.ToKey,
+			Port:     d.Job.ResolvedPort,
+			PortName: d.Job.ResolvedPortName,
+			Protocol: d.Job.Protocol,
+			Before:   d.Before.Output(),
+			After:    d.After.Output(),
+		}
+	}, p)
+}
@@ -15,19 +15,35 @@ import (
 type Resources struct {
 	Namespaces map[string]map[string]string
 	Pods       []*Pod
-	//ExternalIPs []string
-	ports     []int
-	protocols []v1.Protocol
+	// Nodes are only used as probe destinations
+	Nodes []*Node
+	// ExternalIPs are only used as probe destinations
+	ExternalIPs []*ExternalIP
+	ports       []int
+	protocols   []v1.Protocol
 }
 
-func NewDefaultResources(kubernetes kube.IKubernetes, namespaces []string, podNames []string, ports []int, protocols []v1.Protocol, externalIPs []string, podCreationTimeoutSeconds int, batchJobs bool, imageRegistry string) (*Resources, error) {
-	//sort.Strings(externalIPs) // TODO why is this here?
+// ServedPort is a port and protocol served on an IP, such as a node's kubelet port or an external DNS server's port
+type ServedPort struct {
+	Port     int
+	Protocol v1.Protocol
+}
 
+func isServingPortProtocol(ports []*ServedPort, port int, protocol v1.Protocol) bool {
+	for _, p := range ports {
+		if p.Port == port && p.Protocol == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+func NewDefaultResources(kubernetes kube.IKubernetes, namespaces []string, podNames []string, ports []int, protocols []v1.Protocol, externalIPs []*ExternalIP, podCreationTimeoutSeconds int, batchJobs bool, imageRegistry string) (*Resources, error) {
 	r := &Resources{
-		Namespaces: map[string]map[string]string{},
-		//ExternalIPs: externalIPs,
-		ports:     ports,
-		protocols: protocols,
+		Namespaces:  map[string]map[string]string{},
+		ExternalIPs: externalIPs,
+		ports:       ports,
+		protocols:   protocols,
 	}
 
 	for _, ns := range namespaces {
@@ -142,8 +158,10 @@ func (r *Resources) CreateNamespace(ns string, labels map[string]string) (*Resou
 	}
 	newNamespaces[ns] = labels
 	return &Resources{
-		Namespaces: newNamespaces,
-		Pods:       r.Pods,
+		Namespaces:  newNamespaces,
+		Pods:        r.Pods,
+		Nodes:       r.Nodes,
+		ExternalIPs: r.ExternalIPs,
 	}, nil
 }
 
@@ -158,8 +176,10 @@ func (r *Resources) UpdateNamespaceLabels(ns string, labels map[string]string) (
 	}
 	newNamespaces[ns] = labels
 	return &Resources{
-		Namespaces: newNamespaces,
-		Pods:       r.Pods,
+		Namespaces:  newNamespaces,
+		Pods:        r.Pods,
+		Nodes:       r.Nodes,
+		ExternalIPs: r.ExternalIPs,
 	}, nil
 }
 
@@ -183,8 +203,10 @@ func (r *Resources) DeleteNamespace(ns string) (*Resources, error) {
 		}
 	}
 	return &Resources{
-		Namespaces: newNamespaces,
-		Pods:       pods,
+		Namespaces:  newNamespaces,
+		Pods:        pods,
+		Nodes:       r.Nodes,
+		ExternalIPs: r.ExternalIPs,
 	}, nil
 }
 
@@ -196,9 +218,10 @@ func (r *Resources) CreatePod(ns string, podName string, labels map[string]strin
 		return nil, errors.Errorf("can't find namespace %s", ns)
 	}
 	return &Resources{
-		Namespaces: r.Namespaces,
-		Pods:       append(append([]*Pod{}, r.Pods...), NewPod(ns, podName, labels, "TODO", r.Pods[0].Containers)),
-		//ExternalIPs: r.ExternalIPs,
+		Namespaces:  r.Namespaces,
+		Pods:        append(append([]*Pod{}, r.Pods...), NewPod(ns, podName, labels, "TODO", r.Pods[0].Containers)),
+		Nodes:       r.Nodes,
+		ExternalIPs: r.ExternalIPs,
 	}, nil
 }
 
@@ -218,9 +241,10 @@ func (r *Resources) SetPodLabels(ns string, podName string, labels map[string]st
 		return nil, errors.Errorf("no pod named %s/%s found", ns, podName)
 	}
 	return &Resources{
-		Namespaces: r.Namespaces,
-		Pods:       pods,
-		//ExternalIPs: r.ExternalIPs,
+		Namespaces:  r.Namespaces,
+		Pods:        pods,
+		Nodes:       r.Nodes,
+		ExternalIPs: r.ExternalIPs,
 	}, nil
 }
 
@@ -239,9 +263,10 @@ func (r *Resources) DeletePod(ns string, podName string) (*Resources, error) {
 		return nil, errors.Errorf("pod %s/%s not found", ns, podName)
 	}
 	return &Resources{
-		Namespaces: r.Namespaces,
-		Pods:       newPods,
-		//ExternalIPs: r.ExternalIPs,
+		Namespaces:  r.Namespaces,
+		Pods:        newPods,
+		Nodes:       r.Nodes,
+		ExternalIPs: r.ExternalIPs,
 	}, nil
 }
 
@@ -251,6 +276,13 @@ func (r *Resources) SortedPodNames() []string {
 		r.Pods))
 }
 
+// SortedDestinationNames returns the pod names followed by the node keys, then the external IP keys
+func (r *Resources) SortedDestinationNames() []string {
+	nodeKeys := slice.Sort(slice.Map(func(n *Node) string { return n.Key() }, r.Nodes))
+	externalKeys := slice.Sort(slice.Map(func(e *ExternalIP) string { return e.Key() }, r.ExternalIPs))
+	return append(append(r.SortedPodNames(), nodeKeys...), externalKeys...)
+}
+
 func (r *Resources) NamespacesSlice() []string {
 	return maps.Keys(r.Namespaces)
 }
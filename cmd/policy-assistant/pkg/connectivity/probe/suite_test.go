@@ -9,6 +9,8 @@ import (
 
 func TestProbe(t *testing.T) {
 	RegisterFailHandler(Fail)
+	RunDiffTests()
+	RunExternalIPTests()
 	RunResourcesTests()
 	RunSpecs(t, "generator suite")
 }
@@ -1,10 +1,12 @@
 package probe
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/mattfenwick/collections/pkg/slice"
+	"github.com/mattfenwick/cyclonus/pkg/matcher"
 	"github.com/mattfenwick/cyclonus/pkg/utils"
 	"github.com/pkg/errors"
 	"golang.org/x/exp/maps"
@@ -30,28 +32,45 @@ type Table struct {
 }
 
 func NewTableWithDefaultConnectivity(r *Resources, ingress, egress Connectivity) *Table {
-	return &Table{Wrapped: NewTruthTableFromItems(r.SortedPodNames(), func(fr, to string) interface{} {
-		results := make(map[string]*JobResult, len(r.ports)*len(r.protocols))
-		for _, proto := range r.protocols {
-			for _, port := range r.ports {
-				jr := &JobResult{
-					Job: &Job{
-						FromKey:          fr,
-						ToKey:            to,
-						ResolvedPort:     port,
-						ResolvedPortName: "",
-						Protocol:         proto,
-						TimeoutSeconds:   3,
-					},
-					Ingress: &ingress,
-					Egress:  &egress,
+	servedPorts := map[string][]*ServedPort{}
+	for _, node := range r.Nodes {
+		servedPorts[node.Key()] = node.Ports
+	}
+	for _, externalIP := range r.ExternalIPs {
+		servedPorts[externalIP.Key()] = externalIP.Ports
+	}
+	return &Table{Wrapped: NewTruthTable(r.SortedPodNames(), r.SortedDestinationNames(), func(fr, to string) interface{} {
+		// nodes and external IPs only serve their own ports
+		portProtocols := []*ServedPort{}
+		if ports, ok := servedPorts[to]; ok {
+			portProtocols = ports
+		} else {
+			for _, proto := range r.protocols {
+				for _, port := range r.ports {
+					portProtocols = append(portProtocols, &ServedPort{Port: port, Protocol: proto})
 				}
+			}
+		}
 
-				setCombined(jr)
-
-				k := fmt.Sprintf("%s/%d", proto, port)
-				results[k] = jr
+		results := make(map[string]*JobResult, len(portProtocols))
+		for _, pp := range portProtocols {
+			jr := &JobResult{
+				Job: &Job{
+					FromKey:          fr,
+					ToKey:            to,
+					ResolvedPort:     pp.Port,
+					ResolvedPortName: "",
+					Protocol:         pp.Protocol,
+					TimeoutSeconds:   3,
+				},
+				Ingress: &ingress,
+				Egress:  &egress,
 			}
+
+			setCombined(jr)
+
+			k := fmt.Sprintf("%s/%d", pp.Protocol, pp.Port)
+			results[k] = jr
 		}
 
 		return &Item{
@@ -96,8 +115,8 @@ func setCombined(jr *JobResult) {
 	}
 }
 
-func NewTable(items []string) *Table {
-	return &Table{Wrapped: NewTruthTableFromItems(items, func(fr, to string) interface{} {
+func NewTable(froms []string, tos []string) *Table {
+	return &Table{Wrapped: NewTruthTable(froms, tos, func(fr, to string) interface{} {
 		return &Item{
 			From:       fr,
 			To:         to,
@@ -107,7 +126,7 @@ func NewTable(items []string) *Table {
 }
 
 func NewTableFromJobResults(resources *Resources, jobResults []*JobResult) *Table {
-	table := NewTable(resources.SortedPodNames())
+	table := NewTable(resources.SortedPodNames(), resources.SortedDestinationNames())
 	for _, result := range jobResults {
 		fr := result.Job.FromKey
 		to := result.Job.ToKey
@@ -118,6 +137,89 @@ func NewTableFromJobResults(resources *Resources, jobResults []*JobResult) *Tabl
 	return table
 }
 
+// tableJSON is how a Table is serialized.  The truth table's values are interfaces, which can't be unmarshaled,
+// and only the fields of each job which identify its cell are kept, as the rest are the same for every table.
+type tableJSON struct {
+	Froms []string
+	Tos   []string
+	Cells []*cellJSON
+}
+
+type cellJSON struct {
+	From    string
+	To      string
+	Results []*cellResultJSON
+}
+
+type cellResultJSON struct {
+	Protocol v1.Protocol
+	Port     int
+	PortName string        `json:",omitempty"`
+	Ingress  *Connectivity `json:",omitempty"`
+	Egress   *Connectivity `json:",omitempty"`
+	Combined Connectivity
+	// IngressEffect and EgressEffect are only set for simulated results
+	IngressEffect *matcher.Effect `json:",omitempty"`
+	EgressEffect  *matcher.Effect `json:",omitempty"`
+}
+
+func (t *Table) MarshalJSON() ([]byte, error) {
+	serialized := &tableJSON{Froms: t.Wrapped.Froms, Tos: t.Wrapped.Tos}
+	for _, key := range t.Wrapped.Keys() {
+		item := t.Get(key.From, key.To)
+		cell := &cellJSON{From: item.From, To: item.To}
+		for _, k := range slice.Sort(maps.Keys(item.JobResults)) {
+			jr := item.JobResults[k]
+			cell.Results = append(cell.Results, &cellResultJSON{
+				Protocol: jr.Job.Protocol,
+				Port:     jr.Job.ResolvedPort,
+				PortName: jr.Job.ResolvedPortName,
+				Ingress:  jr.Ingress,
+				Egress:   jr.Egress,
+				Combined: jr.Combined,
+
+				IngressEffect: jr.IngressEffect,
+				EgressEffect:  jr.EgressEffect,
+			})
+		}
+		serialized.Cells = append(serialized.Cells, cell)
+	}
+	return json.Marshal(serialized)
+}
+
+func (t *Table) UnmarshalJSON(data []byte) error {
+	serialized := &tableJSON{}
+	if err := json.Unmarshal(data, serialized); err != nil {
+		return errors.Wrapf(err, "unable to unmarshal table")
+	}
+	table := NewTable(serialized.Froms, serialized.Tos)
+	for _, cell := range serialized.Cells {
+		item := table.Get(cell.From, cell.To)
+		for _, result := range cell.Results {
+			err := item.AddJobResult(&JobResult{
+				Job: &Job{
+					FromKey:          cell.From,
+					ToKey:            cell.To,
+					ResolvedPort:     result.Port,
+					ResolvedPortName: result.PortName,
+					Protocol:         result.Protocol,
+				},
+				Ingress:  result.Ingress,
+				Egress:   result.Egress,
+				Combined: result.Combined,
+
+				IngressEffect: result.IngressEffect,
+				EgressEffect:  result.EgressEffect,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	t.Wrapped = table.Wrapped
+	return nil
+}
+
 //func (t *Table) Set(from string, to string, value *Item) {
 //	t.Wrapped.Set(from, to, value)
 //}
@@ -142,11 +244,11 @@ func (t *Table) renderTableHelper(render func(*JobResult) string) string {
 	isSchemaUniform, isSingleElement := true, true
 	schema := map[string]bool{}
 
+	// nodes and external IPs serve different ports than pods, so cells don't necessarily share a schema
 	for _, key := range t.Wrapped.Keys() {
 		dict := t.Get(key.From, key.To).JobResults
 		if len(dict) != 1 {
 			isSingleElement = false
-			break
 		}
 		keys := slice.Sort(maps.Keys(dict))
 		schema[strings.Join(keys, "_")] = true
@@ -0,0 +1,66 @@
+package connectivity
+
+import (
+	"github.com/mattfenwick/cyclonus/pkg/generator"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+)
+
+// ResultsDiff compares two test runs -- for example, before and after a CNI upgrade -- by pairing up test cases
+// with the same description, in order.  Each pair is a MultipleContextResult, with a context for each run.
+type ResultsDiff struct {
+	Pairs []*MultipleContextResult
+	// Unpaired are test cases which were only run in one of the runs, or which probed different pods
+	Unpaired []*generator.TestCase
+}
+
+func DiffResults(beforeName string, before []*Result, afterName string, after []*Result) *ResultsDiff {
+	diff := &ResultsDiff{}
+	afterByDescription := map[string][]*Result{}
+	for _, result := range after {
+		afterByDescription[result.TestCase.Description] = append(afterByDescription[result.TestCase.Description], result)
+	}
+	for _, beforeResult := range before {
+		description := beforeResult.TestCase.Description
+		afterResults := afterByDescription[description]
+		if len(afterResults) == 0 {
+			diff.Unpaired = append(diff.Unpaired, beforeResult.TestCase)
+			continue
+		}
+		afterResult := afterResults[0]
+		afterByDescription[description] = afterResults[1:]
+
+		if !haveSameTables(beforeResult, afterResult) {
+			logrus.Warnf("unable to compare test case '%s': runs probed different pods or steps", description)
+			diff.Unpaired = append(diff.Unpaired, beforeResult.TestCase)
+			continue
+		}
+		diff.Pairs = append(diff.Pairs, &MultipleContextResult{
+			TestCase: beforeResult.TestCase,
+			Contexts: []string{beforeName, afterName},
+			Results:  map[string]*Result{beforeName: beforeResult, afterName: afterResult},
+		})
+	}
+	for _, result := range after {
+		if slices.Contains(afterByDescription[result.TestCase.Description], result) {
+			diff.Unpaired = append(diff.Unpaired, result.TestCase)
+		}
+	}
+	return diff
+}
+
+func haveSameTables(l *Result, r *Result) bool {
+	if len(l.Steps) != len(r.Steps) {
+		return false
+	}
+	for i := range l.Steps {
+		if len(l.Steps[i].KubeProbes) == 0 || len(r.Steps[i].KubeProbes) == 0 {
+			return false
+		}
+		lTable, rTable := l.Steps[i].LastKubeProbe().Wrapped, r.Steps[i].LastKubeProbe().Wrapped
+		if !slices.Equal(lTable.Froms, rTable.Froms) || !slices.Equal(lTable.Tos, rTable.Tos) {
+			return false
+		}
+	}
+	return true
+}
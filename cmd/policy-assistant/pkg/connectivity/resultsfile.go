@@ -0,0 +1,106 @@
+package connectivity
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/mattfenwick/cyclonus/pkg/connectivity/probe"
+	"github.com/mattfenwick/cyclonus/pkg/generator"
+	"github.com/mattfenwick/cyclonus/pkg/matcher"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
+)
+
+// ResultsFile is a test run saved to disk, so that it can be reported on -- or compared to another test run --
+// without rerunning it
+type ResultsFile struct {
+	Results []*Result
+}
+
+func WriteResultsFile(path string, results []*Result) error {
+	bytes, err := json.Marshal(&ResultsFile{Results: results})
+	if err != nil {
+		return errors.Wrapf(err, "unable to marshal results")
+	}
+	return errors.Wrapf(os.WriteFile(path, bytes, 0644), "unable to write results file %s", path)
+}
+
+func ReadResultsFile(path string) ([]*Result, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read results file %s", path)
+	}
+	resultsFile := &ResultsFile{}
+	if err := json.Unmarshal(bytes, resultsFile); err != nil {
+		return nil, errors.Wrapf(err, "unable to unmarshal results file %s", path)
+	}
+	return resultsFile.Results, nil
+}
+
+// resultJSON is how a Result is serialized: errors can't be unmarshaled, so only their messages are kept
+type resultJSON struct {
+	InitialResources *probe.Resources
+	TestCase         *generator.TestCase
+	Steps            []*StepResult
+	Err              string `json:",omitempty"`
+}
+
+func (r *Result) MarshalJSON() ([]byte, error) {
+	serialized := &resultJSON{InitialResources: r.InitialResources, TestCase: r.TestCase, Steps: r.Steps}
+	if r.Err != nil {
+		serialized.Err = r.Err.Error()
+	}
+	return json.Marshal(serialized)
+}
+
+func (r *Result) UnmarshalJSON(data []byte) error {
+	serialized := &resultJSON{}
+	if err := json.Unmarshal(data, serialized); err != nil {
+		return errors.Wrapf(err, "unable to unmarshal result")
+	}
+	r.InitialResources, r.TestCase, r.Steps = serialized.InitialResources, serialized.TestCase, serialized.Steps
+	if serialized.Err != "" {
+		r.Err = errors.New(serialized.Err)
+	}
+	return nil
+}
+
+// stepResultJSON is how a StepResult is serialized: the simulated policy isn't, since it can be rebuilt
+// from the step's policies
+type stepResultJSON struct {
+	SimulatedProbe *probe.Table
+	KubeProbes     []*probe.Table
+	KubePolicies   []*networkingv1.NetworkPolicy
+	ANPs           []*v1alpha1.AdminNetworkPolicy
+	BANP           *v1alpha1.BaselineAdminNetworkPolicy
+}
+
+func (s *StepResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&stepResultJSON{
+		SimulatedProbe: s.SimulatedProbe,
+		KubeProbes:     s.KubeProbes,
+		KubePolicies:   s.KubePolicies,
+		ANPs:           s.ANPs,
+		BANP:           s.BANP,
+	})
+}
+
+func (s *StepResult) UnmarshalJSON(data []byte) error {
+	serialized := &stepResultJSON{}
+	if err := json.Unmarshal(data, serialized); err != nil {
+		return errors.Wrapf(err, "unable to unmarshal step result")
+	}
+	// like the interpreter, which records steps with invalid policies, the valid policies are simulated
+	policy, err := matcher.BuildV1AndV2NetPols(true, serialized.KubePolicies, serialized.ANPs, serialized.BANP)
+	if err != nil {
+		logrus.Warnf("unable to rebuild some policies of step result; simulating the valid policies only:\n%+v", err)
+	}
+	*s = *NewStepResult(serialized.SimulatedProbe, policy, serialized.KubePolicies)
+	s.ANPs, s.BANP = serialized.ANPs, serialized.BANP
+	for _, kubeProbe := range serialized.KubeProbes {
+		s.AddKubeProbe(kubeProbe)
+	}
+	return nil
+}
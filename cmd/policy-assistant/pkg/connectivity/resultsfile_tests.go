@@ -0,0 +1,134 @@
+package connectivity
+
+import (
+	"path/filepath"
+
+	"github.com/mattfenwick/cyclonus/pkg/connectivity/probe"
+	"github.com/mattfenwick/cyclonus/pkg/generator"
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	"github.com/mattfenwick/cyclonus/pkg/matcher"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func RunResultsFileTests() {
+	Describe("Results files", func() {
+		var results []*Result
+		BeforeEach(func() {
+			kubernetes := kube.NewMockKubernetes(1)
+			resources, err := probe.NewDefaultResources(kubernetes, []string{"x", "y"}, []string{"a", "b"}, []int{80, 81}, []v1.Protocol{v1.ProtocolTCP, v1.ProtocolUDP}, nil, 5, false, "registry.k8s.io")
+			utils.DoOrDie(err)
+			interpreter := NewInterpreter(kubernetes, resources, &InterpreterConfig{ResetClusterBeforeTestCase: true, VerifyClusterStateBeforeTestCase: true})
+
+			// SCTP isn't served, so this denies all ingress to x/a
+			sctp := v1.ProtocolSCTP
+			allowSCTPToXA := (&generator.Netpol{
+				Name:    "allow-sctp",
+				Target:  generator.NewNetpolTarget("x", map[string]string{"pod": "a"}, nil),
+				Ingress: &generator.NetpolPeers{Rules: []*generator.Rule{{Ports: []networkingv1.NetworkPolicyPort{{Protocol: &sctp}}}}},
+			}).NetworkPolicy()
+			results = []*Result{
+				interpreter.ExecuteTestCase(generator.NewSingleStepTestCase("allow sctp to x/a", generator.NewStringSet(generator.TagDenyAll), generator.ProbeAllAvailable,
+					generator.CreatePolicy(allowSCTPToXA))),
+				interpreter.ExecuteTestCase(generator.NewSingleStepTestCase("no policies", generator.NewStringSet(generator.TagAllowAll), generator.ProbeAllAvailable)),
+			}
+		})
+
+		It("Should round-trip results, and rebuild policies", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "results.json")
+			Expect(WriteResultsFile(path, results)).To(Succeed())
+			read, err := ReadResultsFile(path)
+			Expect(err).To(Succeed())
+
+			Expect(read).To(HaveLen(2))
+			for i, result := range read {
+				Expect(result.Err).To(BeNil())
+				Expect(result.TestCase).To(Equal(results[i].TestCase))
+				Expect(result.Passed(false)).To(Equal(results[i].Passed(false)))
+				Expect(result.Steps).To(HaveLen(1))
+
+				step, original := result.Steps[0], results[i].Steps[0]
+				Expect(step.Policy).ToNot(BeNil())
+				Expect(step.KubeProbes).To(HaveLen(len(original.KubeProbes)))
+				Expect(step.SimulatedProbe.RenderTable()).To(Equal(original.SimulatedProbe.RenderTable()))
+				Expect(step.SimulatedProbe.RenderIngress()).To(Equal(original.SimulatedProbe.RenderIngress()))
+				Expect(step.LastKubeProbe().RenderTable()).To(Equal(original.LastKubeProbe().RenderTable()))
+				Expect(step.LastComparison().ValueCounts(false)).To(Equal(original.LastComparison().ValueCounts(false)))
+			}
+			// the mock kube allows everything, which the policy doesn't
+			simulated := read[0].Steps[0].SimulatedProbe.Get("y/a", "x/a").JobResults["TCP/80"]
+			Expect(simulated.Combined).To(Equal(probe.ConnectivityBlocked))
+			Expect(simulated.IngressEffect).To(Equal(&matcher.Effect{PolicyKind: matcher.NetworkPolicyV1, Verdict: matcher.None, PolicyName: "x/allow-sctp"}))
+			Expect(simulated.EgressEffect).To(BeNil())
+			Expect(read[0].Passed(false)).To(BeFalse())
+		})
+
+		It("Should round-trip results with invalid policies, simulating the valid ones", func() {
+			kubernetes := kube.NewMockKubernetes(1)
+			resources, err := probe.NewDefaultResources(kubernetes, []string{"x", "y"}, []string{"a", "b"}, []int{80}, []v1.Protocol{v1.ProtocolTCP}, nil, 5, false, "registry.k8s.io")
+			utils.DoOrDie(err)
+			interpreter := NewInterpreter(kubernetes, resources, &InterpreterConfig{ResetClusterBeforeTestCase: true, VerifyClusterStateBeforeTestCase: true})
+
+			invalid := (&generator.Netpol{
+				Name:    "invalid-cidr",
+				Target:  generator.NewNetpolTarget("y", nil, nil),
+				Ingress: &generator.NetpolPeers{Rules: []*generator.Rule{{Peers: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "not-a-cidr"}}}}}},
+			}).NetworkPolicy()
+			denyAllToXA := (&generator.Netpol{
+				Name:    "deny-all",
+				Target:  generator.NewNetpolTarget("x", map[string]string{"pod": "a"}, nil),
+				Ingress: &generator.NetpolPeers{Rules: []*generator.Rule{}},
+			}).NetworkPolicy()
+			withInvalid := []*Result{interpreter.ExecuteTestCase(generator.NewSingleStepTestCase("invalid policy", generator.NewStringSet(generator.TagDenyAll), generator.ProbeAllAvailable,
+				generator.CreatePolicy(invalid), generator.CreatePolicy(denyAllToXA)))}
+			Expect(withInvalid[0].Err).To(BeNil())
+			Expect(withInvalid[0].Steps).To(HaveLen(1))
+
+			path := filepath.Join(GinkgoT().TempDir(), "results.json")
+			Expect(WriteResultsFile(path, withInvalid)).To(Succeed())
+			read, err := ReadResultsFile(path)
+			Expect(err).To(Succeed())
+			Expect(read).To(HaveLen(1))
+			step, original := read[0].Steps[0], withInvalid[0].Steps[0]
+			Expect(step.Policy).ToNot(BeNil())
+			Expect(step.KubePolicies).To(HaveLen(2))
+			_, err = matcher.BuildV1AndV2NetPols(true, step.KubePolicies, nil, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(step.SimulatedProbe.RenderTable()).To(Equal(original.SimulatedProbe.RenderTable()))
+			Expect(step.Policy.ExplainTable()).To(Equal(original.Policy.ExplainTable()))
+		})
+
+		It("Should explain mismatches with the effects which decided the simulated results", func() {
+			mismatches := results[0].Steps[0].LastComparison().Mismatches(false)
+			// y/a, y/b and x/b to x/a, each on 2 ports and 2 protocols, plus loopback
+			Expect(mismatches).To(HaveLen(16))
+			Expect(mismatches).To(ContainElement("y/a -> x/a TCP/80: kube ., simulated X (ingress: [NPv1] x/allow-sctp: None; egress: no policies)"))
+			Expect(results[0].Steps[0].LastComparison().Mismatches(true)).To(HaveLen(12))
+			Expect(results[1].Steps[0].LastComparison().Mismatches(false)).To(BeEmpty())
+		})
+
+		It("Should diff runs cell by cell, pairing test cases by description", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "results.json")
+			Expect(WriteResultsFile(path, results)).To(Succeed())
+			after, err := ReadResultsFile(path)
+			Expect(err).To(Succeed())
+			*after[1].Steps[0].LastKubeProbe().Get("y/b", "x/a").JobResults["UDP/81"] = probe.JobResult{
+				Job:      &probe.Job{ResolvedPort: 81, Protocol: v1.ProtocolUDP},
+				Combined: probe.ConnectivityBlocked,
+			}
+			after = append(after, &Result{TestCase: generator.NewTestCase("only after", generator.NewStringSet()), Steps: after[0].Steps})
+
+			diff := DiffResults("before", results, "after", after)
+			Expect(diff.Pairs).To(HaveLen(2))
+			Expect(diff.Pairs[0].DisagreementCount(0, false)).To(Equal(0))
+			Expect(diff.Pairs[1].TestCase.Description).To(Equal("no policies"))
+			Expect(diff.Pairs[1].DisagreementCount(0, false)).To(Equal(1))
+			Expect(diff.Pairs[1].Disagreements(0, false).Get("y/b", "x/a")).To(Equal([]string{"UDP/81"}))
+			Expect(diff.Unpaired).To(HaveLen(1))
+			Expect(diff.Unpaired[0].Description).To(Equal("only after"))
+		})
+	})
+}
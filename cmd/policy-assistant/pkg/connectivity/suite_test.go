@@ -11,5 +11,8 @@ func TestConnectivity(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunTestCaseStateTests()
 	RunPrinterTests()
+	RunMultipleContextTesterTests()
+	RunResultsFileTests()
+	RunHTMLReportTests()
 	RunSpecs(t, "connectivity suite")
 }
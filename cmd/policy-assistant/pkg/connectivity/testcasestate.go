@@ -7,14 +7,19 @@ import (
 	"github.com/mattfenwick/cyclonus/pkg/kube"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
 )
 
 type TestCaseState struct {
 	Kubernetes kube.IKubernetes
 	Resources  *probe.Resources
 	Policies   []*networkingv1.NetworkPolicy
+	ANPs       []*v1alpha1.AdminNetworkPolicy
+	BANP       *v1alpha1.BaselineAdminNetworkPolicy
 }
 
 func (t *TestCaseState) CreatePolicy(policy *networkingv1.NetworkPolicy) error {
@@ -50,6 +55,87 @@ func (t *TestCaseState) UpdatePolicy(policy *networkingv1.NetworkPolicy) error {
 	return err
 }
 
+func (t *TestCaseState) CreateANP(policy *v1alpha1.AdminNetworkPolicy) error {
+	for _, anp := range t.ANPs {
+		if anp.Name == policy.Name {
+			return errors.Errorf("cannot create admin network policy %s: already exists", policy.Name)
+		}
+	}
+	t.ANPs = append(t.ANPs, policy)
+
+	kubePolicy := policy.DeepCopy()
+	kubePolicy.Labels = withManagedByLabel(policy.Labels)
+	_, err := t.Kubernetes.CreateAdminNetworkPolicy(kubePolicy)
+	return err
+}
+
+func (t *TestCaseState) UpdateANP(policy *v1alpha1.AdminNetworkPolicy) error {
+	index := slices.IndexFunc(t.ANPs, func(anp *v1alpha1.AdminNetworkPolicy) bool { return anp.Name == policy.Name })
+	if index == -1 {
+		return errors.Errorf("cannot update admin network policy %s: not found", policy.Name)
+	}
+
+	t.ANPs[index] = policy
+	kubePolicy := policy.DeepCopy()
+	kubePolicy.Labels = withManagedByLabel(policy.Labels)
+	_, err := t.Kubernetes.UpdateAdminNetworkPolicy(kubePolicy)
+	return err
+}
+
+func (t *TestCaseState) DeleteANP(name string) error {
+	index := slices.IndexFunc(t.ANPs, func(anp *v1alpha1.AdminNetworkPolicy) bool { return anp.Name == name })
+	if index == -1 {
+		return errors.Errorf("cannot delete admin network policy %s: not found", name)
+	}
+
+	t.ANPs = slices.Delete(append([]*v1alpha1.AdminNetworkPolicy{}, t.ANPs...), index, index+1)
+	return t.Kubernetes.DeleteAdminNetworkPolicy(name)
+}
+
+func (t *TestCaseState) CreateBANP(policy *v1alpha1.BaselineAdminNetworkPolicy) error {
+	if t.BANP != nil {
+		return errors.Errorf("cannot create baseline admin network policy %s: %s already exists", policy.Name, t.BANP.Name)
+	}
+	t.BANP = policy
+
+	kubePolicy := policy.DeepCopy()
+	kubePolicy.Labels = withManagedByLabel(policy.Labels)
+	_, err := t.Kubernetes.CreateBaselineAdminNetworkPolicy(kubePolicy)
+	return err
+}
+
+func (t *TestCaseState) UpdateBANP(policy *v1alpha1.BaselineAdminNetworkPolicy) error {
+	if t.BANP == nil || t.BANP.Name != policy.Name {
+		return errors.Errorf("cannot update baseline admin network policy %s: not found", policy.Name)
+	}
+
+	t.BANP = policy
+	kubePolicy := policy.DeepCopy()
+	kubePolicy.Labels = withManagedByLabel(policy.Labels)
+	_, err := t.Kubernetes.UpdateBaselineAdminNetworkPolicy(kubePolicy)
+	return err
+}
+
+func (t *TestCaseState) DeleteBANP(name string) error {
+	if t.BANP == nil || t.BANP.Name != name {
+		return errors.Errorf("cannot delete baseline admin network policy %s: not found", name)
+	}
+
+	t.BANP = nil
+	return t.Kubernetes.DeleteBaselineAdminNetworkPolicy(name)
+}
+
+// withManagedByLabel returns a copy of labels, plus the label which ResetClusterState uses to find the
+// admin policies to delete
+func withManagedByLabel(labels map[string]string) map[string]string {
+	labeled := map[string]string{}
+	for k, v := range labels {
+		labeled[k] = v
+	}
+	labeled[kube.ManagedByLabel] = kube.ManagedByValue
+	return labeled
+}
+
 func (t *TestCaseState) CreateNamespace(ns string, labels map[string]string) error {
 	newResources, err := t.Resources.CreateNamespace(ns, labels)
 	if err != nil {
@@ -313,9 +399,57 @@ func (t *TestCaseState) ResetClusterState() error {
 		return err
 	}
 
+	err = t.deleteManagedAdminPolicies()
+	if err != nil {
+		return err
+	}
+
 	return t.resetLabelsInKubeHelper()
 }
 
+// deleteManagedAdminPolicies deletes the ANPs and BANPs created by earlier test cases.  ANPs and BANPs are
+// cluster-scoped, so only those with cyclonus's label are deleted.
+func (t *TestCaseState) deleteManagedAdminPolicies() error {
+	anps, banps, err := getAdminPolicies(t.Kubernetes)
+	if err != nil {
+		return err
+	}
+	for _, anp := range anps {
+		if anp.Labels[kube.ManagedByLabel] == kube.ManagedByValue {
+			if err := t.Kubernetes.DeleteAdminNetworkPolicy(anp.Name); err != nil {
+				return err
+			}
+		}
+	}
+	for _, banp := range banps {
+		if banp.Labels[kube.ManagedByLabel] == kube.ManagedByValue {
+			if err := t.Kubernetes.DeleteBaselineAdminNetworkPolicy(banp.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getAdminPolicies returns all ANPs and BANPs, or none if their CRDs aren't installed
+func getAdminPolicies(kubernetes kube.IKubernetes) ([]v1alpha1.AdminNetworkPolicy, []v1alpha1.BaselineAdminNetworkPolicy, error) {
+	anps, err := kubernetes.GetAdminNetworkPolicies()
+	if kerrors.IsNotFound(err) {
+		logrus.Debugf("admin network policy CRD not found: %+v", err)
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	banps, err := kubernetes.GetBaselineAdminNetworkPolicies()
+	if kerrors.IsNotFound(err) {
+		logrus.Debugf("baseline admin network policy CRD not found: %+v", err)
+		return anps, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	return anps, banps, nil
+}
+
 func (t *TestCaseState) VerifyClusterState() error {
 	err := t.verifyClusterStateHelper()
 	if err != nil {
@@ -329,5 +463,17 @@ func (t *TestCaseState) VerifyClusterState() error {
 	if len(policies) > 0 {
 		return errors.Errorf("expected 0 policies in namespaces %+v, found %d", t.Resources.NamespacesSlice(), len(policies))
 	}
+
+	// admin policies are cluster-scoped, so any left at this point weren't created by cyclonus
+	anps, banps, err := getAdminPolicies(t.Kubernetes)
+	if err != nil {
+		return err
+	}
+	for _, anp := range anps {
+		logrus.Warnf("found admin network policy %s, which isn't simulated; results may be wrong", anp.Name)
+	}
+	for _, banp := range banps {
+		logrus.Warnf("found baseline admin network policy %s, which isn't simulated; results may be wrong", banp.Name)
+	}
 	return nil
 }
@@ -2,8 +2,17 @@ package connectivity
 
 import (
 	"fmt"
+
+	"github.com/mattfenwick/cyclonus/pkg/connectivity/probe"
+	"github.com/mattfenwick/cyclonus/pkg/generator"
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
 )
 
 type buildLabelDiffCase struct {
@@ -103,4 +112,65 @@ func RunTestCaseStateTests() {
 			}
 		})
 	})
+
+	Describe("Admin network policies", func() {
+		var kubernetes *kube.MockKubernetes
+		var interpreter *Interpreter
+		BeforeEach(func() {
+			kubernetes = kube.NewMockKubernetes(1)
+			resources, err := probe.NewDefaultResources(kubernetes, []string{"x", "y", "z"}, []string{"a"}, []int{80}, []v1.Protocol{v1.ProtocolTCP}, nil, 5, false, "registry.k8s.io")
+			utils.DoOrDie(err)
+			interpreter = NewInterpreter(kubernetes, resources, &InterpreterConfig{ResetClusterBeforeTestCase: true, VerifyClusterStateBeforeTestCase: true})
+		})
+
+		It("Should simulate ANP, then network policy, then BANP", func() {
+			passFromAll := generator.ANP("pass-from-all", 10, []v1alpha1.AdminNetworkPolicyIngressRule{{
+				Name: "pass", Action: v1alpha1.AdminNetworkPolicyRuleActionPass,
+				From: []v1alpha1.AdminNetworkPolicyIngressPeer{{Namespaces: &metav1.LabelSelector{}}},
+			}}, nil)
+			denyFromAll := generator.BANP([]v1alpha1.BaselineAdminNetworkPolicyIngressRule{{
+				Name: "deny", Action: v1alpha1.BaselineAdminNetworkPolicyRuleActionDeny,
+				From: []v1alpha1.AdminNetworkPolicyIngressPeer{{Namespaces: &metav1.LabelSelector{}}},
+			}})
+			allowFromY := (&generator.Netpol{
+				Name:   "allow-from-y",
+				Target: generator.NewNetpolTarget("x", map[string]string{"pod": "a"}, nil),
+				Ingress: &generator.NetpolPeers{Rules: []*generator.Rule{{Peers: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"ns": "y"}},
+				}}}}},
+			}).NetworkPolicy()
+
+			result := interpreter.ExecuteTestCase(generator.NewTestCase("admin precedence", generator.NewStringSet(),
+				generator.NewTestStep(generator.ProbeAllAvailable, generator.CreateANP(passFromAll), generator.CreateBANP(denyFromAll)),
+				generator.NewTestStep(generator.ProbeAllAvailable, generator.CreatePolicy(allowFromY))))
+			Expect(result.Err).To(BeNil())
+
+			// the ANP passes to the BANP, which denies
+			before := result.Steps[0].SimulatedProbe
+			Expect(before.Get("y/a", "x/a").JobResults["TCP/80"].Combined).To(Equal(probe.ConnectivityBlocked))
+			Expect(before.Get("x/a", "y/a").JobResults["TCP/80"].Combined).To(Equal(probe.ConnectivityAllowed))
+			// the network policy takes precedence over the BANP
+			after := result.Steps[1].SimulatedProbe
+			Expect(after.Get("y/a", "x/a").JobResults["TCP/80"].Combined).To(Equal(probe.ConnectivityAllowed))
+			Expect(after.Get("z/a", "x/a").JobResults["TCP/80"].Combined).To(Equal(probe.ConnectivityBlocked))
+
+			Expect(result.Steps[1].ANPs).To(Equal([]*v1alpha1.AdminNetworkPolicy{passFromAll}))
+			Expect(result.Steps[1].BANP).To(Equal(denyFromAll))
+			Expect(kubernetes.ANPs["pass-from-all"].Labels).To(Equal(map[string]string{kube.ManagedByLabel: kube.ManagedByValue}))
+		})
+
+		It("Should only clean up admin policies created by cyclonus", func() {
+			_, err := kubernetes.CreateAdminNetworkPolicy(generator.ANP("not-managed", 1, nil, nil))
+			utils.DoOrDie(err)
+			result := interpreter.ExecuteTestCase(generator.NewSingleStepTestCase("", generator.NewStringSet(), generator.ProbeAllAvailable,
+				generator.CreateANP(generator.ANP("managed", 2, nil, nil))))
+			Expect(result.Err).To(BeNil())
+			Expect(kubernetes.ANPs).To(HaveLen(2))
+
+			state := &TestCaseState{Kubernetes: kubernetes, Resources: result.InitialResources}
+			Expect(state.ResetClusterState()).To(Succeed())
+			Expect(kubernetes.ANPs).To(HaveKey("not-managed"))
+			Expect(kubernetes.ANPs).To(HaveLen(1))
+		})
+	})
 }
@@ -1,6 +1,9 @@
 package generator
 
-import networkingv1 "k8s.io/api/networking/v1"
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
+)
 
 // Action models a sum type (discriminated union): exactly one field must be non-null.
 type Action struct {
@@ -8,6 +11,14 @@ type Action struct {
 	UpdatePolicy *UpdatePolicyAction
 	DeletePolicy *DeletePolicyAction
 
+	CreateANP *CreateANPAction
+	UpdateANP *UpdateANPAction
+	DeleteANP *DeleteANPAction
+
+	CreateBANP *CreateBANPAction
+	UpdateBANP *UpdateBANPAction
+	DeleteBANP *DeleteBANPAction
+
 	CreateNamespace    *CreateNamespaceAction
 	SetNamespaceLabels *SetNamespaceLabelsAction
 	DeleteNamespace    *DeleteNamespaceAction
@@ -44,6 +55,54 @@ func DeletePolicy(ns string, name string) *Action {
 	return &Action{DeletePolicy: &DeletePolicyAction{Namespace: ns, Name: name}}
 }
 
+type CreateANPAction struct {
+	Policy *v1alpha1.AdminNetworkPolicy
+}
+
+func CreateANP(policy *v1alpha1.AdminNetworkPolicy) *Action {
+	return &Action{CreateANP: &CreateANPAction{Policy: policy}}
+}
+
+type UpdateANPAction struct {
+	Policy *v1alpha1.AdminNetworkPolicy
+}
+
+func UpdateANP(policy *v1alpha1.AdminNetworkPolicy) *Action {
+	return &Action{UpdateANP: &UpdateANPAction{Policy: policy}}
+}
+
+type DeleteANPAction struct {
+	Name string
+}
+
+func DeleteANP(name string) *Action {
+	return &Action{DeleteANP: &DeleteANPAction{Name: name}}
+}
+
+type CreateBANPAction struct {
+	Policy *v1alpha1.BaselineAdminNetworkPolicy
+}
+
+func CreateBANP(policy *v1alpha1.BaselineAdminNetworkPolicy) *Action {
+	return &Action{CreateBANP: &CreateBANPAction{Policy: policy}}
+}
+
+type UpdateBANPAction struct {
+	Policy *v1alpha1.BaselineAdminNetworkPolicy
+}
+
+func UpdateBANP(policy *v1alpha1.BaselineAdminNetworkPolicy) *Action {
+	return &Action{UpdateBANP: &UpdateBANPAction{Policy: policy}}
+}
+
+type DeleteBANPAction struct {
+	Name string
+}
+
+func DeleteBANP(name string) *Action {
+	return &Action{DeleteBANP: &DeleteBANPAction{Name: name}}
+}
+
 type CreateNamespaceAction struct {
 	Namespace string
 	Labels    map[string]string
@@ -0,0 +1,166 @@
+package generator
+
+import (
+	. "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
+)
+
+/*
+Admin network policies are evaluated in tiers: ANPs first, in priority order, then v1 network policies
+(if an ANP passes or doesn't match), then the BANP.  These test cases all select pod x/a, so that pods
+outside the subject show that the other pods are unaffected.
+*/
+
+var (
+	adminSubjectXA = v1alpha1.AdminNetworkPolicySubject{
+		Pods: &v1alpha1.NamespacedPod{
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"ns": "x"}},
+			PodSelector:       metav1.LabelSelector{MatchLabels: map[string]string{"pod": "a"}},
+		},
+	}
+
+	adminPeerNamespaceY = v1alpha1.AdminNetworkPolicyIngressPeer{
+		Namespaces: &metav1.LabelSelector{MatchLabels: map[string]string{"ns": "y"}},
+	}
+	adminPeerAllNamespaces = v1alpha1.AdminNetworkPolicyIngressPeer{
+		Namespaces: &metav1.LabelSelector{},
+	}
+)
+
+// ANP builds an AdminNetworkPolicy selecting pod x/a
+func ANP(name string, priority int32, ingress []v1alpha1.AdminNetworkPolicyIngressRule, egress []v1alpha1.AdminNetworkPolicyEgressRule) *v1alpha1.AdminNetworkPolicy {
+	return &v1alpha1.AdminNetworkPolicy{
+		TypeMeta:   metav1.TypeMeta{Kind: "AdminNetworkPolicy", APIVersion: v1alpha1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.AdminNetworkPolicySpec{
+			Priority: priority,
+			Subject:  adminSubjectXA,
+			Ingress:  ingress,
+			Egress:   egress,
+		},
+	}
+}
+
+// BANP builds the BaselineAdminNetworkPolicy selecting pod x/a; a BANP must be named 'default'
+func BANP(ingress []v1alpha1.BaselineAdminNetworkPolicyIngressRule) *v1alpha1.BaselineAdminNetworkPolicy {
+	return &v1alpha1.BaselineAdminNetworkPolicy{
+		TypeMeta:   metav1.TypeMeta{Kind: "BaselineAdminNetworkPolicy", APIVersion: v1alpha1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: v1alpha1.BaselineAdminNetworkPolicySpec{
+			Subject: adminSubjectXA,
+			Ingress: ingress,
+		},
+	}
+}
+
+func anpIngress(name string, action v1alpha1.AdminNetworkPolicyRuleAction, peer v1alpha1.AdminNetworkPolicyIngressPeer) []v1alpha1.AdminNetworkPolicyIngressRule {
+	return []v1alpha1.AdminNetworkPolicyIngressRule{{Name: name, Action: action, From: []v1alpha1.AdminNetworkPolicyIngressPeer{peer}}}
+}
+
+func banpIngress(name string, action v1alpha1.BaselineAdminNetworkPolicyRuleAction, peer v1alpha1.AdminNetworkPolicyIngressPeer) []v1alpha1.BaselineAdminNetworkPolicyIngressRule {
+	return []v1alpha1.BaselineAdminNetworkPolicyIngressRule{{Name: name, Action: action, From: []v1alpha1.AdminNetworkPolicyIngressPeer{peer}}}
+}
+
+// allowIngressFromNamespaceZ has a rule -- rather than none, as in a deny-all -- so that it denies
+// ingress to x/a from everywhere but namespace z
+func allowIngressFromNamespaceZ() *NetworkPolicy {
+	return (&Netpol{
+		Name:   "allow-ingress-from-z",
+		Target: NewNetpolTarget("x", map[string]string{"pod": "a"}, nil),
+		Ingress: &NetpolPeers{Rules: []*Rule{{
+			Peers: []NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"ns": "z"}}}},
+		}}},
+	}).NetworkPolicy()
+}
+
+func allowAllIngressToXA() *NetworkPolicy {
+	return (&Netpol{
+		Name:    "allow-all-ingress",
+		Target:  NewNetpolTarget("x", map[string]string{"pod": "a"}, nil),
+		Ingress: &NetpolPeers{Rules: AllowAllRules},
+	}).NetworkPolicy()
+}
+
+func (t *TestCaseGenerator) AdminNetworkPolicyTestCases() []*TestCase {
+	allow, deny, pass := v1alpha1.AdminNetworkPolicyRuleActionAllow, v1alpha1.AdminNetworkPolicyRuleActionDeny, v1alpha1.AdminNetworkPolicyRuleActionPass
+	baselineAllow, baselineDeny := v1alpha1.BaselineAdminNetworkPolicyRuleActionAllow, v1alpha1.BaselineAdminNetworkPolicyRuleActionDeny
+
+	return []*TestCase{
+		NewSingleStepTestCase("ANP denies ingress from namespace y",
+			NewStringSet(TagANP, TagAdminDeny, TagIngress, TagCreateANP),
+			ProbeAllAvailable,
+			CreateANP(ANP("deny-from-y", 10, anpIngress("deny-from-y", deny, adminPeerNamespaceY), nil))),
+		NewSingleStepTestCase("ANP denies egress to namespace y",
+			NewStringSet(TagANP, TagAdminDeny, TagEgress, TagCreateANP),
+			ProbeAllAvailable,
+			CreateANP(ANP("deny-to-y", 10, nil, []v1alpha1.AdminNetworkPolicyEgressRule{{
+				Name:   "deny-to-y",
+				Action: deny,
+				To:     []v1alpha1.AdminNetworkPolicyEgressPeer{{Namespaces: adminPeerNamespaceY.Namespaces}},
+			}}))),
+		NewSingleStepTestCase("BANP denies ingress from namespace y",
+			NewStringSet(TagBANP, TagAdminDeny, TagIngress, TagCreateBANP),
+			ProbeAllAvailable,
+			CreateBANP(BANP(banpIngress("deny-from-y", baselineDeny, adminPeerNamespaceY)))),
+
+		// precedence: ANP, then v1 network policies, then BANP
+		NewSingleStepTestCase("ANP allow overrides network policy",
+			NewStringSet(TagANP, TagAdminAllow, TagAdminPrecedence, TagIngress, TagCreateANP, TagCreatePolicy),
+			ProbeAllAvailable,
+			CreatePolicy(allowIngressFromNamespaceZ()),
+			CreateANP(ANP("allow-from-y", 10, anpIngress("allow-from-y", allow, adminPeerNamespaceY), nil))),
+		NewSingleStepTestCase("ANP deny overrides network policy",
+			NewStringSet(TagANP, TagAdminDeny, TagAdminPrecedence, TagIngress, TagCreateANP, TagCreatePolicy),
+			ProbeAllAvailable,
+			CreatePolicy(allowAllIngressToXA()),
+			CreateANP(ANP("deny-from-y", 10, anpIngress("deny-from-y", deny, adminPeerNamespaceY), nil))),
+		NewSingleStepTestCase("ANP pass defers to network policy, which overrides BANP",
+			NewStringSet(TagANP, TagBANP, TagAdminPass, TagAdminAllow, TagAdminPrecedence, TagIngress, TagCreateANP, TagCreateBANP, TagCreatePolicy),
+			ProbeAllAvailable,
+			CreatePolicy(allowIngressFromNamespaceZ()),
+			CreateANP(ANP("pass-from-all", 10, anpIngress("pass-from-all", pass, adminPeerAllNamespaces), nil)),
+			CreateBANP(BANP(banpIngress("allow-from-all", baselineAllow, adminPeerAllNamespaces)))),
+		NewSingleStepTestCase("ANP pass defers to BANP",
+			NewStringSet(TagANP, TagBANP, TagAdminPass, TagAdminDeny, TagAdminPrecedence, TagIngress, TagCreateANP, TagCreateBANP),
+			ProbeAllAvailable,
+			CreateANP(ANP("pass-from-y", 10, anpIngress("pass-from-y", pass, adminPeerNamespaceY), nil)),
+			CreateBANP(BANP(banpIngress("deny-from-all", baselineDeny, adminPeerAllNamespaces)))),
+
+		{
+			Description: "ANP with the lowest priority number wins, and changing priorities changes the winner",
+			Tags:        NewStringSet(TagANP, TagANPPriority, TagAdminAllow, TagAdminDeny, TagIngress, TagCreateANP, TagUpdateANP),
+			Steps: []*TestStep{
+				NewTestStep(ProbeAllAvailable,
+					CreateANP(ANP("allow-from-y", 10, anpIngress("allow-from-y", allow, adminPeerNamespaceY), nil)),
+					CreateANP(ANP("deny-from-all", 20, anpIngress("deny-from-all", deny, adminPeerAllNamespaces), nil))),
+				NewTestStep(ProbeAllAvailable,
+					UpdateANP(ANP("allow-from-y", 30, anpIngress("allow-from-y", allow, adminPeerNamespaceY), nil))),
+			},
+		},
+		{
+			Description: "Create/update/delete ANP",
+			Tags:        NewStringSet(TagANP, TagAdminDeny, TagAdminPass, TagIngress, TagCreateANP, TagUpdateANP, TagDeleteANP),
+			Steps: []*TestStep{
+				NewTestStep(ProbeAllAvailable,
+					CreateANP(ANP("from-y", 10, anpIngress("from-y", deny, adminPeerNamespaceY), nil))),
+				NewTestStep(ProbeAllAvailable,
+					UpdateANP(ANP("from-y", 10, anpIngress("from-y", pass, adminPeerNamespaceY), nil))),
+				NewTestStep(ProbeAllAvailable, DeleteANP("from-y")),
+			},
+		},
+		{
+			Description: "Create/update/delete BANP",
+			Tags:        NewStringSet(TagBANP, TagAdminDeny, TagAdminAllow, TagIngress, TagCreateBANP, TagUpdateBANP, TagDeleteBANP),
+			Steps: []*TestStep{
+				NewTestStep(ProbeAllAvailable,
+					CreateBANP(BANP(banpIngress("from-all", baselineDeny, adminPeerAllNamespaces)))),
+				NewTestStep(ProbeAllAvailable,
+					UpdateBANP(BANP(append(
+						banpIngress("allow-from-y", baselineAllow, adminPeerNamespaceY),
+						banpIngress("from-all", baselineDeny, adminPeerAllNamespaces)...)))),
+				NewTestStep(ProbeAllAvailable, DeleteBANP("default")),
+			},
+		},
+	}
+}
@@ -11,6 +11,13 @@ const (
 	ActionFeatureUpdatePolicy = "action: update policy"
 	ActionFeatureDeletePolicy = "action: delete policy"
 
+	ActionFeatureCreateANP  = "action: create admin network policy"
+	ActionFeatureUpdateANP  = "action: update admin network policy"
+	ActionFeatureDeleteANP  = "action: delete admin network policy"
+	ActionFeatureCreateBANP = "action: create baseline admin network policy"
+	ActionFeatureUpdateBANP = "action: update baseline admin network policy"
+	ActionFeatureDeleteBANP = "action: delete baseline admin network policy"
+
 	ActionFeatureCreateNamespace    = "action: create namespace"
 	ActionFeatureSetNamespaceLabels = "action: set namespace labels"
 	ActionFeatureDeleteNamespace    = "action: delete namespace"
@@ -0,0 +1,324 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	. "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
+)
+
+// fuzzLabelKey is the label which fuzzed label changes set, and which fuzzed selectors may select on
+const fuzzLabelKey = "fuzz"
+
+var fuzzLabelValues = []string{"a", "b"}
+
+// MaxFuzzANPs is the most ANPs a test case can have, since each has a distinct priority from 0 to 1000
+const MaxFuzzANPs = 1001
+
+// FuzzConfig describes the pods which fuzzed test cases run against, and bounds what the test cases create
+type FuzzConfig struct {
+	Namespaces         []string
+	Pods               []string
+	Ports              []int
+	Protocols          []v1.Protocol
+	MaxNetworkPolicies int
+	MaxANPs            int
+	// AdminPolicies enables ANPs and the BANP, which require the CRDs to be installed
+	AdminPolicies bool
+	// LabelChanges enables a second step, which changes pod and namespace labels and probes again
+	LabelChanges bool
+}
+
+// Fuzzer generates random test cases.  The same seed and config always generate the same test case, so
+// that a failure can be reproduced from its seed.
+type Fuzzer struct {
+	Config *FuzzConfig
+}
+
+func NewFuzzer(config *FuzzConfig) *Fuzzer {
+	return &Fuzzer{Config: config}
+}
+
+// fuzzState holds the random source for a single test case
+type fuzzState struct {
+	*rand.Rand
+	Config *FuzzConfig
+	Tags   StringSet
+}
+
+func (f *Fuzzer) TestCase(seed int64) *TestCase {
+	s := &fuzzState{
+		Rand:   rand.New(rand.NewSource(seed)),
+		Config: f.Config,
+		Tags:   NewStringSet(TagFuzz),
+	}
+
+	// probe by pod IP, so that egress policies don't need to allow DNS
+	probe := NewAllAvailable(ProbeModePodIP)
+
+	var actions []*Action
+	for i := 0; i < s.count(1, f.Config.MaxNetworkPolicies); i++ {
+		actions = append(actions, CreatePolicy(s.networkPolicy(fmt.Sprintf("fuzz-%d", i))))
+		s.Tags.Add(TagCreatePolicy)
+	}
+	if f.Config.AdminPolicies {
+		// ANPs with the same priority have undefined precedence, so priorities are distinct
+		priorities := s.Perm(max(100, f.Config.MaxANPs))
+		for i := 0; i < s.count(0, f.Config.MaxANPs); i++ {
+			actions = append(actions, CreateANP(s.anp(fmt.Sprintf("fuzz-%d", i), int32(priorities[i]))))
+			s.Tags.Add(TagCreateANP)
+		}
+		if s.Intn(2) == 0 {
+			actions = append(actions, CreateBANP(s.banp()))
+			s.Tags.Add(TagCreateBANP)
+		}
+	}
+	steps := []*TestStep{NewTestStep(probe, actions...)}
+
+	if f.Config.LabelChanges {
+		var labelActions []*Action
+		for i := 0; i < s.count(1, 2); i++ {
+			labelActions = append(labelActions, s.labelChange())
+		}
+		steps = append(steps, NewTestStep(probe, labelActions...))
+	}
+
+	return NewTestCase(fmt.Sprintf("fuzz seed %d", seed), s.Tags, steps...)
+}
+
+// count returns a number between min and max, inclusive
+func (s *fuzzState) count(min int, max int) int {
+	if max <= min {
+		return min
+	}
+	return min + s.Intn(max-min+1)
+}
+
+func (s *fuzzState) pick(values []string) string {
+	return values[s.Intn(len(values))]
+}
+
+func (s *fuzzState) subset(values []string) []string {
+	var picked []string
+	for _, i := range s.Perm(len(values))[:1+s.Intn(len(values))] {
+		picked = append(picked, values[i])
+	}
+	return picked
+}
+
+// selector selects on key, whose values are one of values -- or on the fuzz label, if labels may change
+func (s *fuzzState) selector(key string, values []string) *metav1.LabelSelector {
+	choices := 4
+	if s.Config.LabelChanges {
+		choices++
+	}
+	switch s.Intn(choices) {
+	case 0:
+		return &metav1.LabelSelector{}
+	case 1:
+		return &metav1.LabelSelector{MatchLabels: map[string]string{key: s.pick(values)}}
+	case 2:
+		return &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: key, Operator: metav1.LabelSelectorOpIn, Values: s.subset(values)}}}
+	case 3:
+		return &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: key, Operator: metav1.LabelSelectorOpNotIn, Values: []string{s.pick(values)}}}}
+	default:
+		return &metav1.LabelSelector{MatchLabels: map[string]string{fuzzLabelKey: s.pick(fuzzLabelValues)}}
+	}
+}
+
+func (s *fuzzState) podSelector() *metav1.LabelSelector {
+	return s.selector("pod", s.Config.Pods)
+}
+
+func (s *fuzzState) namespaceSelector() *metav1.LabelSelector {
+	return s.selector("ns", s.Config.Namespaces)
+}
+
+func (s *fuzzState) networkPolicy(name string) *NetworkPolicy {
+	netpol := &Netpol{
+		Name:   name,
+		Target: &NetpolTarget{Namespace: s.pick(s.Config.Namespaces), PodSelector: *s.podSelector()},
+	}
+	switch s.Intn(3) {
+	case 0:
+		netpol.Ingress = s.netpolPeers()
+		s.Tags.Add(TagIngress)
+	case 1:
+		netpol.Egress = s.netpolPeers()
+		s.Tags.Add(TagEgress)
+	default:
+		netpol.Ingress, netpol.Egress = s.netpolPeers(), s.netpolPeers()
+		s.Tags.Add(TagIngress)
+		s.Tags.Add(TagEgress)
+	}
+	return netpol.NetworkPolicy()
+}
+
+// netpolPeers may have no rules, which denies all traffic in its direction
+func (s *fuzzState) netpolPeers() *NetpolPeers {
+	peers := &NetpolPeers{Rules: []*Rule{}}
+	for i := 0; i < s.count(0, 2); i++ {
+		rule := &Rule{}
+		for j := 0; j < s.count(0, 2); j++ {
+			rule.Peers = append(rule.Peers, s.netpolPeer())
+		}
+		for j := 0; j < s.count(0, 2); j++ {
+			rule.Ports = append(rule.Ports, s.netpolPort())
+		}
+		peers.Rules = append(peers.Rules, rule)
+	}
+	return peers
+}
+
+func (s *fuzzState) netpolPeer() NetworkPolicyPeer {
+	switch s.Intn(4) {
+	case 0:
+		s.Tags.Add(TagPodsByLabel)
+		return NetworkPolicyPeer{PodSelector: s.podSelector()}
+	case 1:
+		s.Tags.Add(TagNamespacesByLabel)
+		return NetworkPolicyPeer{NamespaceSelector: s.namespaceSelector()}
+	case 2:
+		s.Tags.Add(TagPodsByLabel)
+		s.Tags.Add(TagNamespacesByLabel)
+		return NetworkPolicyPeer{PodSelector: s.podSelector(), NamespaceSelector: s.namespaceSelector()}
+	default:
+		s.Tags.Add(TagIPBlockNoExcept)
+		return NetworkPolicyPeer{IPBlock: &IPBlock{CIDR: "0.0.0.0/0"}}
+	}
+}
+
+func (s *fuzzState) protocol() v1.Protocol {
+	return s.Config.Protocols[s.Intn(len(s.Config.Protocols))]
+}
+
+func (s *fuzzState) netpolPort() NetworkPolicyPort {
+	protocol := s.protocol()
+	port := s.Config.Ports[s.Intn(len(s.Config.Ports))]
+	switch s.Intn(3) {
+	case 0:
+		s.Tags.Add(TagAnyPort)
+		return NetworkPolicyPort{Protocol: &protocol}
+	case 1:
+		s.Tags.Add(TagNumberedPort)
+		number := intstr.FromInt(port)
+		return NetworkPolicyPort{Protocol: &protocol, Port: &number}
+	default:
+		s.Tags.Add(TagNamedPort)
+		name := intstr.FromString(fmt.Sprintf("serve-%d-%s", port, strings.ToLower(string(protocol))))
+		return NetworkPolicyPort{Protocol: &protocol, Port: &name}
+	}
+}
+
+func (s *fuzzState) adminSubject() v1alpha1.AdminNetworkPolicySubject {
+	if s.Intn(2) == 0 {
+		return v1alpha1.AdminNetworkPolicySubject{Namespaces: s.namespaceSelector()}
+	}
+	return v1alpha1.AdminNetworkPolicySubject{Pods: &v1alpha1.NamespacedPod{
+		NamespaceSelector: *s.namespaceSelector(),
+		PodSelector:       *s.podSelector(),
+	}}
+}
+
+func (s *fuzzState) adminPeer() v1alpha1.AdminNetworkPolicyIngressPeer {
+	if s.Intn(2) == 0 {
+		return v1alpha1.AdminNetworkPolicyIngressPeer{Namespaces: s.namespaceSelector()}
+	}
+	return v1alpha1.AdminNetworkPolicyIngressPeer{Pods: &v1alpha1.NamespacedPod{
+		NamespaceSelector: *s.namespaceSelector(),
+		PodSelector:       *s.podSelector(),
+	}}
+}
+
+func (s *fuzzState) adminPorts() *[]v1alpha1.AdminNetworkPolicyPort {
+	if s.Intn(2) == 0 {
+		return nil
+	}
+	return &[]v1alpha1.AdminNetworkPolicyPort{{PortNumber: &v1alpha1.Port{
+		Protocol: s.protocol(),
+		Port:     int32(s.Config.Ports[s.Intn(len(s.Config.Ports))]),
+	}}}
+}
+
+func (s *fuzzState) anpAction() v1alpha1.AdminNetworkPolicyRuleAction {
+	switch s.Intn(3) {
+	case 0:
+		s.Tags.Add(TagAdminAllow)
+		return v1alpha1.AdminNetworkPolicyRuleActionAllow
+	case 1:
+		s.Tags.Add(TagAdminDeny)
+		return v1alpha1.AdminNetworkPolicyRuleActionDeny
+	default:
+		s.Tags.Add(TagAdminPass)
+		return v1alpha1.AdminNetworkPolicyRuleActionPass
+	}
+}
+
+func (s *fuzzState) banpAction() v1alpha1.BaselineAdminNetworkPolicyRuleAction {
+	if s.Intn(2) == 0 {
+		s.Tags.Add(TagAdminAllow)
+		return v1alpha1.BaselineAdminNetworkPolicyRuleActionAllow
+	}
+	s.Tags.Add(TagAdminDeny)
+	return v1alpha1.BaselineAdminNetworkPolicyRuleActionDeny
+}
+
+func (s *fuzzState) anp(name string, priority int32) *v1alpha1.AdminNetworkPolicy {
+	s.Tags.Add(TagANP)
+	anp := ANP(name, priority, nil, nil)
+	anp.Spec.Subject = s.adminSubject()
+	for i := 0; i < s.count(1, 3); i++ {
+		ruleName := fmt.Sprintf("rule-%d", i)
+		peer := s.adminPeer()
+		if s.Intn(2) == 0 {
+			s.Tags.Add(TagIngress)
+			anp.Spec.Ingress = append(anp.Spec.Ingress, v1alpha1.AdminNetworkPolicyIngressRule{
+				Name: ruleName, Action: s.anpAction(), From: []v1alpha1.AdminNetworkPolicyIngressPeer{peer}, Ports: s.adminPorts()})
+		} else {
+			s.Tags.Add(TagEgress)
+			anp.Spec.Egress = append(anp.Spec.Egress, v1alpha1.AdminNetworkPolicyEgressRule{
+				Name: ruleName, Action: s.anpAction(), To: []v1alpha1.AdminNetworkPolicyEgressPeer{{Namespaces: peer.Namespaces, Pods: peer.Pods}}, Ports: s.adminPorts()})
+		}
+	}
+	return anp
+}
+
+func (s *fuzzState) banp() *v1alpha1.BaselineAdminNetworkPolicy {
+	s.Tags.Add(TagBANP)
+	banp := BANP(nil)
+	banp.Spec.Subject = s.adminSubject()
+	for i := 0; i < s.count(1, 3); i++ {
+		ruleName := fmt.Sprintf("rule-%d", i)
+		peer := s.adminPeer()
+		if s.Intn(2) == 0 {
+			s.Tags.Add(TagIngress)
+			banp.Spec.Ingress = append(banp.Spec.Ingress, v1alpha1.BaselineAdminNetworkPolicyIngressRule{
+				Name: ruleName, Action: s.banpAction(), From: []v1alpha1.AdminNetworkPolicyIngressPeer{peer}, Ports: s.adminPorts()})
+		} else {
+			s.Tags.Add(TagEgress)
+			banp.Spec.Egress = append(banp.Spec.Egress, v1alpha1.BaselineAdminNetworkPolicyEgressRule{
+				Name: ruleName, Action: s.banpAction(), To: []v1alpha1.BaselineAdminNetworkPolicyEgressPeer{{Namespaces: peer.Namespaces, Pods: peer.Pods}}, Ports: s.adminPorts()})
+		}
+	}
+	return banp
+}
+
+// labelChange sets the fuzz label on a pod or a namespace.  The pod and ns labels are kept, since services
+// select pods by them.
+func (s *fuzzState) labelChange() *Action {
+	ns, value := s.pick(s.Config.Namespaces), s.pick(fuzzLabelValues)
+	if s.Intn(2) == 0 {
+		s.Tags.Add(TagSetNamespaceLabels)
+		return SetNamespaceLabels(ns, map[string]string{"ns": ns, fuzzLabelKey: value})
+	}
+	pod := s.pick(s.Config.Pods)
+	s.Tags.Add(TagSetPodLabels)
+	return SetPodLabels(ns, pod, map[string]string{"pod": pod, fuzzLabelKey: value})
+}
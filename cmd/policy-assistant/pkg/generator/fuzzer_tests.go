@@ -0,0 +1,132 @@
+package generator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	. "k8s.io/api/networking/v1"
+)
+
+func RunFuzzerTests() {
+	config := &FuzzConfig{
+		Namespaces:         []string{"x", "y", "z"},
+		Pods:               []string{"a", "b", "c"},
+		Ports:              []int{80, 81},
+		Protocols:          []v1.Protocol{v1.ProtocolTCP, v1.ProtocolUDP},
+		MaxNetworkPolicies: 3,
+		MaxANPs:            2,
+		AdminPolicies:      true,
+		LabelChanges:       true,
+	}
+
+	Describe("Fuzzer", func() {
+		It("generates the same test case from the same seed", func() {
+			for seed := int64(0); seed < 20; seed++ {
+				Expect(NewFuzzer(config).TestCase(seed)).To(Equal(NewFuzzer(config).TestCase(seed)))
+			}
+			Expect(NewFuzzer(config).TestCase(1)).ToNot(Equal(NewFuzzer(config).TestCase(2)))
+		})
+
+		It("generates policies within the config's bounds", func() {
+			for seed := int64(0); seed < 100; seed++ {
+				testCase := NewFuzzer(config).TestCase(seed)
+				Expect(testCase.Steps).To(HaveLen(2))
+				Expect(ValidateTags(testCase.Tags.Keys())).To(Succeed())
+
+				netpols, anps, banps := 0, 0, 0
+				priorities := map[int32]bool{}
+				for _, action := range testCase.Steps[0].Actions {
+					switch {
+					case action.CreatePolicy != nil:
+						netpols++
+						Expect(config.Namespaces).To(ContainElement(action.CreatePolicy.Policy.Namespace))
+						Expect(action.CreatePolicy.Policy.Spec.PolicyTypes).ToNot(BeEmpty())
+					case action.CreateANP != nil:
+						anps++
+						priorities[action.CreateANP.Policy.Spec.Priority] = true
+					case action.CreateBANP != nil:
+						banps++
+						Expect(action.CreateBANP.Policy.Name).To(Equal("default"))
+					}
+				}
+				Expect(netpols).To(BeNumerically(">=", 1))
+				Expect(netpols).To(BeNumerically("<=", config.MaxNetworkPolicies))
+				Expect(anps).To(BeNumerically("<=", config.MaxANPs))
+				Expect(priorities).To(HaveLen(anps))
+				Expect(banps).To(BeNumerically("<=", 1))
+
+				for _, action := range testCase.Steps[1].Actions {
+					Expect(action.SetPodLabels != nil || action.SetNamespaceLabels != nil).To(BeTrue())
+				}
+			}
+		})
+	})
+
+	Describe("Fuzzer with many ANPs", func() {
+		It("gives every ANP a distinct, valid priority", func() {
+			manyANPs := *config
+			manyANPs.MaxANPs = MaxFuzzANPs
+			most := 0
+			for seed := int64(0); seed < 20; seed++ {
+				priorities := map[int32]bool{}
+				for _, action := range NewFuzzer(&manyANPs).TestCase(seed).Steps[0].Actions {
+					if action.CreateANP != nil {
+						priority := action.CreateANP.Policy.Spec.Priority
+						Expect(priority).To(BeNumerically("<=", 1000))
+						Expect(priorities).ToNot(HaveKey(priority))
+						priorities[priority] = true
+					}
+				}
+				Expect(len(priorities)).To(BeNumerically("<=", MaxFuzzANPs))
+				most = max(most, len(priorities))
+			}
+			Expect(most).To(BeNumerically(">", 100))
+		})
+	})
+
+	Describe("Shrink", func() {
+		It("shrinks to the culprit policy with a single rule and peer", func() {
+			culprit := (&Netpol{
+				Name:   "culprit",
+				Target: NewNetpolTarget("x", nil, nil),
+				Ingress: &NetpolPeers{Rules: []*Rule{
+					{Peers: []NetworkPolicyPeer{{IPBlock: &IPBlock{CIDR: "0.0.0.0/0"}}, {NamespaceSelector: nsXMatchLabelsSelector}}},
+					{Ports: []NetworkPolicyPort{{Protocol: &tcp, Port: &port80}}},
+				}},
+			}).NetworkPolicy()
+			testCase := NewTestCase("shrink me", NewStringSet(TagFuzz),
+				NewTestStep(ProbeAllAvailable, CreatePolicy(allowAllIngressToXA()), CreatePolicy(culprit)),
+				NewTestStep(ProbeAllAvailable, SetPodLabels("x", "a", map[string]string{"pod": "a", "fuzz": "a"})))
+
+			// fails whenever the culprit is created with a namespace selector peer
+			fails := func(candidate *TestCase) bool {
+				for _, step := range candidate.Steps {
+					for _, action := range step.Actions {
+						if action.CreatePolicy == nil || action.CreatePolicy.Policy.Name != "culprit" {
+							continue
+						}
+						for _, rule := range action.CreatePolicy.Policy.Spec.Ingress {
+							for _, peer := range rule.From {
+								if peer.NamespaceSelector != nil {
+									return true
+								}
+							}
+						}
+					}
+				}
+				return false
+			}
+
+			shrunk := Shrink(testCase, fails)
+			Expect(shrunk.Steps).To(HaveLen(1))
+			Expect(shrunk.Steps[0].Actions).To(HaveLen(1))
+			policy := shrunk.Steps[0].Actions[0].CreatePolicy.Policy
+			Expect(policy.Name).To(Equal("culprit"))
+			Expect(policy.Spec.Ingress).To(Equal([]NetworkPolicyIngressRule{{From: []NetworkPolicyPeer{{NamespaceSelector: nsXMatchLabelsSelector}}}}))
+
+			// the original test case is unchanged
+			Expect(testCase.Steps).To(HaveLen(2))
+			Expect(culprit.Spec.Ingress).To(HaveLen(2))
+		})
+	})
+}
@@ -0,0 +1,182 @@
+package generator
+
+import (
+	"golang.org/x/exp/slices"
+	. "k8s.io/api/networking/v1"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
+)
+
+// Shrink reduces a failing test case to a smaller one which still fails: it repeatedly tries removing a single
+// step, action, rule, peer or port, and keeps the first reduction which fails, until none do.
+// The result is minimal in the sense that no single one of these reductions still fails.
+func Shrink(testCase *TestCase, fails func(*TestCase) bool) *TestCase {
+	current := testCase
+	for {
+		shrunk := false
+		for _, candidate := range shrinkCandidates(current) {
+			if fails(candidate) {
+				current = candidate
+				shrunk = true
+				break
+			}
+		}
+		if !shrunk {
+			return current
+		}
+	}
+}
+
+// shrinkCandidates returns the reductions of a test case, coarsest first, so that Shrink
+// removes whole actions before picking policies apart
+func shrinkCandidates(testCase *TestCase) []*TestCase {
+	var candidates []*TestCase
+	if len(testCase.Steps) > 1 {
+		for i := range testCase.Steps {
+			candidates = append(candidates, testCase.withSteps(slices.Delete(slices.Clone(testCase.Steps), i, i+1)))
+		}
+	}
+	for i, step := range testCase.Steps {
+		for j := range step.Actions {
+			candidates = append(candidates, testCase.withActions(i, slices.Delete(slices.Clone(step.Actions), j, j+1)))
+		}
+	}
+	for i, step := range testCase.Steps {
+		for j, action := range step.Actions {
+			for _, shrunkAction := range shrinkAction(action) {
+				actions := slices.Clone(step.Actions)
+				actions[j] = shrunkAction
+				candidates = append(candidates, testCase.withActions(i, actions))
+			}
+		}
+	}
+	return candidates
+}
+
+func (t *TestCase) withSteps(steps []*TestStep) *TestCase {
+	return &TestCase{Description: t.Description, Tags: t.Tags, Steps: steps}
+}
+
+func (t *TestCase) withActions(stepIndex int, actions []*Action) *TestCase {
+	steps := slices.Clone(t.Steps)
+	steps[stepIndex] = NewTestStep(steps[stepIndex].Probe, actions...)
+	return t.withSteps(steps)
+}
+
+// shrinkAction shrinks the policies of create and update actions; other actions can only be removed
+func shrinkAction(action *Action) []*Action {
+	var actions []*Action
+	switch {
+	case action.CreatePolicy != nil:
+		for _, policy := range shrinkNetworkPolicy(action.CreatePolicy.Policy) {
+			actions = append(actions, CreatePolicy(policy))
+		}
+	case action.UpdatePolicy != nil:
+		for _, policy := range shrinkNetworkPolicy(action.UpdatePolicy.Policy) {
+			actions = append(actions, UpdatePolicy(policy))
+		}
+	case action.CreateANP != nil:
+		for _, anp := range shrinkANP(action.CreateANP.Policy) {
+			actions = append(actions, CreateANP(anp))
+		}
+	case action.UpdateANP != nil:
+		for _, anp := range shrinkANP(action.UpdateANP.Policy) {
+			actions = append(actions, UpdateANP(anp))
+		}
+	case action.CreateBANP != nil:
+		for _, banp := range shrinkBANP(action.CreateBANP.Policy) {
+			actions = append(actions, CreateBANP(banp))
+		}
+	case action.UpdateBANP != nil:
+		for _, banp := range shrinkBANP(action.UpdateBANP.Policy) {
+			actions = append(actions, UpdateBANP(banp))
+		}
+	}
+	return actions
+}
+
+// shrinkNetworkPolicy removes a direction, a rule, or a peer or port from a rule.  Removing the last peer or port
+// of a rule widens the rule to all peers or ports; removing the last rule denies all traffic in that direction.
+func shrinkNetworkPolicy(policy *NetworkPolicy) []*NetworkPolicy {
+	var policies []*NetworkPolicy
+	if len(policy.Spec.PolicyTypes) > 1 {
+		for i, policyType := range policy.Spec.PolicyTypes {
+			shrunk := policy.DeepCopy()
+			shrunk.Spec.PolicyTypes = slices.Delete(shrunk.Spec.PolicyTypes, i, i+1)
+			if policyType == PolicyTypeIngress {
+				shrunk.Spec.Ingress = nil
+			} else {
+				shrunk.Spec.Egress = nil
+			}
+			policies = append(policies, shrunk)
+		}
+	}
+	for i, rule := range policy.Spec.Ingress {
+		shrunk := policy.DeepCopy()
+		shrunk.Spec.Ingress = slices.Delete(shrunk.Spec.Ingress, i, i+1)
+		policies = append(policies, shrunk)
+		for j := range rule.From {
+			shrunk := policy.DeepCopy()
+			shrunk.Spec.Ingress[i].From = slices.Delete(shrunk.Spec.Ingress[i].From, j, j+1)
+			policies = append(policies, shrunk)
+		}
+		for j := range rule.Ports {
+			shrunk := policy.DeepCopy()
+			shrunk.Spec.Ingress[i].Ports = slices.Delete(shrunk.Spec.Ingress[i].Ports, j, j+1)
+			policies = append(policies, shrunk)
+		}
+	}
+	for i, rule := range policy.Spec.Egress {
+		shrunk := policy.DeepCopy()
+		shrunk.Spec.Egress = slices.Delete(shrunk.Spec.Egress, i, i+1)
+		policies = append(policies, shrunk)
+		for j := range rule.To {
+			shrunk := policy.DeepCopy()
+			shrunk.Spec.Egress[i].To = slices.Delete(shrunk.Spec.Egress[i].To, j, j+1)
+			policies = append(policies, shrunk)
+		}
+		for j := range rule.Ports {
+			shrunk := policy.DeepCopy()
+			shrunk.Spec.Egress[i].Ports = slices.Delete(shrunk.Spec.Egress[i].Ports, j, j+1)
+			policies = append(policies, shrunk)
+		}
+	}
+	return policies
+}
+
+// shrinkANP removes a rule.  An ANP needs at least one rule, so the last rule is only removed along with the action.
+func shrinkANP(anp *v1alpha1.AdminNetworkPolicy) []*v1alpha1.AdminNetworkPolicy {
+	var anps []*v1alpha1.AdminNetworkPolicy
+	if len(anp.Spec.Ingress)+len(anp.Spec.Egress) <= 1 {
+		return nil
+	}
+	for i := range anp.Spec.Ingress {
+		shrunk := anp.DeepCopy()
+		shrunk.Spec.Ingress = slices.Delete(shrunk.Spec.Ingress, i, i+1)
+		anps = append(anps, shrunk)
+	}
+	for i := range anp.Spec.Egress {
+		shrunk := anp.DeepCopy()
+		shrunk.Spec.Egress = slices.Delete(shrunk.Spec.Egress, i, i+1)
+		anps = append(anps, shrunk)
+	}
+	return anps
+}
+
+// shrinkBANP removes a rule; as with ANPs, the last rule is kept.
+func shrinkBANP(banp *v1alpha1.BaselineAdminNetworkPolicy) []*v1alpha1.BaselineAdminNetworkPolicy {
+	var banps []*v1alpha1.BaselineAdminNetworkPolicy
+	if len(banp.Spec.Ingress)+len(banp.Spec.Egress) <= 1 {
+		return nil
+	}
+	for i := range banp.Spec.Ingress {
+		shrunk := banp.DeepCopy()
+		shrunk.Spec.Ingress = slices.Delete(shrunk.Spec.Ingress, i, i+1)
+		banps = append(banps, shrunk)
+	}
+	for i := range banp.Spec.Egress {
+		shrunk := banp.DeepCopy()
+		shrunk.Spec.Egress = slices.Delete(shrunk.Spec.Egress, i, i+1)
+		banps = append(banps, shrunk)
+	}
+	return banps
+}
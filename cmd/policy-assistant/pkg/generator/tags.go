@@ -18,6 +18,7 @@ const (
 	TagPort          = "port"
 	TagPeerIPBlock   = "peer-ipblock"
 	TagPeerPods      = "peer-pods"
+	TagAdminPolicy   = "admin-network-policy"
 	TagMiscellaneous = "miscellaneous"
 )
 
@@ -31,6 +32,12 @@ const (
 	TagCreateNamespace    = "create-namespace"
 	TagDeleteNamespace    = "delete-namespace"
 	TagSetNamespaceLabels = "set-namespace-labels"
+	TagCreateANP          = "create-anp"
+	TagUpdateANP          = "update-anp"
+	TagDeleteANP          = "delete-anp"
+	TagCreateBANP         = "create-banp"
+	TagUpdateBANP         = "update-banp"
+	TagDeleteBANP         = "delete-banp"
 )
 
 const (
@@ -79,11 +86,22 @@ const (
 	TagSCTPProtocol = "sctp"
 )
 
+const (
+	TagANP             = "anp"
+	TagBANP            = "banp"
+	TagAdminAllow      = "admin-allow"
+	TagAdminDeny       = "admin-deny"
+	TagAdminPass       = "admin-pass"
+	TagANPPriority     = "anp-priority"
+	TagAdminPrecedence = "admin-precedence"
+)
+
 const (
 	TagPathological = "pathological"
 	TagConflict     = "conflict"
 	TagExample      = "example"
 	TagUpstreamE2E  = "upstream-e2e"
+	TagFuzz         = "fuzz"
 )
 
 var AllTags = map[string][]string{
@@ -97,6 +115,12 @@ var AllTags = map[string][]string{
 		TagCreateNamespace,
 		TagDeleteNamespace,
 		TagSetNamespaceLabels,
+		TagCreateANP,
+		TagUpdateANP,
+		TagDeleteANP,
+		TagCreateBANP,
+		TagUpdateBANP,
+		TagDeleteBANP,
 	},
 	TagTarget: {
 		TagTargetNamespace,
@@ -138,11 +162,21 @@ var AllTags = map[string][]string{
 		TagUDPProtocol,
 		TagSCTPProtocol,
 	},
+	TagAdminPolicy: {
+		TagANP,
+		TagBANP,
+		TagAdminAllow,
+		TagAdminDeny,
+		TagAdminPass,
+		TagANPPriority,
+		TagAdminPrecedence,
+	},
 	TagMiscellaneous: {
 		TagPathological,
 		TagConflict,
 		TagExample,
 		TagUpstreamE2E,
+		TagFuzz,
 	},
 }
 
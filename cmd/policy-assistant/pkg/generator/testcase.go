@@ -49,6 +49,18 @@ func (t *TestCase) collectActionsAndPolicies() (map[string]bool, []*networkingv1
 				policies = append(policies, action.UpdatePolicy.Policy)
 			} else if action.DeletePolicy != nil {
 				features[ActionFeatureDeletePolicy] = true
+			} else if action.CreateANP != nil {
+				features[ActionFeatureCreateANP] = true
+			} else if action.UpdateANP != nil {
+				features[ActionFeatureUpdateANP] = true
+			} else if action.DeleteANP != nil {
+				features[ActionFeatureDeleteANP] = true
+			} else if action.CreateBANP != nil {
+				features[ActionFeatureCreateBANP] = true
+			} else if action.UpdateBANP != nil {
+				features[ActionFeatureUpdateBANP] = true
+			} else if action.DeleteBANP != nil {
+				features[ActionFeatureDeleteBANP] = true
 			} else if action.CreateNamespace != nil {
 				features[ActionFeatureCreateNamespace] = true
 			} else if action.SetNamespaceLabels != nil {
@@ -72,6 +72,7 @@ func (t *TestCaseGenerator) GenerateAllTestCases() []*TestCase {
 		t.ActionTestCases(),
 		t.ConflictTestCases(),
 		t.NamespaceTestCases(),
+		t.AdminNetworkPolicyTestCases(),
 		t.UpstreamE2ETestCases())
 }
 
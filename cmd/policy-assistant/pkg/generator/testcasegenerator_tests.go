@@ -19,8 +19,9 @@ func RunTestCaseGeneratorTests() {
 			Expect(len(gen.PortProtocolTestCases())).To(Equal(70))
 			Expect(len(gen.ConflictTestCases())).To(Equal(16))
 			Expect(len(gen.NamespaceTestCases())).To(Equal(2))
+			Expect(len(gen.AdminNetworkPolicyTestCases())).To(Equal(10))
 
-			Expect(len(gen.GenerateTestCases())).To(Equal(230))
+			Expect(len(gen.GenerateTestCases())).To(Equal(240))
 		})
 	})
 }
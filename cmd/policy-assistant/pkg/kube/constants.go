@@ -2,4 +2,9 @@ package kube
 
 const (
 	DefaultNamespaceLabel = "kubernetes.io/metadata.name"
+
+	// ManagedByLabel marks the cluster-scoped resources -- ANPs and BANPs -- which cyclonus creates,
+	// so that it can clean them up without touching anyone else's
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	ManagedByValue = "cyclonus"
 )
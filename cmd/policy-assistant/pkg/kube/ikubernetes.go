@@ -8,6 +8,7 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"math/rand"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
 )
 
 type IKubernetes interface {
@@ -23,6 +24,16 @@ type IKubernetes interface {
 	DeleteNetworkPolicy(namespace string, name string) error
 	DeleteAllNetworkPoliciesInNamespace(namespace string) error
 
+	CreateAdminNetworkPolicy(kubePolicy *v1alpha1.AdminNetworkPolicy) (*v1alpha1.AdminNetworkPolicy, error)
+	GetAdminNetworkPolicies() ([]v1alpha1.AdminNetworkPolicy, error)
+	UpdateAdminNetworkPolicy(kubePolicy *v1alpha1.AdminNetworkPolicy) (*v1alpha1.AdminNetworkPolicy, error)
+	DeleteAdminNetworkPolicy(name string) error
+
+	CreateBaselineAdminNetworkPolicy(kubePolicy *v1alpha1.BaselineAdminNetworkPolicy) (*v1alpha1.BaselineAdminNetworkPolicy, error)
+	GetBaselineAdminNetworkPolicies() ([]v1alpha1.BaselineAdminNetworkPolicy, error)
+	UpdateBaselineAdminNetworkPolicy(kubePolicy *v1alpha1.BaselineAdminNetworkPolicy) (*v1alpha1.BaselineAdminNetworkPolicy, error)
+	DeleteBaselineAdminNetworkPolicy(name string) error
+
 	CreateService(kubeService *v1.Service) (*v1.Service, error)
 	GetService(namespace string, name string) (*v1.Service, error)
 	DeleteService(namespace string, name string) error
@@ -92,13 +103,18 @@ type MockNamespace struct {
 
 type MockKubernetes struct {
 	Namespaces map[string]*MockNamespace
-	passRate   float64
-	podID      int
+	// ANPs and BANPs are cluster-scoped, so they're keyed by name only
+	ANPs     map[string]*v1alpha1.AdminNetworkPolicy
+	BANPs    map[string]*v1alpha1.BaselineAdminNetworkPolicy
+	passRate float64
+	podID    int
 }
 
 func NewMockKubernetes(passRate float64) *MockKubernetes {
 	return &MockKubernetes{
 		Namespaces: map[string]*MockNamespace{},
+		ANPs:       map[string]*v1alpha1.AdminNetworkPolicy{},
+		BANPs:      map[string]*v1alpha1.BaselineAdminNetworkPolicy{},
 		passRate:   passRate,
 		podID:      1,
 	}
@@ -227,6 +243,70 @@ func (m *MockKubernetes) CreateNetworkPolicy(policy *networkingv1.NetworkPolicy)
 	return policy, nil
 }
 
+func (m *MockKubernetes) CreateAdminNetworkPolicy(policy *v1alpha1.AdminNetworkPolicy) (*v1alpha1.AdminNetworkPolicy, error) {
+	if _, ok := m.ANPs[policy.Name]; ok {
+		return nil, errors.Errorf("admin network policy %s already present", policy.Name)
+	}
+	m.ANPs[policy.Name] = policy
+	return policy, nil
+}
+
+func (m *MockKubernetes) GetAdminNetworkPolicies() ([]v1alpha1.AdminNetworkPolicy, error) {
+	var policies []v1alpha1.AdminNetworkPolicy
+	for _, policy := range m.ANPs {
+		policies = append(policies, *policy)
+	}
+	return policies, nil
+}
+
+func (m *MockKubernetes) UpdateAdminNetworkPolicy(policy *v1alpha1.AdminNetworkPolicy) (*v1alpha1.AdminNetworkPolicy, error) {
+	if _, ok := m.ANPs[policy.Name]; !ok {
+		return nil, errors.Errorf("admin network policy %s not found", policy.Name)
+	}
+	m.ANPs[policy.Name] = policy
+	return policy, nil
+}
+
+func (m *MockKubernetes) DeleteAdminNetworkPolicy(name string) error {
+	if _, ok := m.ANPs[name]; !ok {
+		return errors.Errorf("admin network policy %s not found", name)
+	}
+	delete(m.ANPs, name)
+	return nil
+}
+
+func (m *MockKubernetes) CreateBaselineAdminNetworkPolicy(policy *v1alpha1.BaselineAdminNetworkPolicy) (*v1alpha1.BaselineAdminNetworkPolicy, error) {
+	if _, ok := m.BANPs[policy.Name]; ok {
+		return nil, errors.Errorf("baseline admin network policy %s already present", policy.Name)
+	}
+	m.BANPs[policy.Name] = policy
+	return policy, nil
+}
+
+func (m *MockKubernetes) GetBaselineAdminNetworkPolicies() ([]v1alpha1.BaselineAdminNetworkPolicy, error) {
+	var policies []v1alpha1.BaselineAdminNetworkPolicy
+	for _, policy := range m.BANPs {
+		policies = append(policies, *policy)
+	}
+	return policies, nil
+}
+
+func (m *MockKubernetes) UpdateBaselineAdminNetworkPolicy(policy *v1alpha1.BaselineAdminNetworkPolicy) (*v1alpha1.BaselineAdminNetworkPolicy, error) {
+	if _, ok := m.BANPs[policy.Name]; !ok {
+		return nil, errors.Errorf("baseline admin network policy %s not found", policy.Name)
+	}
+	m.BANPs[policy.Name] = policy
+	return policy, nil
+}
+
+func (m *MockKubernetes) DeleteBaselineAdminNetworkPolicy(name string) error {
+	if _, ok := m.BANPs[name]; !ok {
+		return errors.Errorf("baseline admin network policy %s not found", name)
+	}
+	delete(m.BANPs, name)
+	return nil
+}
+
 func (m *MockKubernetes) GetService(namespace string, name string) (*v1.Service, error) {
 	nsObject, err := m.getNamespaceObject(namespace)
 	if err != nil {
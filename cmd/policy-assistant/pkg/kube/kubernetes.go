@@ -8,6 +8,7 @@ import (
 	"github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -16,11 +17,14 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/remotecommand"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
+	policyclientset "sigs.k8s.io/network-policy-api/pkg/client/clientset/versioned"
 )
 
 type Kubernetes struct {
-	ClientSet  *kubernetes.Clientset
-	RestConfig *rest.Config
+	ClientSet       *kubernetes.Clientset
+	PolicyClientSet *policyclientset.Clientset
+	RestConfig      *rest.Config
 }
 
 func NewKubernetesForContext(context string) (*Kubernetes, error) {
@@ -35,9 +39,14 @@ func NewKubernetesForContext(context string) (*Kubernetes, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to instantiate Clientset")
 	}
+	policyClientset, err := policyclientset.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to instantiate network policy api Clientset")
+	}
 	return &Kubernetes{
-		ClientSet:  clientset,
-		RestConfig: kubeConfig,
+		ClientSet:       clientset,
+		PolicyClientSet: policyClientset,
+		RestConfig:      kubeConfig,
 	}, nil
 }
 
@@ -100,6 +109,72 @@ func (k *Kubernetes) GetNetworkPoliciesInNamespace(namespace string) ([]networki
 	return netpolList.Items, nil
 }
 
+func (k *Kubernetes) GetAdminNetworkPolicies() ([]v1alpha1.AdminNetworkPolicy, error) {
+	anpList, err := k.PolicyClientSet.PolicyV1alpha1().AdminNetworkPolicies().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get admin network policies")
+	}
+	return anpList.Items, nil
+}
+
+func (k *Kubernetes) GetBaselineAdminNetworkPolicies() ([]v1alpha1.BaselineAdminNetworkPolicy, error) {
+	banpList, err := k.PolicyClientSet.PolicyV1alpha1().BaselineAdminNetworkPolicies().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get baseline admin network policies")
+	}
+	return banpList.Items, nil
+}
+
+func (k *Kubernetes) CreateAdminNetworkPolicy(policy *v1alpha1.AdminNetworkPolicy) (*v1alpha1.AdminNetworkPolicy, error) {
+	logrus.Debugf("creating admin network policy %s", policy.Name)
+	createdPolicy, err := k.PolicyClientSet.PolicyV1alpha1().AdminNetworkPolicies().Create(context.TODO(), policy, metav1.CreateOptions{})
+	return createdPolicy, errors.Wrapf(err, "unable to create admin network policy %s", policy.Name)
+}
+
+func (k *Kubernetes) UpdateAdminNetworkPolicy(policy *v1alpha1.AdminNetworkPolicy) (*v1alpha1.AdminNetworkPolicy, error) {
+	logrus.Debugf("updating admin network policy %s", policy.Name)
+	// updates must carry the current resourceVersion
+	current, err := k.PolicyClientSet.PolicyV1alpha1().AdminNetworkPolicies().Get(context.TODO(), policy.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get admin network policy %s", policy.Name)
+	}
+	update := policy.DeepCopy()
+	update.ResourceVersion = current.ResourceVersion
+	updatedPolicy, err := k.PolicyClientSet.PolicyV1alpha1().AdminNetworkPolicies().Update(context.TODO(), update, metav1.UpdateOptions{})
+	return updatedPolicy, errors.Wrapf(err, "unable to update admin network policy %s", policy.Name)
+}
+
+func (k *Kubernetes) DeleteAdminNetworkPolicy(name string) error {
+	logrus.Debugf("deleting admin network policy %s", name)
+	err := k.PolicyClientSet.PolicyV1alpha1().AdminNetworkPolicies().Delete(context.TODO(), name, metav1.DeleteOptions{})
+	return errors.Wrapf(err, "unable to delete admin network policy %s", name)
+}
+
+func (k *Kubernetes) CreateBaselineAdminNetworkPolicy(policy *v1alpha1.BaselineAdminNetworkPolicy) (*v1alpha1.BaselineAdminNetworkPolicy, error) {
+	logrus.Debugf("creating baseline admin network policy %s", policy.Name)
+	createdPolicy, err := k.PolicyClientSet.PolicyV1alpha1().BaselineAdminNetworkPolicies().Create(context.TODO(), policy, metav1.CreateOptions{})
+	return createdPolicy, errors.Wrapf(err, "unable to create baseline admin network policy %s", policy.Name)
+}
+
+func (k *Kubernetes) UpdateBaselineAdminNetworkPolicy(policy *v1alpha1.BaselineAdminNetworkPolicy) (*v1alpha1.BaselineAdminNetworkPolicy, error) {
+	logrus.Debugf("updating baseline admin network policy %s", policy.Name)
+	// updates must carry the current resourceVersion
+	current, err := k.PolicyClientSet.PolicyV1alpha1().BaselineAdminNetworkPolicies().Get(context.TODO(), policy.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get baseline admin network policy %s", policy.Name)
+	}
+	update := policy.DeepCopy()
+	update.ResourceVersion = current.ResourceVersion
+	updatedPolicy, err := k.PolicyClientSet.PolicyV1alpha1().BaselineAdminNetworkPolicies().Update(context.TODO(), update, metav1.UpdateOptions{})
+	return updatedPolicy, errors.Wrapf(err, "unable to update baseline admin network policy %s", policy.Name)
+}
+
+func (k *Kubernetes) DeleteBaselineAdminNetworkPolicy(name string) error {
+	logrus.Debugf("deleting baseline admin network policy %s", name)
+	err := k.PolicyClientSet.PolicyV1alpha1().BaselineAdminNetworkPolicies().Delete(context.TODO(), name, metav1.DeleteOptions{})
+	return errors.Wrapf(err, "unable to delete baseline admin network policy %s", name)
+}
+
 func (k *Kubernetes) UpdateNetworkPolicy(policy *networkingv1.NetworkPolicy) (*networkingv1.NetworkPolicy, error) {
 	logrus.Debugf("updating network policy %s/%s", policy.Namespace, policy.Name)
 	np, err := k.ClientSet.NetworkingV1().NetworkPolicies(policy.Namespace).Update(context.TODO(), policy, metav1.UpdateOptions{})
@@ -150,6 +225,11 @@ func (k *Kubernetes) GetReplicaSet(namespace string, name string) (*appsv1.Repli
 	return replicaSet, errors.Wrapf(err, "unable to get replicaSet %s/%s", namespace, name)
 }
 
+func (k *Kubernetes) GetNode(name string) (*v1.Node, error) {
+	node, err := k.ClientSet.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+	return node, errors.Wrapf(err, "unable to get node %s", name)
+}
+
 func (k *Kubernetes) GetService(namespace string, name string) (*v1.Service, error) {
 	service, err := k.ClientSet.CoreV1().Services(namespace).Get(context.TODO(), name, metav1.GetOptions{})
 	return service, errors.Wrapf(err, "unable to get service %s/%s", namespace, name)
@@ -179,6 +259,14 @@ func (k *Kubernetes) GetServicesInNamespace(namespace string) ([]v1.Service, err
 	return serviceList.Items, nil
 }
 
+func (k *Kubernetes) GetEndpointSlicesInNamespace(namespace string) ([]discoveryv1.EndpointSlice, error) {
+	endpointSliceList, err := k.ClientSet.DiscoveryV1().EndpointSlices(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get endpoint slices in namespace %s", namespace)
+	}
+	return endpointSliceList.Items, nil
+}
+
 func (k *Kubernetes) GetPodsInNamespace(namespace string) ([]v1.Pod, error) {
 	podList, err := k.ClientSet.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
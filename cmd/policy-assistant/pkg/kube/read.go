@@ -1,6 +1,10 @@
 package kube
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -11,11 +15,61 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
 )
 
+// Policies holds all the kinds of policies which can be read from a path:
+// v1 NetworkPolicies, AdminNetworkPolicies and BaselineAdminNetworkPolicies.
+type Policies struct {
+	NetworkPolicies              []*networkingv1.NetworkPolicy          `json:"networkPolicies"`
+	AdminNetworkPolicies         []*v1alpha1.AdminNetworkPolicy         `json:"adminNetworkPolicies"`
+	BaselineAdminNetworkPolicies []*v1alpha1.BaselineAdminNetworkPolicy `json:"baselineAdminNetworkPolicies"`
+}
+
 func ReadNetworkPoliciesFromPath(policyPath string) ([]*networkingv1.NetworkPolicy, error) {
-	var allPolicies []*networkingv1.NetworkPolicy
-	err := filepath.Walk(policyPath, func(path string, info os.FileInfo, err error) error {
+	policies, err := ReadPoliciesFromPath(policyPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range policies.NetworkPolicies {
+		if len(p.Spec.PolicyTypes) == 0 {
+			return nil, errors.Errorf("missing spec.policyTypes from network policy %s/%s", p.Namespace, p.Name)
+		}
+	}
+	return policies.NetworkPolicies, nil
+}
+
+// ReadPoliciesFromPath reads policies from a file, or from all files under a directory.
+// Each file may contain multiple yaml documents separated by '---' lines, and each document
+// may be a single policy, a typed list of policies (e.g. AdminNetworkPolicyList),
+// a generic 'kind: List', or a plain yaml list.  Kinds may be mixed freely.
+// Policies are not validated here: see matcher.BuildV1AndV2NetPols.
+func ReadPoliciesFromPath(policyPath string) (*Policies, error) {
+	policies := &Policies{}
+	err := walkFiles(policyPath, func(path string, bs []byte) error {
+		return errors.WithMessagef(readPoliciesFromBytes(bs, policies), "unable to parse policies from yaml at %s", path)
+	})
+	if err != nil {
+		return nil, err
+		//return nil, errors.Wrapf(err, "unable to walk filesystem from %s", policyPath)
+	}
+	return policies, nil
+}
+
+// ReadPoliciesFromBytes reads policies from yaml documents, in the same formats as ReadPoliciesFromPath.
+func ReadPoliciesFromBytes(bs []byte) (*Policies, error) {
+	policies := &Policies{}
+	if err := readPoliciesFromBytes(bs, policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// walkFiles calls handle with the contents of a file, or of each file under a directory
+func walkFiles(root string, handle func(path string, bs []byte) error) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return errors.Wrapf(err, "unable to walk path %s", path)
 		}
@@ -24,51 +78,127 @@ func ReadNetworkPoliciesFromPath(policyPath string) ([]*networkingv1.NetworkPoli
 			return nil
 		}
 		logrus.Debugf("walking path %s", path)
-		bytes, err := file.Read(path)
+		bs, err := file.Read(path)
 		if err != nil {
 			return err
 		}
+		return handle(path, bs)
+	})
+}
+
+// readYamlDocuments calls handle with each yaml document, where documents are separated by '---' lines
+func readYamlDocuments(bs []byte, handle func(document []byte) error) error {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(bs)))
+	for {
+		document, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Wrapf(err, "unable to split yaml documents")
+		}
+		if err := handle(document); err != nil {
+			return err
+		}
+	}
+}
 
-		// TODO try parsing plain yaml list (that is: not a NetworkPolicyList)
-		// policies, err := utils.ParseYaml[[]*networkingv1.NetworkPolicy](bytes)
+func readPoliciesFromBytes(bs []byte, policies *Policies) error {
+	return readYamlDocuments(bs, func(document []byte) error {
+		return readPoliciesFromDocument(document, policies)
+	})
+}
+
+func readPoliciesFromDocument(document []byte, policies *Policies) error {
+	// skip empty and comment-only documents, e.g. a leading or trailing '---'
+	contents, err := utils.ParseYaml[interface{}](document)
+	if err != nil {
+		return err
+	}
+	if *contents == nil {
+		return nil
+	}
 
-		// TODO try parsing multiple policies separated by '---' lines
-		// policies, err := yaml.ParseMany[networkingv1.NetworkPolicy](bytes)
-		// if err == nil {
-		// 	logrus.Debugf("parsed %d policies from %s", len(policies), path)
-		// 	allPolicies = append(allPolicies, refNetpolList(policies)...)
-		// 	return nil
-		// }
-		// logrus.Errorf("unable to parse multiple policies separated by '---' lines: %+v", err)
+	// a plain yaml list
+	if items, err := utils.ParseYaml[[]json.RawMessage](document); err == nil {
+		return readPoliciesFromItems(*items, policies)
+	}
 
-		// try parsing a NetworkPolicyList
-		policyList, err := utils.ParseYamlStrict[networkingv1.NetworkPolicyList](bytes)
+	typeMeta, err := utils.ParseYaml[metav1.TypeMeta](document)
+	if err != nil {
+		return err
+	}
+	switch typeMeta.Kind {
+	case "NetworkPolicy":
+		policy, err := utils.ParseYamlStrict[networkingv1.NetworkPolicy](document)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse NetworkPolicy")
+		}
+		policies.NetworkPolicies = append(policies.NetworkPolicies, policy)
+	case "NetworkPolicyList":
+		policyList, err := utils.ParseYamlStrict[networkingv1.NetworkPolicyList](document)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse NetworkPolicyList")
+		}
+		policies.NetworkPolicies = append(policies.NetworkPolicies, refNetpolList(policyList.Items)...)
+	case "AdminNetworkPolicy":
+		policy, err := utils.ParseYamlStrict[v1alpha1.AdminNetworkPolicy](document)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse AdminNetworkPolicy")
+		}
+		policies.AdminNetworkPolicies = append(policies.AdminNetworkPolicies, policy)
+	case "AdminNetworkPolicyList":
+		policyList, err := utils.ParseYamlStrict[v1alpha1.AdminNetworkPolicyList](document)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse AdminNetworkPolicyList")
+		}
+		policies.AdminNetworkPolicies = append(policies.AdminNetworkPolicies, refANPList(policyList.Items)...)
+	case "BaselineAdminNetworkPolicy":
+		policy, err := utils.ParseYamlStrict[v1alpha1.BaselineAdminNetworkPolicy](document)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse BaselineAdminNetworkPolicy")
+		}
+		policies.BaselineAdminNetworkPolicies = append(policies.BaselineAdminNetworkPolicies, policy)
+	case "BaselineAdminNetworkPolicyList":
+		policyList, err := utils.ParseYamlStrict[v1alpha1.BaselineAdminNetworkPolicyList](document)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse BaselineAdminNetworkPolicyList")
+		}
+		policies.BaselineAdminNetworkPolicies = append(policies.BaselineAdminNetworkPolicies, refBANPList(policyList.Items)...)
+	case "List":
+		list, err := utils.ParseYaml[struct {
+			Items []json.RawMessage `json:"items"`
+		}](document)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse List")
+		}
+		return readPoliciesFromItems(list.Items, policies)
+	case "":
+		// no kind: for backwards compatibility, assume a NetworkPolicyList or a NetworkPolicy
+		policyList, err := utils.ParseYamlStrict[networkingv1.NetworkPolicyList](document)
 		if err == nil {
-			allPolicies = append(allPolicies, refNetpolList(policyList.Items)...)
+			policies.NetworkPolicies = append(policies.NetworkPolicies, refNetpolList(policyList.Items)...)
 			return nil
 		}
-
 		logrus.Debugf("unable to parse list of policies: %+v", err)
 
-		policy, err := utils.ParseYamlStrict[networkingv1.NetworkPolicy](bytes)
+		policy, err := utils.ParseYamlStrict[networkingv1.NetworkPolicy](document)
 		if err != nil {
-			return errors.WithMessagef(err, "unable to parse single policy from yaml at %s", path)
+			return errors.WithMessagef(err, "unable to parse single policy")
 		}
-
-		logrus.Debugf("parsed single policy from %s: %+v", path, policy)
-		allPolicies = append(allPolicies, policy)
-		return nil
-	})
-	if err != nil {
-		return nil, err
-		//return nil, errors.Wrapf(err, "unable to walk filesystem from %s", policyPath)
+		policies.NetworkPolicies = append(policies.NetworkPolicies, policy)
+	default:
+		return errors.Errorf("unsupported kind %s", typeMeta.Kind)
 	}
-	for _, p := range allPolicies {
-		if len(p.Spec.PolicyTypes) == 0 {
-			return nil, errors.Errorf("missing spec.policyTypes from network policy %s/%s", p.Namespace, p.Name)
+	return nil
+}
+
+func readPoliciesFromItems(items []json.RawMessage, policies *Policies) error {
+	for i, item := range items {
+		if err := readPoliciesFromDocument(item, policies); err != nil {
+			return errors.WithMessagef(err, "unable to parse list item %d", i)
 		}
 	}
-	return allPolicies, nil
+	return nil
 }
 
 func ReadNetworkPoliciesFromKube(kubeClient *Kubernetes, namespaces []string) ([]*networkingv1.NetworkPolicy, error) {
@@ -79,6 +209,32 @@ func ReadNetworkPoliciesFromKube(kubeClient *Kubernetes, namespaces []string) ([
 	return refNetpolList(netpols), nil
 }
 
+// ReadAdminNetworkPoliciesFromKube reads all AdminNetworkPolicies; these are cluster-scoped.
+func ReadAdminNetworkPoliciesFromKube(kubeClient *Kubernetes) ([]*v1alpha1.AdminNetworkPolicy, error) {
+	anps, err := kubeClient.GetAdminNetworkPolicies()
+	if err != nil {
+		return nil, err
+	}
+	return refANPList(anps), nil
+}
+
+// ReadBaselineAdminNetworkPoliciesFromKube reads all BaselineAdminNetworkPolicies; these are cluster-scoped.
+func ReadBaselineAdminNetworkPoliciesFromKube(kubeClient *Kubernetes) ([]*v1alpha1.BaselineAdminNetworkPolicy, error) {
+	banps, err := kubeClient.GetBaselineAdminNetworkPolicies()
+	if err != nil {
+		return nil, err
+	}
+	return refBANPList(banps), nil
+}
+
 func refNetpolList(refs []networkingv1.NetworkPolicy) []*networkingv1.NetworkPolicy {
 	return slice.Map(builtin.Reference[networkingv1.NetworkPolicy], refs)
 }
+
+func refANPList(refs []v1alpha1.AdminNetworkPolicy) []*v1alpha1.AdminNetworkPolicy {
+	return slice.Map(builtin.Reference[v1alpha1.AdminNetworkPolicy], refs)
+}
+
+func refBANPList(refs []v1alpha1.BaselineAdminNetworkPolicy) []*v1alpha1.BaselineAdminNetworkPolicy {
+	return slice.Map(builtin.Reference[v1alpha1.BaselineAdminNetworkPolicy], refs)
+}
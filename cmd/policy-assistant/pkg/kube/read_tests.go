@@ -18,14 +18,17 @@ func RunReadNetworkPolicyTests() {
 			Expect(len(policies)).To(Equal(3))
 		})
 
-		// TODO test case to read multiple policies from plain yaml list
+		It("Should read multiple policies from a plain yaml list", func() {
+			policies, err := ReadNetworkPoliciesFromPath("../../networkpolicies/yaml-syntax/plain-yaml-list.yaml")
+			Expect(err).To(BeNil())
+			Expect(len(policies)).To(Equal(2))
+		})
 
-		// TODO
-		// It("Should read multiple policies separated by '---' lines from a single file", func() {
-		// 	policies, err := ReadNetworkPoliciesFromPath("../../networkpolicies/yaml-syntax/triple-dash-separated.yaml")
-		// 	Expect(err).To(BeNil())
-		// 	Expect(len(policies)).To(Equal(3))
-		// })
+		It("Should read multiple policies separated by '---' lines from a single file", func() {
+			policies, err := ReadNetworkPoliciesFromPath("../../networkpolicies/yaml-syntax/triple-dash-separated.yaml")
+			Expect(err).To(BeNil())
+			Expect(len(policies)).To(Equal(3))
+		})
 
 		It("Should read multiple policies from all files in a directory", func() {
 			policies, err := ReadNetworkPoliciesFromPath("../../networkpolicies/simple-example")
@@ -34,9 +37,59 @@ func RunReadNetworkPolicyTests() {
 
 			policies, err = ReadNetworkPoliciesFromPath("../../networkpolicies/")
 			Expect(err).To(BeNil())
-			Expect(len(policies)).To(Equal(14))
+			Expect(len(policies)).To(Equal(20))
 		})
 
 		// TODO test to show what happens for duplicate names
 	})
+
+	Describe("ReadPolicies", func() {
+		It("Should read a list of admin network policies from a single file", func() {
+			policies, err := ReadPoliciesFromPath("../../networkpolicies/admin/anp-list.yaml")
+			Expect(err).To(BeNil())
+			Expect(len(policies.NetworkPolicies)).To(Equal(0))
+			Expect(len(policies.AdminNetworkPolicies)).To(Equal(2))
+			Expect(len(policies.BaselineAdminNetworkPolicies)).To(Equal(0))
+		})
+
+		It("Should read mixed kinds separated by '---' lines from a single file", func() {
+			policies, err := ReadPoliciesFromPath("../../networkpolicies/admin/mixed-kinds.yaml")
+			Expect(err).To(BeNil())
+			Expect(len(policies.NetworkPolicies)).To(Equal(1))
+			Expect(len(policies.AdminNetworkPolicies)).To(Equal(1))
+			Expect(policies.AdminNetworkPolicies[0].Name).To(Equal("allow-kube-dns"))
+			Expect(len(policies.BaselineAdminNetworkPolicies)).To(Equal(1))
+			Expect(policies.BaselineAdminNetworkPolicies[0].Name).To(Equal("default"))
+		})
+
+		It("Should read all kinds from all files in a directory", func() {
+			policies, err := ReadPoliciesFromPath("../../networkpolicies/admin")
+			Expect(err).To(BeNil())
+			Expect(len(policies.NetworkPolicies)).To(Equal(1))
+			Expect(len(policies.AdminNetworkPolicies)).To(Equal(3))
+			Expect(len(policies.BaselineAdminNetworkPolicies)).To(Equal(1))
+		})
+
+		It("Should reject unsupported kinds", func() {
+			policies := &Policies{}
+			err := readPoliciesFromBytes([]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: abc"), policies)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Describe("ReadServices", func() {
+		It("Should read services, endpoint slices, pods and namespaces from '---' separated documents and lists", func() {
+			services, err := ReadServicesFromPath("../../examples/services.yaml")
+			Expect(err).To(BeNil())
+			Expect(len(services.Services)).To(Equal(2))
+			Expect(len(services.EndpointSlices)).To(Equal(1))
+			Expect(len(services.Pods)).To(Equal(5))
+			Expect(len(services.Namespaces)).To(Equal(1))
+		})
+
+		It("Should reject unsupported kinds", func() {
+			err := readServicesFromDocument([]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: abc"), &Services{})
+			Expect(err).ToNot(BeNil())
+		})
+	})
 }
@@ -0,0 +1,149 @@
+package kube
+
+import (
+	"encoding/json"
+
+	"github.com/mattfenwick/collections/pkg/builtin"
+	"github.com/mattfenwick/collections/pkg/slice"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Services holds the resources needed to resolve traffic to a Service into traffic to its backend pods:
+// the Services and their EndpointSlices, plus the Pods and Namespaces whose labels policies select on.
+type Services struct {
+	Services       []*v1.Service
+	EndpointSlices []*discoveryv1.EndpointSlice
+	Pods           []*v1.Pod
+	Namespaces     []*v1.Namespace
+}
+
+// ReadServicesFromPath reads Services, EndpointSlices, Pods and Namespaces from a file, or from all files
+// under a directory, in the same formats as ReadPoliciesFromPath.
+func ReadServicesFromPath(servicePath string) (*Services, error) {
+	services := &Services{}
+	err := walkFiles(servicePath, func(path string, bs []byte) error {
+		err := readYamlDocuments(bs, func(document []byte) error {
+			return readServicesFromDocument(document, services)
+		})
+		return errors.WithMessagef(err, "unable to parse services from yaml at %s", path)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+func readServicesFromDocument(document []byte, services *Services) error {
+	// skip empty and comment-only documents, e.g. a leading or trailing '---'
+	contents, err := utils.ParseYaml[interface{}](document)
+	if err != nil {
+		return err
+	}
+	if *contents == nil {
+		return nil
+	}
+
+	// a plain yaml list
+	if items, err := utils.ParseYaml[[]json.RawMessage](document); err == nil {
+		return readServicesFromItems(*items, services)
+	}
+
+	typeMeta, err := utils.ParseYaml[metav1.TypeMeta](document)
+	if err != nil {
+		return err
+	}
+	switch typeMeta.Kind {
+	case "Service":
+		service, err := utils.ParseYaml[v1.Service](document)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse Service")
+		}
+		services.Services = append(services.Services, service)
+	case "ServiceList":
+		list, err := utils.ParseYaml[v1.ServiceList](document)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse ServiceList")
+		}
+		services.Services = append(services.Services, slice.Map(builtin.Reference[v1.Service], list.Items)...)
+	case "EndpointSlice":
+		endpointSlice, err := utils.ParseYaml[discoveryv1.EndpointSlice](document)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse EndpointSlice")
+		}
+		services.EndpointSlices = append(services.EndpointSlices, endpointSlice)
+	case "EndpointSliceList":
+		list, err := utils.ParseYaml[discoveryv1.EndpointSliceList](document)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse EndpointSliceList")
+		}
+		services.EndpointSlices = append(services.EndpointSlices, slice.Map(builtin.Reference[discoveryv1.EndpointSlice], list.Items)...)
+	case "Pod":
+		pod, err := utils.ParseYaml[v1.Pod](document)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse Pod")
+		}
+		services.Pods = append(services.Pods, pod)
+	case "PodList":
+		list, err := utils.ParseYaml[v1.PodList](document)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse PodList")
+		}
+		services.Pods = append(services.Pods, slice.Map(builtin.Reference[v1.Pod], list.Items)...)
+	case "Namespace":
+		ns, err := utils.ParseYaml[v1.Namespace](document)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse Namespace")
+		}
+		services.Namespaces = append(services.Namespaces, ns)
+	case "NamespaceList":
+		list, err := utils.ParseYaml[v1.NamespaceList](document)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse NamespaceList")
+		}
+		services.Namespaces = append(services.Namespaces, slice.Map(builtin.Reference[v1.Namespace], list.Items)...)
+	case "List":
+		list, err := utils.ParseYaml[struct {
+			Items []json.RawMessage `json:"items"`
+		}](document)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse List")
+		}
+		return readServicesFromItems(list.Items, services)
+	default:
+		return errors.Errorf("unsupported kind %s", typeMeta.Kind)
+	}
+	return nil
+}
+
+func readServicesFromItems(items []json.RawMessage, services *Services) error {
+	for i, item := range items {
+		if err := readServicesFromDocument(item, services); err != nil {
+			return errors.WithMessagef(err, "unable to parse list item %d", i)
+		}
+	}
+	return nil
+}
+
+// ReadServicesFromKube reads Services and EndpointSlices in the namespaces; use v1.NamespaceAll for all namespaces.
+// Pods and Namespaces aren't read, since callers generally already have them.
+func ReadServicesFromKube(kubeClient *Kubernetes, namespaces []string) (*Services, error) {
+	services := &Services{}
+	for _, ns := range namespaces {
+		kubeServices, err := kubeClient.GetServicesInNamespace(ns)
+		if err != nil {
+			return nil, err
+		}
+		services.Services = append(services.Services, slice.Map(builtin.Reference[v1.Service], kubeServices)...)
+
+		endpointSlices, err := kubeClient.GetEndpointSlicesInNamespace(ns)
+		if err != nil {
+			return nil, err
+		}
+		services.EndpointSlices = append(services.EndpointSlices, slice.Map(builtin.Reference[discoveryv1.EndpointSlice], endpointSlices)...)
+	}
+	return services, nil
+}
@@ -2,49 +2,71 @@ package matcher
 
 import (
 	"github.com/mattfenwick/cyclonus/pkg/kube"
-	"github.com/pkg/errors"
+	"github.com/mattfenwick/cyclonus/pkg/validation"
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
 )
 
-func BuildNetworkPolicies(simplify bool, netpols []*networkingv1.NetworkPolicy) *Policy {
+func BuildNetworkPolicies(simplify bool, netpols []*networkingv1.NetworkPolicy) (*Policy, error) {
 	return BuildV1AndV2NetPols(simplify, netpols, nil, nil)
 }
 
-func BuildV1AndV2NetPols(simplify bool, netpols []*networkingv1.NetworkPolicy, anps []*v1alpha1.AdminNetworkPolicy, banp *v1alpha1.BaselineAdminNetworkPolicy) *Policy {
+// BuildV1AndV2NetPols builds all valid policies.  Invalid policies are left out in their entirety,
+// and the problems with them are returned as ValidationErrors.
+func BuildV1AndV2NetPols(simplify bool, netpols []*networkingv1.NetworkPolicy, anps []*v1alpha1.AdminNetworkPolicy, banp *v1alpha1.BaselineAdminNetworkPolicy) (*Policy, error) {
 	np := NewPolicy()
+	var errs ValidationErrors
 	for _, p := range netpols {
-		ingress, egress := BuildTarget(p)
+		ingress, egress, targetErrs := BuildTarget(p)
+		if len(targetErrs) > 0 {
+			errs = append(errs, targetErrs...)
+			continue
+		}
 		np.AddTarget(true, ingress)
 		np.AddTarget(false, egress)
 	}
 
-	priorities := make(map[int32]struct{})
+	// every ANP sharing a priority is invalid, whether or not it's otherwise valid, since nothing decides
+	// which of them takes precedence
+	priorityCounts := make(map[int32]int)
+	for _, p := range anps {
+		priorityCounts[p.Spec.Priority]++
+	}
 	for _, p := range anps {
-		if _, ok := priorities[p.Spec.Priority]; ok {
-			panic(errors.Errorf("duplicate priorities are undefined. priority: %d", p.Spec.Priority))
+		ingress, egress, targetErrs := BuildTargetANP(p)
+		if priorityCounts[p.Spec.Priority] > 1 {
+			targetErrs = append(targetErrs, NewValidationErrors(netPolID(p), field.ErrorList{
+				field.Duplicate(field.NewPath("spec", "priority"), p.Spec.Priority),
+			})...)
+		}
+		if len(targetErrs) > 0 {
+			errs = append(errs, targetErrs...)
+			continue
 		}
-		priorities[p.Spec.Priority] = struct{}{}
-
-		ingress, egress := BuildTargetANP(p)
 		np.AddTarget(true, ingress)
 		np.AddTarget(false, egress)
 	}
 
 	if banp != nil {
 		// there can only be one BANP by definition
-		ingress, egress := BuildTargetBANP(banp)
-		np.AddTarget(true, ingress)
-		np.AddTarget(false, egress)
+		ingress, egress, targetErrs := BuildTargetBANP(banp)
+		if len(targetErrs) > 0 {
+			errs = append(errs, targetErrs...)
+		} else {
+			np.AddTarget(true, ingress)
+			np.AddTarget(false, egress)
+		}
 	}
 
 	if simplify {
 		np.Simplify()
 	}
 
-	return np
+	return np, errs.toError()
 }
 
 func getPolicyNamespace(policy *networkingv1.NetworkPolicy) string {
@@ -54,71 +76,105 @@ func getPolicyNamespace(policy *networkingv1.NetworkPolicy) string {
 	return policy.Namespace
 }
 
-func BuildTarget(netpol *networkingv1.NetworkPolicy) (*Target, *Target) {
+// BuildTarget builds the ingress and egress Targets of a NetworkPolicy.
+// If the policy is invalid, no Targets are returned, only ValidationErrors.
+func BuildTarget(netpol *networkingv1.NetworkPolicy) (*Target, *Target, ValidationErrors) {
 	var ingress *Target
 	var egress *Target
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
 	if len(netpol.Spec.PolicyTypes) == 0 {
-		panic(errors.Errorf("invalid NetworkPolicy: need at least 1 type"))
+		errs = append(errs, field.Required(specPath.Child("policyTypes"), "need at least 1 type"))
 	}
 	policyNamespace := getPolicyNamespace(netpol)
+	policyName := policyNamespace + "/" + netpol.Name
 	for _, pType := range netpol.Spec.PolicyTypes {
 		switch pType {
 		case networkingv1.PolicyTypeIngress:
-			ingress = &Target{
-				SubjectMatcher: NewSubjectV1(policyNamespace, netpol.Spec.PodSelector),
-				SourceRules:    []NetPolID{netPolID(netpol)},
-				Peers:          BuildIngressMatcher(policyNamespace, netpol.Spec.Ingress),
+			var rules []*V1Rule
+			for i, rule := range netpol.Spec.Ingress {
+				fldPath := specPath.Child("ingress").Index(i)
+				peers, peerErrs := BuildPeerMatcher(policyNamespace, rule.Ports, rule.From, fldPath.Child("ports"), fldPath.Child("from"))
+				errs = append(errs, peerErrs...)
+				rules = append(rules, &V1Rule{PolicyName: policyName, Path: rulePath("ingress", i), Peers: peers})
 			}
+			ingress = NewV1Target(policyNamespace, netpol.Spec.PodSelector, netPolID(netpol), rules)
 		case networkingv1.PolicyTypeEgress:
-			egress = &Target{
-				SubjectMatcher: NewSubjectV1(policyNamespace, netpol.Spec.PodSelector),
-				SourceRules:    []NetPolID{netPolID(netpol)},
-				Peers:          BuildEgressMatcher(policyNamespace, netpol.Spec.Egress),
+			var rules []*V1Rule
+			for i, rule := range netpol.Spec.Egress {
+				fldPath := specPath.Child("egress").Index(i)
+				peers, peerErrs := BuildPeerMatcher(policyNamespace, rule.Ports, rule.To, fldPath.Child("ports"), fldPath.Child("to"))
+				errs = append(errs, peerErrs...)
+				rules = append(rules, &V1Rule{PolicyName: policyName, Path: rulePath("egress", i), Peers: peers})
 			}
+			egress = NewV1Target(policyNamespace, netpol.Spec.PodSelector, netPolID(netpol), rules)
 		}
 	}
-	return ingress, egress
+	if len(errs) > 0 {
+		return nil, nil, NewValidationErrors(netPolID(netpol), errs)
+	}
+	return ingress, egress, nil
 }
 
-func BuildIngressMatcher(policyNamespace string, ingresses []networkingv1.NetworkPolicyIngressRule) []PeerMatcher {
+// rulePath locates a rule in its policy, e.g. "ingress[2]"
+func rulePath(direction string, index int) string {
+	return field.NewPath(direction).Index(index).String()
+}
+
+func BuildIngressMatcher(policyNamespace string, ingresses []networkingv1.NetworkPolicyIngressRule, fldPath *field.Path) ([]PeerMatcher, field.ErrorList) {
 	var matchers []PeerMatcher
-	for _, ingress := range ingresses {
-		matchers = append(matchers, BuildPeerMatcher(policyNamespace, ingress.Ports, ingress.From)...)
-	}
-	return matchers
+	var errs field.ErrorList
+	for i, ingress := range ingresses {
+		rulePath := fldPath.Index(i)
+		ruleMatchers, ruleErrs := BuildPeerMatcher(policyNamespace, ingress.Ports, ingress.From, rulePath.Child("ports"), rulePath.Child("from"))
+		matchers = append(matchers, ruleMatchers...)
+		errs = append(errs, ruleErrs...)
+	}
+	return matchers, errs
 }
 
-func BuildEgressMatcher(policyNamespace string, egresses []networkingv1.NetworkPolicyEgressRule) []PeerMatcher {
+func BuildEgressMatcher(policyNamespace string, egresses []networkingv1.NetworkPolicyEgressRule, fldPath *field.Path) ([]PeerMatcher, field.ErrorList) {
 	var matchers []PeerMatcher
-	for _, egress := range egresses {
-		matchers = append(matchers, BuildPeerMatcher(policyNamespace, egress.Ports, egress.To)...)
-	}
-	return matchers
+	var errs field.ErrorList
+	for i, egress := range egresses {
+		rulePath := fldPath.Index(i)
+		ruleMatchers, ruleErrs := BuildPeerMatcher(policyNamespace, egress.Ports, egress.To, rulePath.Child("ports"), rulePath.Child("to"))
+		matchers = append(matchers, ruleMatchers...)
+		errs = append(errs, ruleErrs...)
+	}
+	return matchers, errs
 }
 
-func BuildPeerMatcher(policyNamespace string, npPorts []networkingv1.NetworkPolicyPort, peers []networkingv1.NetworkPolicyPeer) []PeerMatcher {
+func BuildPeerMatcher(policyNamespace string, npPorts []networkingv1.NetworkPolicyPort, peers []networkingv1.NetworkPolicyPeer, portsPath *field.Path, peersPath *field.Path) ([]PeerMatcher, field.ErrorList) {
 	if len(npPorts) == 0 && len(peers) == 0 {
-		return []PeerMatcher{AllPeersPorts}
+		return []PeerMatcher{AllPeersPorts}, nil
 	}
 	// 1. build port matcher
-	port := BuildPortMatcher(npPorts)
+	port, errs := BuildPortMatcher(npPorts, portsPath)
 	// 2. build Peers
 	if len(peers) == 0 {
-		return []PeerMatcher{&PortsForAllPeersMatcher{Port: port}}
+		return []PeerMatcher{&PortsForAllPeersMatcher{Port: port}}, errs
 	}
 
 	var matchers []PeerMatcher
-	for _, from := range peers {
-		ip, ns, pod := BuildIPBlockNamespacePodMatcher(policyNamespace, from)
+	for i, from := range peers {
+		peerPath := peersPath.Index(i)
 		// invalid netpol guards
-		if ip == nil && ns == nil && pod == nil {
-			panic(errors.Errorf("invalid NetworkPolicyPeer: all of IPBlock, NamespaceSelector, and PodSelector are nil"))
+		if from.IPBlock == nil && from.NamespaceSelector == nil && from.PodSelector == nil {
+			errs = append(errs, field.Required(peerPath, "one of IPBlock, NamespaceSelector, or PodSelector must be set"))
+			continue
 		}
-		if ip != nil && (ns != nil || pod != nil) {
-			panic(errors.Errorf("invalid NetworkPolicyPeer: if NamespaceSelector or PodSelector is non-nil, IPBlock must be nil"))
+		if from.IPBlock != nil && (from.NamespaceSelector != nil || from.PodSelector != nil) {
+			errs = append(errs, field.Forbidden(peerPath.Child("ipBlock"), "if NamespaceSelector or PodSelector is non-nil, IPBlock must be nil"))
+			continue
 		}
 		// process a valid netpol
+		ip, ns, pod := BuildIPBlockNamespacePodMatcher(policyNamespace, from)
 		if ip != nil {
+			if ipErrs := validateIPBlock(ip.IPBlock, peerPath.Child("ipBlock")); len(ipErrs) > 0 {
+				errs = append(errs, ipErrs...)
+				continue
+			}
 			ip.Port = port
 			matchers = append(matchers, ip)
 		} else {
@@ -129,7 +185,15 @@ func BuildPeerMatcher(policyNamespace string, npPorts []networkingv1.NetworkPoli
 			})
 		}
 	}
-	return matchers
+	return matchers, errs
+}
+
+func validateIPBlock(ipBlock *networkingv1.IPBlock, fldPath *field.Path) field.ErrorList {
+	errs := validation.ValidateCIDR(ipBlock.CIDR, fldPath.Child("cidr"))
+	for i, except := range ipBlock.Except {
+		errs = append(errs, validation.ValidateCIDR(except, fldPath.Child("except").Index(i))...)
+	}
+	return errs
 }
 
 func BuildIPBlockNamespacePodMatcher(policyNamespace string, peer networkingv1.NetworkPolicyPeer) (*IPPeerMatcher, NamespaceMatcher, PodMatcher) {
@@ -161,24 +225,27 @@ func BuildIPBlockNamespacePodMatcher(policyNamespace string, peer networkingv1.N
 	return nil, nsMatcher, podMatcher
 }
 
-func BuildPortMatcher(npPorts []networkingv1.NetworkPolicyPort) PortMatcher {
+func BuildPortMatcher(npPorts []networkingv1.NetworkPolicyPort, fldPath *field.Path) (PortMatcher, field.ErrorList) {
 	if len(npPorts) == 0 {
-		return &AllPortMatcher{}
+		return &AllPortMatcher{}, nil
 	} else {
 		matcher := &SpecificPortMatcher{}
-		for _, p := range npPorts {
-			singlePort, portRange := BuildSinglePortMatcher(p)
-			if singlePort != nil {
+		var errs field.ErrorList
+		for i, p := range npPorts {
+			singlePort, portRange, err := BuildSinglePortMatcher(p, fldPath.Index(i))
+			if err != nil {
+				errs = append(errs, err)
+			} else if singlePort != nil {
 				matcher.Ports = append(matcher.Ports, singlePort)
 			} else {
 				matcher.PortRanges = append(matcher.PortRanges, portRange)
 			}
 		}
-		return matcher
+		return matcher, errs
 	}
 }
 
-func BuildSinglePortMatcher(npPort networkingv1.NetworkPolicyPort) (*PortProtocolMatcher, *PortRangeMatcher) {
+func BuildSinglePortMatcher(npPort networkingv1.NetworkPolicyPort, fldPath *field.Path) (*PortProtocolMatcher, *PortRangeMatcher, *field.Error) {
 	protocol := v1.ProtocolTCP
 	if npPort.Protocol != nil {
 		protocol = *npPort.Protocol
@@ -187,32 +254,38 @@ func BuildSinglePortMatcher(npPort networkingv1.NetworkPolicyPort) (*PortProtoco
 		return &PortProtocolMatcher{
 			Port:     npPort.Port,
 			Protocol: protocol,
-		}, nil
+		}, nil, nil
 	}
 	// we have a port range: make sure it's valid
 	if npPort.Port == nil {
-		panic(errors.Errorf("invalid port range: start port is nil"))
+		return nil, nil, field.Required(fldPath.Child("port"), "start port of port range must be set")
 	}
 	if npPort.Port.Type == intstr.String {
-		panic(errors.Errorf("invalid port range: start port is string"))
+		return nil, nil, field.Invalid(fldPath.Child("port"), npPort.Port.StrVal, "start port of port range must be a number")
 	}
 	if *npPort.EndPort < npPort.Port.IntVal {
-		panic(errors.Errorf("invalid port range: end port < start port"))
+		return nil, nil, field.Invalid(fldPath.Child("endPort"), *npPort.EndPort, "end port must not be less than start port")
 	}
 	return nil, &PortRangeMatcher{
 		From:     int(npPort.Port.IntVal),
 		To:       int(*npPort.EndPort),
 		Protocol: protocol,
-	}
+	}, nil
 }
 
-func BuildTargetANP(anp *v1alpha1.AdminNetworkPolicy) (*Target, *Target) {
+// BuildTargetANP builds the ingress and egress Targets of an AdminNetworkPolicy.
+// If the policy is invalid, no Targets are returned, only ValidationErrors.
+func BuildTargetANP(anp *v1alpha1.AdminNetworkPolicy) (*Target, *Target, ValidationErrors) {
+	specPath := field.NewPath("spec")
 	if len(anp.Spec.Ingress) == 0 && len(anp.Spec.Egress) == 0 {
-		panic(errors.Errorf("invalid AdminNetworkPolicy: need at least one egress or ingress rule"))
+		return nil, nil, NewValidationErrors(netPolID(anp), field.ErrorList{
+			field.Required(specPath, "need at least one egress or ingress rule"),
+		})
 	}
 
 	var ingress *Target
 	var egress *Target
+	var errs field.ErrorList
 
 	if len(anp.Spec.Ingress) > 0 {
 		ingress = &Target{
@@ -220,11 +293,14 @@ func BuildTargetANP(anp *v1alpha1.AdminNetworkPolicy) (*Target, *Target) {
 			SourceRules:    []NetPolID{netPolID(anp)},
 		}
 
-		for _, r := range anp.Spec.Ingress {
+		for i, r := range anp.Spec.Ingress {
+			ruleFldPath := specPath.Child("ingress").Index(i)
+			errs = append(errs, validation.ValidateANPAction(r.Action, ruleFldPath.Child("action"))...)
 			v := AdminActionToVerdict(r.Action)
-			matchers := BuildPeerMatcherAdmin(r.From, r.Ports)
+			matchers, ruleErrs := BuildPeerMatcherAdminIngress(r.From, r.Ports, ruleFldPath)
+			errs = append(errs, ruleErrs...)
 			for _, m := range matchers {
-				matcherAdmin := NewPeerMatcherANP(m, v, int(anp.Spec.Priority), anp.Name)
+				matcherAdmin := NewPeerMatcherANP(m, v, int(anp.Spec.Priority), anp.Name, r.Name, rulePath("ingress", i))
 				ingress.Peers = append(ingress.Peers, matcherAdmin)
 			}
 		}
@@ -236,26 +312,38 @@ func BuildTargetANP(anp *v1alpha1.AdminNetworkPolicy) (*Target, *Target) {
 			SourceRules:    []NetPolID{netPolID(anp)},
 		}
 
-		for _, r := range anp.Spec.Egress {
+		for i, r := range anp.Spec.Egress {
+			ruleFldPath := specPath.Child("egress").Index(i)
+			errs = append(errs, validation.ValidateANPAction(r.Action, ruleFldPath.Child("action"))...)
 			v := AdminActionToVerdict(r.Action)
-			matchers := BuildPeerMatcherAdmin(r.To, r.Ports)
+			matchers, ruleErrs := BuildPeerMatcherAdminEgress(r.To, r.Ports, ruleFldPath)
+			errs = append(errs, ruleErrs...)
 			for _, m := range matchers {
-				matcherAdmin := NewPeerMatcherANP(m, v, int(anp.Spec.Priority), anp.Name)
+				matcherAdmin := NewPeerMatcherANP(m, v, int(anp.Spec.Priority), anp.Name, r.Name, rulePath("egress", i))
 				egress.Peers = append(egress.Peers, matcherAdmin)
 			}
 		}
 	}
 
-	return ingress, egress
+	if len(errs) > 0 {
+		return nil, nil, NewValidationErrors(netPolID(anp), errs)
+	}
+	return ingress, egress, nil
 }
 
-func BuildTargetBANP(banp *v1alpha1.BaselineAdminNetworkPolicy) (*Target, *Target) {
+// BuildTargetBANP builds the ingress and egress Targets of a BaselineAdminNetworkPolicy.
+// If the policy is invalid, no Targets are returned, only ValidationErrors.
+func BuildTargetBANP(banp *v1alpha1.BaselineAdminNetworkPolicy) (*Target, *Target, ValidationErrors) {
+	specPath := field.NewPath("spec")
 	if len(banp.Spec.Ingress) == 0 && len(banp.Spec.Egress) == 0 {
-		panic(errors.Errorf("invalid BaselineAdminNetworkPolicy: need at least one egress or ingress rule"))
+		return nil, nil, NewValidationErrors(netPolID(banp), field.ErrorList{
+			field.Required(specPath, "need at least one egress or ingress rule"),
+		})
 	}
 
 	var ingress *Target
 	var egress *Target
+	var errs field.ErrorList
 
 	if len(banp.Spec.Ingress) > 0 {
 		ingress = &Target{
@@ -263,11 +351,14 @@ func BuildTargetBANP(banp *v1alpha1.BaselineAdminNetworkPolicy) (*Target, *Targe
 			SourceRules:    []NetPolID{netPolID(banp)},
 		}
 
-		for _, r := range banp.Spec.Ingress {
+		for i, r := range banp.Spec.Ingress {
+			ruleFldPath := specPath.Child("ingress").Index(i)
+			errs = append(errs, validation.ValidateBANPAction(r.Action, ruleFldPath.Child("action"))...)
 			v := BaselineAdminActionToVerdict(r.Action)
-			matchers := BuildPeerMatcherAdmin(r.From, r.Ports)
+			matchers, ruleErrs := BuildPeerMatcherAdminIngress(r.From, r.Ports, ruleFldPath)
+			errs = append(errs, ruleErrs...)
 			for _, m := range matchers {
-				matcherAdmin := NewPeerMatcherBANP(m, v, banp.Name)
+				matcherAdmin := NewPeerMatcherBANP(m, v, banp.Name, r.Name, rulePath("ingress", i))
 				ingress.Peers = append(ingress.Peers, matcherAdmin)
 			}
 		}
@@ -279,120 +370,202 @@ func BuildTargetBANP(banp *v1alpha1.BaselineAdminNetworkPolicy) (*Target, *Targe
 			SourceRules:    []NetPolID{netPolID(banp)},
 		}
 
-		for _, r := range banp.Spec.Egress {
+		for i, r := range banp.Spec.Egress {
+			ruleFldPath := specPath.Child("egress").Index(i)
+			errs = append(errs, validation.ValidateBANPAction(r.Action, ruleFldPath.Child("action"))...)
 			v := BaselineAdminActionToVerdict(r.Action)
-			matchers := BuildPeerMatcherAdmin(r.To, r.Ports)
+			matchers, ruleErrs := BuildPeerMatcherBANPEgress(r.To, r.Ports, ruleFldPath)
+			errs = append(errs, ruleErrs...)
 			for _, m := range matchers {
-				matcherAdmin := NewPeerMatcherBANP(m, v, banp.Name)
+				matcherAdmin := NewPeerMatcherBANP(m, v, banp.Name, r.Name, rulePath("egress", i))
 				egress.Peers = append(egress.Peers, matcherAdmin)
 			}
 		}
 	}
 
-	return ingress, egress
+	if len(errs) > 0 {
+		return nil, nil, NewValidationErrors(netPolID(banp), errs)
+	}
+	return ingress, egress, nil
 }
 
-func BuildPeerMatcherAdmin(peers []v1alpha1.AdminNetworkPolicyPeer, ports *[]v1alpha1.AdminNetworkPolicyPort) []*PodPeerMatcher {
+// BuildPeerMatcherAdminIngress builds matchers for the "from" field of an ANP or BANP ingress rule at fldPath.
+func BuildPeerMatcherAdminIngress(peers []v1alpha1.AdminNetworkPolicyIngressPeer, ports *[]v1alpha1.AdminNetworkPolicyPort, fldPath *field.Path) ([]*PodPeerMatcher, field.ErrorList) {
 	if len(peers) == 0 {
-		panic(errors.Errorf("invalid admin to/from field: must have at least one peer"))
+		return nil, field.ErrorList{field.Required(fldPath.Child("from"), "must have at least one peer")}
 	}
 
-	// 1. build port matcher
-	var portMatcher PortMatcher
-	if ports == nil {
-		portMatcher = BuildPortMatcherAdmin(nil)
-	} else {
-		portMatcher = BuildPortMatcherAdmin(*ports)
-	}
+	portMatcher, errs := buildPortMatcherAdminFromPointer(ports, fldPath.Child("ports"))
 
-	// 2. build Peers
 	var peerMatchers []*PodPeerMatcher
-	for _, peer := range peers {
-		if (peer.Namespaces == nil && peer.Pods == nil) || (peer.Namespaces != nil && peer.Pods != nil) {
-			panic(errors.Errorf("invalid admin peer: must have exactly one of Namespaces or Pods"))
+	for i, peer := range peers {
+		peerMatcher, err := buildPodPeerMatcherAdmin(peer.Namespaces, peer.Pods, portMatcher, fldPath.Child("from").Index(i))
+		if err != nil {
+			errs = append(errs, err)
+			continue
 		}
+		peerMatchers = append(peerMatchers, peerMatcher)
+	}
+	return peerMatchers, errs
+}
 
-		var ns v1alpha1.NamespacedPeer
-		var podMatcher PodMatcher
-		if peer.Pods != nil {
-			ns = peer.Pods.Namespaces
+// BuildPeerMatcherAdminEgress builds matchers for the "to" field of an ANP egress rule at fldPath.
+func BuildPeerMatcherAdminEgress(peers []v1alpha1.AdminNetworkPolicyEgressPeer, ports *[]v1alpha1.AdminNetworkPolicyPort, fldPath *field.Path) ([]PeerMatcher, field.ErrorList) {
+	if len(peers) == 0 {
+		return nil, field.ErrorList{field.Required(fldPath.Child("to"), "must have at least one peer")}
+	}
 
-			// TODO account for Tenancy or Pod same/not-same labels when these become features
-			podSel := peer.Pods.PodSelector
-			if kube.IsLabelSelectorEmpty(podSel) {
-				podMatcher = &AllPodMatcher{}
-			} else {
-				podMatcher = &LabelSelectorPodMatcher{Selector: podSel}
+	portMatcher, errs := buildPortMatcherAdminFromPointer(ports, fldPath.Child("ports"))
+
+	var peerMatchers []PeerMatcher
+	for i, peer := range peers {
+		peerPath := fldPath.Child("to").Index(i)
+		if len(peer.DomainNames) > 0 {
+			if peer.Namespaces != nil || peer.Pods != nil || peer.Nodes != nil || len(peer.Networks) > 0 {
+				errs = append(errs, field.Invalid(peerPath, peer, "must have exactly one of Namespaces, Pods, Nodes, Networks, or DomainNames"))
+				continue
 			}
-		} else {
-			// peer.Namespaces is non-nil
-			ns = *peer.Namespaces
-			podMatcher = &AllPodMatcher{}
+			for _, domainName := range peer.DomainNames {
+				peerMatchers = append(peerMatchers, &DomainPeerMatcher{DomainName: string(domainName), Port: portMatcher})
+			}
+			continue
 		}
-
-		nonNilCount := 0
-		if ns.NamespaceSelector != nil {
-			nonNilCount++
+		if peer.Nodes != nil || len(peer.Networks) > 0 {
+			if peer.Namespaces != nil || peer.Pods != nil || (peer.Nodes != nil && len(peer.Networks) > 0) {
+				errs = append(errs, field.Invalid(peerPath, peer, "must have exactly one of Namespaces, Pods, Nodes, Networks, or DomainNames"))
+				continue
+			}
+			matchers, peerErrs := buildNodeOrIPPeerMatchersAdmin(peer.Nodes, peer.Networks, portMatcher, peerPath)
+			errs = append(errs, peerErrs...)
+			peerMatchers = append(peerMatchers, matchers...)
+			continue
 		}
-		if ns.SameLabels != nil {
-			nonNilCount++
+		peerMatcher, err := buildPodPeerMatcherAdmin(peer.Namespaces, peer.Pods, portMatcher, peerPath)
+		if err != nil {
+			errs = append(errs, err)
+			continue
 		}
-		if ns.NotSameLabels != nil {
-			nonNilCount++
+		peerMatchers = append(peerMatchers, peerMatcher)
+	}
+	return peerMatchers, errs
+}
+
+// BuildPeerMatcherBANPEgress builds matchers for the "to" field of a BANP egress rule at fldPath.
+func BuildPeerMatcherBANPEgress(peers []v1alpha1.BaselineAdminNetworkPolicyEgressPeer, ports *[]v1alpha1.AdminNetworkPolicyPort, fldPath *field.Path) ([]PeerMatcher, field.ErrorList) {
+	if len(peers) == 0 {
+		return nil, field.ErrorList{field.Required(fldPath.Child("to"), "must have at least one peer")}
+	}
+
+	portMatcher, errs := buildPortMatcherAdminFromPointer(ports, fldPath.Child("ports"))
+
+	var peerMatchers []PeerMatcher
+	for i, peer := range peers {
+		peerPath := fldPath.Child("to").Index(i)
+		if peer.Nodes != nil || len(peer.Networks) > 0 {
+			if peer.Namespaces != nil || peer.Pods != nil || (peer.Nodes != nil && len(peer.Networks) > 0) {
+				errs = append(errs, field.Invalid(peerPath, peer, "must have exactly one of Namespaces, Pods, Nodes, or Networks"))
+				continue
+			}
+			matchers, peerErrs := buildNodeOrIPPeerMatchersAdmin(peer.Nodes, peer.Networks, portMatcher, peerPath)
+			errs = append(errs, peerErrs...)
+			peerMatchers = append(peerMatchers, matchers...)
+			continue
 		}
-		if nonNilCount != 1 {
-			panic(errors.Errorf("invalid admin peer: must have exactly one of NamespaceSelector, SameLabels, or NotSameLabels"))
+		peerMatcher, err := buildPodPeerMatcherAdmin(peer.Namespaces, peer.Pods, portMatcher, peerPath)
+		if err != nil {
+			errs = append(errs, err)
+			continue
 		}
+		peerMatchers = append(peerMatchers, peerMatcher)
+	}
+	return peerMatchers, errs
+}
 
-		var nsMatcher NamespaceMatcher
-		if ns.NamespaceSelector != nil {
-			if kube.IsLabelSelectorEmpty(*ns.NamespaceSelector) {
-				nsMatcher = &AllNamespaceMatcher{}
-			} else {
-				nsMatcher = &LabelSelectorNamespaceMatcher{
-					Selector: *ns.NamespaceSelector,
-				}
-			}
-		} else if ns.SameLabels != nil {
-			nsMatcher = &SameLabelsNamespaceMatcher{
-				labels: ns.SameLabels,
-			}
-		} else {
-			// ns.NotSameLabels is non-nil
-			nsMatcher = &NotSameLabelsNamespaceMatcher{
-				labels: ns.NotSameLabels,
-			}
+// buildNodeOrIPPeerMatchersAdmin handles the egress-only Nodes and Networks peers.
+// For networks, it builds one IPPeerMatcher per CIDR.
+// Unlike v1 IPBlocks, networks peers have no excepts, and also match cluster-internal traffic.
+func buildNodeOrIPPeerMatchersAdmin(nodes *metav1.LabelSelector, networks []v1alpha1.CIDR, portMatcher PortMatcher, fldPath *field.Path) ([]PeerMatcher, field.ErrorList) {
+	if nodes != nil {
+		return []PeerMatcher{&NodePeerMatcher{Selector: *nodes, Port: portMatcher}}, nil
+	}
+
+	var matchers []PeerMatcher
+	var errs field.ErrorList
+	for i, cidr := range networks {
+		if cidrErrs := validation.ValidateNetworkCIDR(string(cidr), fldPath.Child("networks").Index(i)); len(cidrErrs) > 0 {
+			errs = append(errs, cidrErrs...)
+			continue
 		}
+		matchers = append(matchers, &IPPeerMatcher{
+			IPBlock: &networkingv1.IPBlock{CIDR: string(cidr)},
+			Port:    portMatcher,
+		})
+	}
+	return matchers, errs
+}
+
+func buildPortMatcherAdminFromPointer(ports *[]v1alpha1.AdminNetworkPolicyPort, fldPath *field.Path) (PortMatcher, field.ErrorList) {
+	if ports == nil {
+		return BuildPortMatcherAdmin(nil, fldPath)
+	}
+	return BuildPortMatcherAdmin(*ports, fldPath)
+}
+
+// buildPodPeerMatcherAdmin handles the Namespaces and Pods peers, which are shared by all ANP/BANP peer types.
+func buildPodPeerMatcherAdmin(namespaces *metav1.LabelSelector, pods *v1alpha1.NamespacedPod, portMatcher PortMatcher, fldPath *field.Path) (*PodPeerMatcher, *field.Error) {
+	if (namespaces == nil && pods == nil) || (namespaces != nil && pods != nil) {
+		return nil, field.Invalid(fldPath, map[string]interface{}{"namespaces": namespaces, "pods": pods}, "must have exactly one of Namespaces or Pods")
+	}
 
-		m := &PodPeerMatcher{
-			Namespace: nsMatcher,
-			Pod:       podMatcher,
-			Port:      portMatcher,
+	var nsSel metav1.LabelSelector
+	var podMatcher PodMatcher
+	if pods != nil {
+		nsSel = pods.NamespaceSelector
+		if kube.IsLabelSelectorEmpty(pods.PodSelector) {
+			podMatcher = &AllPodMatcher{}
+		} else {
+			podMatcher = &LabelSelectorPodMatcher{Selector: pods.PodSelector}
 		}
-		peerMatchers = append(peerMatchers, m)
+	} else {
+		nsSel = *namespaces
+		podMatcher = &AllPodMatcher{}
 	}
 
-	return peerMatchers
+	var nsMatcher NamespaceMatcher
+	if kube.IsLabelSelectorEmpty(nsSel) {
+		nsMatcher = &AllNamespaceMatcher{}
+	} else {
+		nsMatcher = &LabelSelectorNamespaceMatcher{Selector: nsSel}
+	}
+
+	return &PodPeerMatcher{
+		Namespace: nsMatcher,
+		Pod:       podMatcher,
+		Port:      portMatcher,
+	}, nil
 }
 
-func BuildPortMatcherAdmin(ports []v1alpha1.AdminNetworkPolicyPort) PortMatcher {
+func BuildPortMatcherAdmin(ports []v1alpha1.AdminNetworkPolicyPort, fldPath *field.Path) (PortMatcher, field.ErrorList) {
 	if len(ports) == 0 {
-		return &AllPortMatcher{}
+		return &AllPortMatcher{}, nil
 	} else {
 		matcher := &SpecificPortMatcher{}
-		for _, p := range ports {
-			singlePort, portRange := BuildSinglePortMatcherAdmin(p)
-			if singlePort != nil {
+		var errs field.ErrorList
+		for i, p := range ports {
+			singlePort, portRange, err := BuildSinglePortMatcherAdmin(p, fldPath.Index(i))
+			if err != nil {
+				errs = append(errs, err)
+			} else if singlePort != nil {
 				matcher.Ports = append(matcher.Ports, singlePort)
 			} else {
 				matcher.PortRanges = append(matcher.PortRanges, portRange)
 			}
 		}
-		return matcher
+		return matcher, errs
 	}
 }
 
-func BuildSinglePortMatcherAdmin(port v1alpha1.AdminNetworkPolicyPort) (*PortProtocolMatcher, *PortRangeMatcher) {
+func BuildSinglePortMatcherAdmin(port v1alpha1.AdminNetworkPolicyPort, fldPath *field.Path) (*PortProtocolMatcher, *PortRangeMatcher, *field.Error) {
 	nonNilCount := 0
 	if port.PortNumber != nil {
 		nonNilCount++
@@ -404,7 +577,7 @@ func BuildSinglePortMatcherAdmin(port v1alpha1.AdminNetworkPolicyPort) (*PortPro
 		nonNilCount++
 	}
 	if nonNilCount != 1 {
-		panic(errors.Errorf("invalid port: must have exactly one of PortNumber, NamedPort, or PortRange"))
+		return nil, nil, field.Invalid(fldPath, port, "must have exactly one of PortNumber, NamedPort, or PortRange")
 	}
 
 	if port.PortNumber != nil {
@@ -419,7 +592,7 @@ func BuildSinglePortMatcherAdmin(port v1alpha1.AdminNetworkPolicyPort) (*PortPro
 			Protocol: proto,
 		}
 
-		return m, nil
+		return m, nil, nil
 	}
 
 	if port.NamedPort != nil {
@@ -439,7 +612,7 @@ func BuildSinglePortMatcherAdmin(port v1alpha1.AdminNetworkPolicyPort) (*PortPro
 			Protocol: proto,
 		}
 
-		return m, nil
+		return m, nil, nil
 	}
 
 	// port.PortRange is non-nil
@@ -450,14 +623,14 @@ func BuildSinglePortMatcherAdmin(port v1alpha1.AdminNetworkPolicyPort) (*PortPro
 	}
 
 	if port.PortRange.Start >= port.PortRange.End {
-		panic(errors.Errorf("invalid port range: start >= end"))
+		return nil, nil, field.Invalid(fldPath.Child("portRange"), port.PortRange, "start must be less than end")
 	}
 
 	return nil, &PortRangeMatcher{
 		From:     int(port.PortRange.Start),
 		To:       int(port.PortRange.End),
 		Protocol: proto,
-	}
+	}, nil
 }
 
 func endsIn(s string, suffix string) bool {
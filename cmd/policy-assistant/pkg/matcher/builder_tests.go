@@ -1,15 +1,21 @@
 package matcher
 
 import (
+	"github.com/mattfenwick/collections/pkg/slice"
 	"github.com/mattfenwick/cyclonus/examples"
 	"github.com/mattfenwick/cyclonus/pkg/kube/netpol"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	"github.com/mattfenwick/cyclonus/pkg/validation"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"golang.org/x/exp/maps"
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
 )
 
 var (
@@ -20,12 +26,18 @@ var (
 	port53  = intstr.FromInt(53)
 	port80  = intstr.FromInt(80)
 	port103 = intstr.FromInt(103)
+
+	ingressPath = field.NewPath("spec", "ingress")
+	egressPath  = field.NewPath("spec", "egress")
+	portsPath   = ingressPath.Index(0).Child("ports")
+	fromPath    = ingressPath.Index(0).Child("from")
 )
 
 func RunBuilderTests() {
 	Describe("BuildTarget: Allow none -- nil egress/ingress", func() {
 		It("allow-no-ingress", func() {
-			ingress, egress := BuildTarget(netpol.AllowNoIngress)
+			ingress, egress, err := BuildTarget(netpol.AllowNoIngress)
+			Expect(err).To(BeNil())
 
 			Expect(ingress).ToNot(BeNil())
 			Expect(ingress.Peers).To(BeNil())
@@ -34,7 +46,8 @@ func RunBuilderTests() {
 		})
 
 		It("allow-no-egress", func() {
-			ingress, egress := BuildTarget(netpol.AllowNoEgress)
+			ingress, egress, err := BuildTarget(netpol.AllowNoEgress)
+			Expect(err).To(BeNil())
 
 			Expect(egress).ToNot(BeNil())
 			Expect(egress.Peers).To(BeNil())
@@ -43,7 +56,8 @@ func RunBuilderTests() {
 		})
 
 		It("allow-neither", func() {
-			ingress, egress := BuildTarget(netpol.AllowNoIngressAllowNoEgress)
+			ingress, egress, err := BuildTarget(netpol.AllowNoIngressAllowNoEgress)
+			Expect(err).To(BeNil())
 
 			Expect(egress).ToNot(BeNil())
 			Expect(egress.Peers).To(BeNil())
@@ -55,7 +69,7 @@ func RunBuilderTests() {
 
 	Describe("BuildTarget: missing namespace gets treated as default namespace", func() {
 		It("missing namespace", func() {
-			ingress, egress := BuildTarget(&networkingv1.NetworkPolicy{
+			ingress, egress, err := BuildTarget(&networkingv1.NetworkPolicy{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "abc",
 				},
@@ -64,6 +78,7 @@ func RunBuilderTests() {
 					Ingress:     []networkingv1.NetworkPolicyIngressRule{},
 					PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
 				}})
+			Expect(err).To(BeNil())
 
 			Expect(ingress.SubjectMatcher.(*SubjectV1).namespace).To(Equal("default"))
 			Expect(egress.SubjectMatcher.(*SubjectV1).namespace).To(Equal("default"))
@@ -72,7 +87,8 @@ func RunBuilderTests() {
 
 	Describe("BuildTarget: Allow none -- empty ingress/egress", func() {
 		It("allow-no-ingress", func() {
-			ingress, egress := BuildTarget(netpol.AllowNoIngress_EmptyIngress)
+			ingress, egress, err := BuildTarget(netpol.AllowNoIngress_EmptyIngress)
+			Expect(err).To(BeNil())
 
 			Expect(ingress).ToNot(BeNil())
 			Expect(ingress.Peers).To(BeNil())
@@ -81,7 +97,8 @@ func RunBuilderTests() {
 		})
 
 		It("allow-no-egress", func() {
-			ingress, egress := BuildTarget(netpol.AllowNoEgress_EmptyEgress)
+			ingress, egress, err := BuildTarget(netpol.AllowNoEgress_EmptyEgress)
+			Expect(err).To(BeNil())
 
 			Expect(egress).ToNot(BeNil())
 			Expect(egress.Peers).To(BeNil())
@@ -90,7 +107,8 @@ func RunBuilderTests() {
 		})
 
 		It("allow-neither", func() {
-			ingress, egress := BuildTarget(netpol.AllowNoIngressAllowNoEgress_EmptyEgressEmptyIngress)
+			ingress, egress, err := BuildTarget(netpol.AllowNoIngressAllowNoEgress_EmptyEgressEmptyIngress)
+			Expect(err).To(BeNil())
 
 			Expect(egress).ToNot(BeNil())
 			Expect(egress.Peers).To(BeNil())
@@ -102,21 +120,24 @@ func RunBuilderTests() {
 
 	Describe("BuildTarget: Allow all", func() {
 		It("allow-all-ingress", func() {
-			ingress, egress := BuildTarget(netpol.AllowAllIngress)
+			ingress, egress, err := BuildTarget(netpol.AllowAllIngress)
+			Expect(err).To(BeNil())
 
 			Expect(egress).To(BeNil())
 			Expect(ingress.Peers).To(Equal([]PeerMatcher{AllPeersPorts}))
 		})
 
 		It("allow-all-egress", func() {
-			ingress, egress := BuildTarget(netpol.AllowAllEgress)
+			ingress, egress, err := BuildTarget(netpol.AllowAllEgress)
+			Expect(err).To(BeNil())
 
 			Expect(egress.Peers).To(Equal([]PeerMatcher{AllPeersPorts}))
 			Expect(ingress).To(BeNil())
 		})
 
 		It("allow-all-both", func() {
-			ingress, egress := BuildTarget(netpol.AllowAllIngressAllowAllEgress)
+			ingress, egress, err := BuildTarget(netpol.AllowAllIngressAllowAllEgress)
+			Expect(err).To(BeNil())
 
 			Expect(egress.Peers).To(Equal([]PeerMatcher{AllPeersPorts}))
 			Expect(ingress.Peers).To(Equal([]PeerMatcher{AllPeersPorts}))
@@ -127,31 +148,35 @@ func RunBuilderTests() {
 
 	Describe("PeerMatcher from slice of ingress/egress rules", func() {
 		It("allows no ingress from an empty slice of ingress rules", func() {
-			peers := BuildIngressMatcher("abc", []networkingv1.NetworkPolicyIngressRule{})
+			peers, errs := BuildIngressMatcher("abc", []networkingv1.NetworkPolicyIngressRule{}, ingressPath)
+			Expect(errs).To(BeEmpty())
 			Expect(peers).To(BeNil())
 		})
 
 		It("allows no egress from an empty slice of egress rules", func() {
-			peers := BuildEgressMatcher("abc", []networkingv1.NetworkPolicyEgressRule{})
+			peers, errs := BuildEgressMatcher("abc", []networkingv1.NetworkPolicyEgressRule{}, egressPath)
+			Expect(errs).To(BeEmpty())
 			Expect(peers).To(BeNil())
 		})
 
 		It("allows all ingress from an ingress containing a single empty rule", func() {
-			peers := BuildIngressMatcher("abc", []networkingv1.NetworkPolicyIngressRule{
+			peers, errs := BuildIngressMatcher("abc", []networkingv1.NetworkPolicyIngressRule{
 				{Ports: nil, From: nil},
-			})
+			}, ingressPath)
+			Expect(errs).To(BeEmpty())
 			Expect(peers).To(Equal([]PeerMatcher{AllPeersPorts}))
 		})
 
 		It("allows all egress from an ingress containing a single empty rule", func() {
-			peers := BuildEgressMatcher("abc", []networkingv1.NetworkPolicyEgressRule{
+			peers, errs := BuildEgressMatcher("abc", []networkingv1.NetworkPolicyEgressRule{
 				{Ports: nil, To: nil},
-			})
+			}, egressPath)
+			Expect(errs).To(BeEmpty())
 			Expect(peers).To(Equal([]PeerMatcher{AllPeersPorts}))
 		})
 
 		It("allows to ips in IPBlock range and also to all pods/ips for DNS", func() {
-			peers := BuildEgressMatcher("abc", []networkingv1.NetworkPolicyEgressRule{
+			peers, errs := BuildEgressMatcher("abc", []networkingv1.NetworkPolicyEgressRule{
 				{
 					Ports: []networkingv1.NetworkPolicyPort{{Port: &port80, Protocol: &tcp}},
 					To: []networkingv1.NetworkPolicyPeer{
@@ -162,7 +187,8 @@ func RunBuilderTests() {
 				{
 					Ports: []networkingv1.NetworkPolicyPort{{Port: &port53, Protocol: &udp}},
 				},
-			})
+			}, egressPath)
+			Expect(errs).To(BeEmpty())
 			port53UDPMatcher := &SpecificPortMatcher{Ports: []*PortProtocolMatcher{{Port: &port53, Protocol: v1.ProtocolUDP}}}
 			port80TCPMatcher := &SpecificPortMatcher{Ports: []*PortProtocolMatcher{{Port: &port80, Protocol: v1.ProtocolTCP}}}
 			ip := &IPPeerMatcher{
@@ -186,15 +212,17 @@ func RunBuilderTests() {
 
 	Describe("PeerMatcher from slice of NetworkPolicyPeer", func() {
 		It("allows all source/destination from an empty slice", func() {
-			sds := BuildPeerMatcher("abc", []networkingv1.NetworkPolicyPort{}, []networkingv1.NetworkPolicyPeer{})
+			sds, errs := BuildPeerMatcher("abc", []networkingv1.NetworkPolicyPort{}, []networkingv1.NetworkPolicyPeer{}, portsPath, fromPath)
+			Expect(errs).To(BeEmpty())
 			Expect(sds).To(Equal([]PeerMatcher{AllPeersPorts}))
 		})
 
 		It("allows all ips and all pods over a specific port from an empty peer slice", func() {
-			sds := BuildPeerMatcher("abc", []networkingv1.NetworkPolicyPort{{
+			sds, errs := BuildPeerMatcher("abc", []networkingv1.NetworkPolicyPort{{
 				Protocol: &sctp,
 				Port:     &port103,
-			}}, []networkingv1.NetworkPolicyPeer{})
+			}}, []networkingv1.NetworkPolicyPeer{}, portsPath, fromPath)
+			Expect(errs).To(BeEmpty())
 			portMatcher := &SpecificPortMatcher{Ports: []*PortProtocolMatcher{
 				{Port: &port103, Protocol: v1.ProtocolSCTP},
 			}}
@@ -203,9 +231,10 @@ func RunBuilderTests() {
 		})
 
 		It("allows ips, but no pods from a single IPBlock", func() {
-			peers := BuildPeerMatcher("abc", []networkingv1.NetworkPolicyPort{}, []networkingv1.NetworkPolicyPeer{
+			peers, errs := BuildPeerMatcher("abc", []networkingv1.NetworkPolicyPort{}, []networkingv1.NetworkPolicyPeer{
 				{IPBlock: netpol.IPBlock_10_0_0_1_24},
-			})
+			}, portsPath, fromPath)
+			Expect(errs).To(BeEmpty())
 			ip := &IPPeerMatcher{
 				IPBlock: netpol.IPBlock_10_0_0_1_24,
 				Port:    &AllPortMatcher{},
@@ -214,20 +243,22 @@ func RunBuilderTests() {
 		})
 
 		It("allows all ns/pods/ports, but no ips from a single peer with empty pod/ns selectors", func() {
-			peers := BuildPeerMatcher("abc", []networkingv1.NetworkPolicyPort{}, []networkingv1.NetworkPolicyPeer{
+			peers, errs := BuildPeerMatcher("abc", []networkingv1.NetworkPolicyPort{}, []networkingv1.NetworkPolicyPeer{
 				{
 					PodSelector:       netpol.SelectorEmpty,
 					NamespaceSelector: netpol.SelectorEmpty,
 				},
-			})
+			}, portsPath, fromPath)
+			Expect(errs).To(BeEmpty())
 			Expect(peers).To(Equal([]PeerMatcher{
 				&PodPeerMatcher{Namespace: &AllNamespaceMatcher{}, Pod: &AllPodMatcher{}, Port: &AllPortMatcher{}}}))
 		})
 
 		It("allows ns/pods, but no ips from a single namespace/pod", func() {
-			peers := BuildPeerMatcher("abc", []networkingv1.NetworkPolicyPort{}, []networkingv1.NetworkPolicyPeer{
+			peers, errs := BuildPeerMatcher("abc", []networkingv1.NetworkPolicyPort{}, []networkingv1.NetworkPolicyPeer{
 				{PodSelector: netpol.SelectorEmpty},
-			})
+			}, portsPath, fromPath)
+			Expect(errs).To(BeEmpty())
 			matcher := &PodPeerMatcher{
 				Namespace: &ExactNamespaceMatcher{Namespace: "abc"},
 				Pod:       &AllPodMatcher{},
@@ -314,18 +345,21 @@ func RunBuilderTests() {
 
 	Describe("Port from NetworkPolicyPort", func() {
 		It("allows all ports and all protocols from an empty slice", func() {
-			pm := BuildPortMatcher([]networkingv1.NetworkPolicyPort{})
+			pm, errs := BuildPortMatcher([]networkingv1.NetworkPolicyPort{}, portsPath)
+			Expect(errs).To(BeEmpty())
 			Expect(pm).To(Equal(&AllPortMatcher{}))
 		})
 
 		It("allow all ports on protocol", func() {
-			pm := BuildPortMatcher([]networkingv1.NetworkPolicyPort{netpol.AllowAllPortsOnProtocol})
+			pm, errs := BuildPortMatcher([]networkingv1.NetworkPolicyPort{netpol.AllowAllPortsOnProtocol}, portsPath)
+			Expect(errs).To(BeEmpty())
 			Expect(pm).To(Equal(&SpecificPortMatcher{Ports: []*PortProtocolMatcher{{Port: nil, Protocol: v1.ProtocolSCTP}}}))
 		})
 
 		It("allow numbered port on protocol", func() {
 			portNumber := intstr.FromInt(9001)
-			pm := BuildPortMatcher([]networkingv1.NetworkPolicyPort{netpol.AllowNumberedPortOnProtocol})
+			pm, errs := BuildPortMatcher([]networkingv1.NetworkPolicyPort{netpol.AllowNumberedPortOnProtocol}, portsPath)
+			Expect(errs).To(BeEmpty())
 			Expect(pm).To(Equal(&SpecificPortMatcher{Ports: []*PortProtocolMatcher{{
 				Protocol: v1.ProtocolTCP,
 				Port:     &portNumber,
@@ -334,7 +368,8 @@ func RunBuilderTests() {
 
 		It("allow named port on protocol", func() {
 			portName := intstr.FromString("hello")
-			pm := BuildPortMatcher([]networkingv1.NetworkPolicyPort{netpol.AllowNamedPortOnProtocol})
+			pm, errs := BuildPortMatcher([]networkingv1.NetworkPolicyPort{netpol.AllowNamedPortOnProtocol}, portsPath)
+			Expect(errs).To(BeEmpty())
 			Expect(pm).To(Equal(&SpecificPortMatcher{Ports: []*PortProtocolMatcher{{
 				Protocol: v1.ProtocolUDP,
 				Port:     &portName,
@@ -344,11 +379,230 @@ func RunBuilderTests() {
 
 	Describe("BuildV1AndV2NetPols", func() {
 		It("it combines ANPs with same subject", func() {
-			result := BuildV1AndV2NetPols(true, nil, examples.SimpleANPs, nil)
+			result, err := BuildV1AndV2NetPols(true, nil, examples.SimpleANPs, nil)
+			Expect(err).To(BeNil())
 			Expect(result.Egress).To(HaveLen(1))
 			k := maps.Keys(result.Egress)
 			firstRule := result.Egress[k[0]]
 			Expect(firstRule.SourceRules).To(HaveLen(2))
 		})
 	})
+
+	Describe("BuildV1AndV2NetPols: invalid policies", func() {
+		netpolYaml := `
+- apiVersion: networking.k8s.io/v1
+  kind: NetworkPolicy
+  metadata:
+    name: valid
+    namespace: x
+  spec:
+    podSelector: {}
+    policyTypes:
+    - Ingress
+- apiVersion: networking.k8s.io/v1
+  kind: NetworkPolicy
+  metadata:
+    name: missing-policy-types
+    namespace: x
+  spec:
+    podSelector: {}
+- apiVersion: networking.k8s.io/v1
+  kind: NetworkPolicy
+  metadata:
+    name: bad-peers
+    namespace: z
+  spec:
+    podSelector: {}
+    policyTypes:
+    - Egress
+    egress:
+    - to:
+      - ipBlock:
+          cidr: 1.2.3.4/99
+      - {}
+      ports:
+      - port: 90
+        endPort: 80`
+		anpYaml := `
+- apiVersion: policy.networking.k8s.io/v1alpha1
+  kind: AdminNetworkPolicy
+  metadata:
+    name: first
+  spec:
+    priority: 10
+    subject:
+      namespaces: {}
+    egress:
+    - name: bad-network
+      action: Deny
+      to:
+      - networks:
+        - not-a-cidr
+- apiVersion: policy.networking.k8s.io/v1alpha1
+  kind: AdminNetworkPolicy
+  metadata:
+    name: second
+  spec:
+    priority: 20
+    subject:
+      namespaces: {}
+    ingress:
+    - name: deny-all
+      action: Deny
+      from:
+      - namespaces: {}
+- apiVersion: policy.networking.k8s.io/v1alpha1
+  kind: AdminNetworkPolicy
+  metadata:
+    name: duplicate-priority
+  spec:
+    priority: 20
+    subject:
+      namespaces: {}
+    ingress:
+    - name: allow-all
+      action: Allow
+      from:
+      - namespaces: {}`
+		netpols, err := utils.ParseYaml[[]*networkingv1.NetworkPolicy]([]byte(netpolYaml))
+		utils.DoOrDie(err)
+		anps, err := utils.ParseYaml[[]*v1alpha1.AdminNetworkPolicy]([]byte(anpYaml))
+		utils.DoOrDie(err)
+
+		It("builds the valid policies and reports all the problems with the invalid ones", func() {
+			policy, err := BuildV1AndV2NetPols(false, *netpols, *anps, nil)
+			Expect(policy).ToNot(BeNil())
+			Expect(policy.Ingress).To(HaveLen(1))
+			Expect(policy.Egress).To(BeEmpty())
+
+			var validationErrors ValidationErrors
+			Expect(errors.As(err, &validationErrors)).To(BeTrue())
+			Expect(validationErrors.InvalidPolicies()).To(Equal([]NetPolID{
+				"[NPv1] x/missing-policy-types",
+				"[NPv1] z/bad-peers",
+				"[ANP] default/first",
+				"[ANP] default/second",
+				"[ANP] default/duplicate-priority",
+			}))
+			fields := slice.Map(func(e *ValidationError) string { return e.Field }, validationErrors)
+			Expect(fields).To(Equal([]string{
+				"spec.policyTypes",
+				"spec.egress[0].ports[0].endPort",
+				"spec.egress[0].to[0].ipBlock.cidr",
+				"spec.egress[0].to[1]",
+				"spec.egress[0].to[0].networks[0]",
+				"spec.priority",
+				"spec.priority",
+			}))
+		})
+
+		It("doesn't return an error for valid policies", func() {
+			_, err := BuildV1AndV2NetPols(false, (*netpols)[:1], (*anps)[1:2], nil)
+			Expect(err).To(BeNil())
+		})
+
+		It("reports every ANP sharing a priority, whatever their order and validity", func() {
+			samePriority := func(name string, action v1alpha1.AdminNetworkPolicyRuleAction) *v1alpha1.AdminNetworkPolicy {
+				return &v1alpha1.AdminNetworkPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec: v1alpha1.AdminNetworkPolicySpec{
+						Priority: 40,
+						Subject:  v1alpha1.AdminNetworkPolicySubject{Namespaces: &metav1.LabelSelector{}},
+						Ingress: []v1alpha1.AdminNetworkPolicyIngressRule{{
+							Name:   "rule",
+							Action: action,
+							From:   []v1alpha1.AdminNetworkPolicyIngressPeer{{Namespaces: &metav1.LabelSelector{}}},
+						}},
+					},
+				}
+			}
+			invalid := samePriority("invalid", "allow")
+			allow := samePriority("allow", v1alpha1.AdminNetworkPolicyRuleActionAllow)
+			deny := samePriority("deny", v1alpha1.AdminNetworkPolicyRuleActionDeny)
+
+			for _, order := range [][]*v1alpha1.AdminNetworkPolicy{{invalid, allow, deny}, {deny, allow, invalid}} {
+				policy, err := BuildV1AndV2NetPols(false, nil, order, nil)
+				Expect(policy.Ingress).To(BeEmpty())
+
+				var validationErrors ValidationErrors
+				Expect(errors.As(err, &validationErrors)).To(BeTrue())
+				Expect(validationErrors.InvalidPolicies()).To(ConsistOf([]NetPolID{
+					"[ANP] default/invalid",
+					"[ANP] default/allow",
+					"[ANP] default/deny",
+				}))
+				priorityErrors := slice.Filter(func(e *ValidationError) bool { return e.Field == "spec.priority" }, validationErrors)
+				Expect(priorityErrors).To(HaveLen(3))
+			}
+		})
+
+		It("reports unsupported actions instead of panicking", func() {
+			badANP := &v1alpha1.AdminNetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "lowercase-action"},
+				Spec: v1alpha1.AdminNetworkPolicySpec{
+					Priority: 30,
+					Subject:  v1alpha1.AdminNetworkPolicySubject{Namespaces: &metav1.LabelSelector{}},
+					Ingress: []v1alpha1.AdminNetworkPolicyIngressRule{{
+						Name:   "allow",
+						Action: "allow",
+						From:   []v1alpha1.AdminNetworkPolicyIngressPeer{{Namespaces: &metav1.LabelSelector{}}},
+					}},
+				},
+			}
+			badBANP := &v1alpha1.BaselineAdminNetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "default"},
+				Spec: v1alpha1.BaselineAdminNetworkPolicySpec{
+					Subject: v1alpha1.AdminNetworkPolicySubject{Namespaces: &metav1.LabelSelector{}},
+					Egress: []v1alpha1.BaselineAdminNetworkPolicyEgressRule{{
+						Name:   "pass",
+						Action: "Pass",
+						To:     []v1alpha1.BaselineAdminNetworkPolicyEgressPeer{{Namespaces: &metav1.LabelSelector{}}},
+					}},
+				},
+			}
+
+			policy, err := BuildV1AndV2NetPols(false, nil, []*v1alpha1.AdminNetworkPolicy{(*anps)[1], badANP}, badBANP)
+			Expect(policy.Ingress).To(HaveLen(1))
+			Expect(policy.Egress).To(BeEmpty())
+
+			var validationErrors ValidationErrors
+			Expect(errors.As(err, &validationErrors)).To(BeTrue())
+			Expect(validationErrors.InvalidPolicies()).To(Equal([]NetPolID{
+				"[ANP] default/lowercase-action",
+				"[BANP] default/default",
+			}))
+			fields := slice.Map(func(e *ValidationError) string { return e.Field }, validationErrors)
+			Expect(fields).To(Equal([]string{
+				"spec.ingress[0].action",
+				"spec.egress[0].action",
+			}))
+		})
+
+		It("agrees with the linter on which networks are valid", func() {
+			for _, cidr := range []string{"10.0.0.0/8", "2001:db8::/32", "::ffff:1.2.3.4/128", "1.2.3.4/99", "not-a-cidr"} {
+				anp := &v1alpha1.AdminNetworkPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "networks"},
+					Spec: v1alpha1.AdminNetworkPolicySpec{
+						Priority: 10,
+						Subject:  v1alpha1.AdminNetworkPolicySubject{Namespaces: &metav1.LabelSelector{}},
+						Egress: []v1alpha1.AdminNetworkPolicyEgressRule{{
+							Name:   "deny",
+							Action: v1alpha1.AdminNetworkPolicyRuleActionDeny,
+							To:     []v1alpha1.AdminNetworkPolicyEgressPeer{{Networks: []v1alpha1.CIDR{v1alpha1.CIDR(cidr)}}},
+						}},
+					},
+				}
+				_, err := BuildV1AndV2NetPols(false, nil, []*v1alpha1.AdminNetworkPolicy{anp}, nil)
+				Expect(err == nil).To(Equal(len(validation.ValidateAdminNetworkPolicy(anp)) == 0), cidr)
+			}
+		})
+	})
+
+	Describe("IPPeerMatcher with malformed traffic IPs", func() {
+		It("doesn't match", func() {
+			ip := &IPPeerMatcher{IPBlock: netpol.IPBlock_10_0_0_1_24, Port: &AllPortMatcher{}}
+			Expect(ip.Matches(nil, &TrafficPeer{IP: "not-an-ip"}, 80, "", tcp)).To(BeFalse())
+			Expect(ip.Matches(nil, &TrafficPeer{}, 80, "", tcp)).To(BeFalse())
+		})
+	})
 }
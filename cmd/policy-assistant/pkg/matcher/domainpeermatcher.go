@@ -0,0 +1,56 @@
+package matcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// DomainPeerMatcher matches traffic to hostnames.
+// It is only relevant to ANP egress domainNames peers.
+// Traffic is matched on its destination Hostname; use a DomainResolver to check that
+// the destination IP is actually backed by that Hostname.
+type DomainPeerMatcher struct {
+	DomainName string
+	Port       PortMatcher
+}
+
+func (d *DomainPeerMatcher) PrimaryKey() string {
+	return fmt.Sprintf(`{"type": "domain-name", "domainName": "%s"}`, d.DomainName)
+}
+
+func (d *DomainPeerMatcher) MarshalJSON() (b []byte, e error) {
+	return json.Marshal(map[string]interface{}{
+		"Type":       "matching hostnames by domain name",
+		"DomainName": d.DomainName,
+		"Port":       d.Port,
+	})
+}
+
+func (d *DomainPeerMatcher) Matches(_, peer *TrafficPeer, portInt int, portName string, protocol v1.Protocol) bool {
+	return peer.Hostname != "" &&
+		IsDomainNameMatch(d.DomainName, peer.Hostname) &&
+		d.Port.Matches(portInt, portName, protocol)
+}
+
+// IsDomainNameMatch implements the DomainName semantics of NPEP-133:
+//   - `kubernetes.io` matches only `kubernetes.io`
+//   - `*.kubernetes.io` matches one or more entire labels in front of `kubernetes.io`,
+//     e.g. `www.kubernetes.io` and `latest.blog.kubernetes.io`, but not `kubernetes.io`
+//
+// Comparisons are case-insensitive, and a trailing '.' (fully-qualified form) is ignored.
+func IsDomainNameMatch(domainName string, hostname string) bool {
+	domainName, hostname = normalizeHostname(domainName), normalizeHostname(hostname)
+	suffix, isWildcard := strings.CutPrefix(domainName, "*.")
+	if !isWildcard {
+		return domainName == hostname
+	}
+	prefix, found := strings.CutSuffix(hostname, "."+suffix)
+	return found && prefix != "" && !strings.HasPrefix(prefix, ".") && !strings.HasSuffix(prefix, ".")
+}
+
+func normalizeHostname(hostname string) string {
+	return strings.ToLower(strings.TrimSuffix(hostname, "."))
+}
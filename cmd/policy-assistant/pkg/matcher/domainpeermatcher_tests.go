@@ -0,0 +1,58 @@
+package matcher
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func RunDomainPeerMatcherTests() {
+	Describe("IsDomainNameMatch", func() {
+		It("exact domain names only match themselves", func() {
+			Expect(IsDomainNameMatch("kubernetes.io", "kubernetes.io")).To(BeTrue())
+			Expect(IsDomainNameMatch("blog.kubernetes.io", "blog.kubernetes.io")).To(BeTrue())
+
+			Expect(IsDomainNameMatch("kubernetes.io", "www.kubernetes.io")).To(BeFalse())
+			Expect(IsDomainNameMatch("kubernetes.io", "blog.kubernetes.io")).To(BeFalse())
+			Expect(IsDomainNameMatch("kubernetes.io", "my-kubernetes.io")).To(BeFalse())
+			Expect(IsDomainNameMatch("kubernetes.io", "wikipedia.org")).To(BeFalse())
+			Expect(IsDomainNameMatch("blog.kubernetes.io", "kubernetes.io")).To(BeFalse())
+		})
+
+		It("wildcards match one or more entire labels", func() {
+			Expect(IsDomainNameMatch("*.kubernetes.io", "www.kubernetes.io")).To(BeTrue())
+			Expect(IsDomainNameMatch("*.kubernetes.io", "blog.kubernetes.io")).To(BeTrue())
+			Expect(IsDomainNameMatch("*.kubernetes.io", "latest.blog.kubernetes.io")).To(BeTrue())
+
+			Expect(IsDomainNameMatch("*.kubernetes.io", "kubernetes.io")).To(BeFalse())
+			Expect(IsDomainNameMatch("*.kubernetes.io", "my-kubernetes.io")).To(BeFalse())
+			Expect(IsDomainNameMatch("*.kubernetes.io", "wikipedia.org")).To(BeFalse())
+		})
+
+		It("ignores case and trailing dots", func() {
+			Expect(IsDomainNameMatch("Kubernetes.IO.", "kubernetes.io")).To(BeTrue())
+			Expect(IsDomainNameMatch("*.kubernetes.io", "WWW.kubernetes.io.")).To(BeTrue())
+		})
+	})
+
+	Describe("StaticDomainResolver", func() {
+		resolver := NewStaticDomainResolver(map[string][]string{
+			"www.kubernetes.io": {"1.2.3.4", "1.2.3.5"},
+		})
+
+		It("fills in a missing destination IP", func() {
+			traffic := &Traffic{Source: &TrafficPeer{}, Destination: &TrafficPeer{Hostname: "WWW.kubernetes.io."}}
+			Expect(traffic.ResolveDestination(resolver)).To(Succeed())
+			Expect(traffic.Destination.IP).To(Equal("1.2.3.4"))
+		})
+
+		It("rejects an IP which doesn't belong to the hostname", func() {
+			traffic := &Traffic{Source: &TrafficPeer{}, Destination: &TrafficPeer{Hostname: "www.kubernetes.io", IP: "8.8.8.8"}}
+			Expect(traffic.ResolveDestination(resolver)).ToNot(Succeed())
+		})
+
+		It("rejects unknown hostnames", func() {
+			traffic := &Traffic{Source: &TrafficPeer{}, Destination: &TrafficPeer{Hostname: "wikipedia.org"}}
+			Expect(traffic.ResolveDestination(resolver)).ToNot(Succeed())
+		})
+	})
+}
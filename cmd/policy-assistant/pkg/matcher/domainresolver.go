@@ -0,0 +1,67 @@
+package matcher
+
+import (
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+)
+
+// DomainResolver resolves hostnames to IPs, so that traffic to hostnames can be simulated
+// without a DNS server.
+type DomainResolver interface {
+	Resolve(hostname string) ([]string, error)
+}
+
+// StaticDomainResolver resolves hostnames from a fixed hostname -> IPs map, similar to /etc/hosts.
+type StaticDomainResolver struct {
+	Hosts map[string][]string
+}
+
+func NewStaticDomainResolver(hosts map[string][]string) *StaticDomainResolver {
+	normalized := map[string][]string{}
+	for hostname, ips := range hosts {
+		key := normalizeHostname(hostname)
+		normalized[key] = append(normalized[key], ips...)
+	}
+	return &StaticDomainResolver{Hosts: normalized}
+}
+
+// NewStaticDomainResolverFromFile reads a yaml or json file mapping hostnames to lists of IPs, e.g.
+//
+//	www.kubernetes.io: [1.2.3.4, 1.2.3.5]
+func NewStaticDomainResolverFromFile(path string) (*StaticDomainResolver, error) {
+	hosts, err := utils.ParseYamlFromFileStrict[map[string][]string](path)
+	if err != nil {
+		return nil, err
+	}
+	return NewStaticDomainResolver(*hosts), nil
+}
+
+func (s *StaticDomainResolver) Resolve(hostname string) ([]string, error) {
+	ips, ok := s.Hosts[normalizeHostname(hostname)]
+	if !ok || len(ips) == 0 {
+		return nil, errors.Errorf("unable to resolve hostname %s", hostname)
+	}
+	return ips, nil
+}
+
+// ResolveDestination resolves the destination of traffic addressed by hostname:
+// - if the destination has no IP, it's set to the first IP of the hostname
+// - if the destination has an IP, it must be one of the IPs of the hostname
+// Traffic without a destination hostname is left alone.
+func (t *Traffic) ResolveDestination(resolver DomainResolver) error {
+	hostname := t.Destination.Hostname
+	if hostname == "" {
+		return nil
+	}
+	ips, err := resolver.Resolve(hostname)
+	if err != nil {
+		return err
+	}
+	if t.Destination.IP == "" {
+		t.Destination.IP = ips[0]
+	} else if !slices.Contains(ips, t.Destination.IP) {
+		return errors.Errorf("ip %s does not belong to hostname %s (resolved ips: %+v)", t.Destination.IP, hostname, ips)
+	}
+	return nil
+}
@@ -128,11 +128,8 @@ func (s *SliceBuilder) peerProtocolGroupTableLines(t *peerProtocolGroup) {
 			return a.priority < b.priority
 		})
 		for _, v := range anps {
-			if len(v.effects) > 1 {
-				actions = append(actions, fmt.Sprintf("   pri=%d (%s): %s (ineffective rules: %s)", v.priority, v.name, v.effects[0], strings.Join(v.effects[1:], ", ")))
-			} else {
-				actions = append(actions, fmt.Sprintf("   pri=%d (%s): %s", v.priority, v.name, v.effects[0]))
-			}
+			actions = append(actions, fmt.Sprintf("   pri=%d (%s): %s", v.priority, v.name, v.effects[0]))
+			actions = append(actions, ineffectiveRuleLines(v.effects[1:])...)
 		}
 	}
 
@@ -145,17 +142,20 @@ func (s *SliceBuilder) peerProtocolGroupTableLines(t *peerProtocolGroup) {
 	if len(banps) > 0 {
 		actions = append(actions, "BANP:")
 		for _, v := range banps {
-			if len(v.effects) > 1 {
-				actions = append(actions, fmt.Sprintf("   %s (ineffective rules: %s)", v.effects[0], strings.Join(v.effects[1:], ", ")))
-			} else {
-				actions = append(actions, fmt.Sprintf("   %s", v.effects[0]))
-			}
+			actions = append(actions, fmt.Sprintf("   %s", v.effects[0]))
+			actions = append(actions, ineffectiveRuleLines(v.effects[1:])...)
 		}
 	}
 
 	s.Append(t.subject, strings.Join(actions, "\n"), t.port)
 }
 
+// ineffectiveRuleLines lists the rules of a policy which never decide traffic to a peer, since an earlier rule
+// of the policy always does
+func ineffectiveRuleLines(effects []string) []string {
+	return slice.Map(func(e string) string { return "      ineffective: " + e }, effects)
+}
+
 func PortMatcherTableLines(pm PortMatcher, kind PolicyKind) []string {
 	switch port := pm.(type) {
 	case *AllPortMatcher:
@@ -191,11 +191,11 @@ func groupAnbAndBanp(p []PeerMatcher) []PeerMatcher {
 	for _, v := range p {
 		switch t := v.(type) {
 		case *PeerMatcherAdmin:
-			k := t.Port.GetPrimaryKey() + t.Pod.PrimaryKey() + t.Namespace.PrimaryKey()
+			k, subject, port := resolveAdminPeer(t.PeerMatcher)
 			if _, ok := groups[k]; !ok {
 				groups[k] = &peerProtocolGroup{
-					port:     strings.Join(PortMatcherTableLines(t.PodPeerMatcher.Port, t.effectFromMatch.PolicyKind), "\n"),
-					subject:  resolveSubject(t.PodPeerMatcher),
+					port:     strings.Join(PortMatcherTableLines(port, t.effectFromMatch.PolicyKind), "\n"),
+					subject:  subject,
 					policies: map[string]*anpGroup{},
 				}
 			}
@@ -208,7 +208,7 @@ func groupAnbAndBanp(p []PeerMatcher) []PeerMatcher {
 					kind:     t.effectFromMatch.PolicyKind,
 				}
 			}
-			groups[k].policies[kg].effects = append(groups[k].policies[kg].effects, string(t.effectFromMatch.Verdict))
+			groups[k].policies[kg].effects = append(groups[k].policies[kg].effects, explainAdminRule(t.effectFromMatch))
 		default:
 			result = append(result, v)
 		}
@@ -232,6 +232,30 @@ func groupAnbAndBanp(p []PeerMatcher) []PeerMatcher {
 	return result
 }
 
+// explainAdminRule describes the verdict of an ANP or BANP rule, and which rule it is, e.g. "Deny rule 'deny-to-db' (ingress[2])"
+func explainAdminRule(e Effect) string {
+	if e.RuleName == "" {
+		return fmt.Sprintf("%s (%s)", e.Verdict, e.RulePath)
+	}
+	return fmt.Sprintf("%s rule '%s' (%s)", e.Verdict, e.RuleName, e.RulePath)
+}
+
+// resolveAdminPeer returns the grouping key, table description, and port matcher of a PeerMatcher wrapped by a PeerMatcherAdmin
+func resolveAdminPeer(m PeerMatcher) (string, string, PortMatcher) {
+	switch t := m.(type) {
+	case *PodPeerMatcher:
+		return t.Port.GetPrimaryKey() + t.Pod.PrimaryKey() + t.Namespace.PrimaryKey(), resolveSubject(t), t.Port
+	case *IPPeerMatcher:
+		return t.Port.GetPrimaryKey() + t.PrimaryKey(), fmt.Sprintf("Network:\n   %s", t.IPBlock.CIDR), t.Port
+	case *DomainPeerMatcher:
+		return t.Port.GetPrimaryKey() + t.PrimaryKey(), fmt.Sprintf("Domain:\n   %s", t.DomainName), t.Port
+	case *NodePeerMatcher:
+		return t.Port.GetPrimaryKey() + t.PrimaryKey(), fmt.Sprintf("Node:\n   %s", strings.TrimSpace(kube.LabelSelectorTableLines(t.Selector))), t.Port
+	default:
+		panic(errors.Errorf("invalid admin PeerMatcher type %T", m))
+	}
+}
+
 func resolveSubject(nsPodMatcher *PodPeerMatcher) string {
 	var namespaces string
 	var pods string
@@ -240,10 +264,6 @@ func resolveSubject(nsPodMatcher *PodPeerMatcher) string {
 		namespaces = "all"
 	case *LabelSelectorNamespaceMatcher:
 		namespaces = kube.LabelSelectorTableLines(ns.Selector)
-	case *SameLabelsNamespaceMatcher:
-		namespaces = fmt.Sprintf("Same labels - %s", strings.Join(ns.labels, ", "))
-	case *NotSameLabelsNamespaceMatcher:
-		namespaces = fmt.Sprintf("Not Same labels - %s", strings.Join(ns.labels, ", "))
 	case *ExactNamespaceMatcher:
 		namespaces = ns.Namespace
 	default:
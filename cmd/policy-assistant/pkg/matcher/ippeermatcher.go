@@ -7,12 +7,13 @@ import (
 
 	"github.com/mattfenwick/collections/pkg/slice"
 	"github.com/mattfenwick/cyclonus/pkg/kube"
+	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 )
 
 // IPPeerMatcher matches traffic to CIDR blocks.
-// It is only relevant to v1 NetPols.
+// It is used for v1 NetPol IPBlocks and for ANP/BANP egress networks peers (which have no excepts).
 type IPPeerMatcher struct {
 	IPBlock *networkingv1.IPBlock
 	Port    PortMatcher
@@ -37,9 +38,11 @@ func (i *IPPeerMatcher) MarshalJSON() (b []byte, e error) {
 
 func (i *IPPeerMatcher) Matches(_, peer *TrafficPeer, portInt int, portName string, protocol v1.Protocol) bool {
 	isIpMatch, err := kube.IsIPAddressMatchForIPBlock(peer.IP, i.IPBlock)
-	// TODO propagate this error instead of panic
+	// CIDRs are validated by the builders, so this is a malformed or missing traffic IP,
+	//   which can't be in any CIDR
 	if err != nil {
-		panic(err)
+		logrus.Debugf("unable to match ip '%s' against ipblock %s: %+v", peer.IP, i.PrimaryKey(), err)
+		return false
 	}
 
 	return isIpMatch && i.Port.Matches(portInt, portName, protocol)
@@ -0,0 +1,373 @@
+package matcher
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+
+	"github.com/mattfenwick/collections/pkg/slice"
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	"github.com/olekukonko/tablewriter"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
+)
+
+type LintKind string
+
+const (
+	// LintShadowed is an ANP or BANP rule which never decides traffic, since rules of higher-priority ANPs
+	// always decide it first
+	LintShadowed LintKind = "shadowed"
+	// LintRedundant is an ANP or BANP rule which never decides traffic, since earlier rules of the same policy
+	// always decide it first
+	LintRedundant LintKind = "redundant"
+	// LintUnreachable is a BANP rule which never decides traffic, since v1 NetPols select every pod it applies to
+	LintUnreachable LintKind = "unreachable"
+	// LintPriorityCollision is ANPs sharing a priority, whose precedence is undefined
+	LintPriorityCollision LintKind = "priority-collision"
+)
+
+// LintFinding is a problem with an ANP or BANP
+type LintFinding struct {
+	Kind LintKind `json:"kind"`
+	// Policy is e.g. "[ANP] team-a"
+	Policy string `json:"policy"`
+	// Rule is e.g. "Deny rule 'deny-to-db' (ingress[2])".  It's not set for priority collisions.
+	Rule string `json:"rule,omitempty"`
+	// Causes are the rules or policies responsible for the finding
+	Causes []string `json:"causes"`
+}
+
+func (f *LintFinding) String() string {
+	str := fmt.Sprintf("%s: %s", f.Kind, f.Policy)
+	if f.Rule != "" {
+		str += " " + f.Rule
+	}
+	return fmt.Sprintf("%s, because of %s", str, strings.Join(f.Causes, "; "))
+}
+
+func (f *LintFinding) isSameRule(other *LintFinding) bool {
+	return f.Policy == other.Policy && f.Rule == other.Rule
+}
+
+type LintFindings []*LintFinding
+
+func (l LintFindings) Table() string {
+	tableString := &strings.Builder{}
+	table := tablewriter.NewWriter(tableString)
+	table.SetAutoWrapText(false)
+	table.SetRowLine(true)
+	table.SetHeader([]string{"Kind", "Policy", "Rule", "Because of"})
+	for _, f := range l {
+		table.Append([]string{string(f.Kind), f.Policy, f.Rule, strings.Join(f.Causes, "\n")})
+	}
+	table.Render()
+	return tableString.String()
+}
+
+// Lint finds ANP and BANP rules which can never decide traffic, and ANPs whose priorities collide.
+// anps are checked for priority collisions, since ANPs sharing a priority aren't built into the policy.
+// BANP rules are only found to be unreachable because of v1 NetPols if pods are given, and only if they aren't
+// already shadowed or redundant, so that each rule is reported once.
+// Coverage is only checked conservatively -- e.g. port ranges never cover other ports -- so that every
+// finding is real, though some may be missed.
+func Lint(policy *Policy, anps []*v1alpha1.AdminNetworkPolicy, pods []*InternalPeer) LintFindings {
+	findings := lintPriorities(anps)
+	ingress, egress := policy.SortedTargets()
+	for _, targets := range [][]*Target{ingress, egress} {
+		adminFindings := lintAdminRules(targets)
+		findings = append(findings, adminFindings...)
+		for _, finding := range lintUnreachableBANPRules(targets, pods) {
+			if slices.IndexFunc(adminFindings, finding.isSameRule) < 0 {
+				findings = append(findings, finding)
+			}
+		}
+	}
+	return findings
+}
+
+func lintPriorities(anps []*v1alpha1.AdminNetworkPolicy) LintFindings {
+	byPriority := slice.GroupOn(func(anp *v1alpha1.AdminNetworkPolicy) int32 { return anp.Spec.Priority }, anps)
+	var findings LintFindings
+	for _, priority := range slice.Sort(maps.Keys(byPriority)) {
+		colliding := byPriority[priority]
+		if len(colliding) < 2 {
+			continue
+		}
+		for _, anp := range colliding {
+			others := slice.Filter(func(other *v1alpha1.AdminNetworkPolicy) bool { return other != anp }, colliding)
+			findings = append(findings, &LintFinding{
+				Kind:   LintPriorityCollision,
+				Policy: fmt.Sprintf("[%s] %s", AdminNetworkPolicy, anp.Name),
+				Causes: slice.Map(func(other *v1alpha1.AdminNetworkPolicy) string {
+					return fmt.Sprintf("[%s] %s priority %d", AdminNetworkPolicy, other.Name, priority)
+				}, others),
+			})
+		}
+	}
+	return findings
+}
+
+// adminPeer is a peer of an ANP or BANP rule, with the subject of its rule
+type adminPeer struct {
+	subject *SubjectAdmin
+	matcher *PeerMatcherAdmin
+	// position orders the peers of a policy
+	position int
+}
+
+func (a *adminPeer) ruleKey() string {
+	e := a.matcher.effectFromMatch
+	return fmt.Sprintf("%s/%s/%s", e.PolicyKind, e.PolicyName, e.RulePath)
+}
+
+// precedes returns true if a decides traffic which it matches before b can
+func (a *adminPeer) precedes(b *adminPeer) bool {
+	ae, be := a.matcher.effectFromMatch, b.matcher.effectFromMatch
+	if ae.PolicyKind == be.PolicyKind && ae.PolicyName == be.PolicyName {
+		return a.position < b.position && ae.RulePath != be.RulePath
+	}
+	switch be.PolicyKind {
+	case AdminNetworkPolicy:
+		return ae.PolicyKind == AdminNetworkPolicy && ae.Priority < be.Priority
+	case BaselineAdminNetworkPolicy:
+		// a Pass goes on to v1 NetPols, and then to the BANP
+		return ae.PolicyKind == AdminNetworkPolicy && (ae.Verdict == Allow || ae.Verdict == Deny)
+	default:
+		return false
+	}
+}
+
+// lintAdminRules finds ANP and BANP rules, each of whose peers is covered by the peers of rules which take precedence
+func lintAdminRules(targets []*Target) LintFindings {
+	var peers []*adminPeer
+	for _, target := range targets {
+		subject, ok := target.SubjectMatcher.(*SubjectAdmin)
+		if !ok {
+			continue
+		}
+		for i, m := range target.Peers {
+			if matcherAdmin, ok := m.(*PeerMatcherAdmin); ok {
+				peers = append(peers, &adminPeer{subject: subject, matcher: matcherAdmin, position: i})
+			}
+		}
+	}
+
+	var findings LintFindings
+	rules := slice.GroupOn((*adminPeer).ruleKey, peers)
+	for _, key := range slice.Sort(maps.Keys(rules)) {
+		causes := map[string]*adminPeer{}
+		isCovered := true
+		for _, peer := range rules[key] {
+			peerCauses, ok := coveringPeers(peer, peers)
+			if !ok {
+				isCovered = false
+				break
+			}
+			for _, cause := range peerCauses {
+				causes[cause.ruleKey()] = cause
+			}
+		}
+		if !isCovered {
+			continue
+		}
+
+		rule := rules[key][0].matcher.effectFromMatch
+		kind := LintRedundant
+		for _, cause := range causes {
+			if cause.matcher.effectFromMatch.PolicyName != rule.PolicyName || cause.matcher.effectFromMatch.PolicyKind != rule.PolicyKind {
+				kind = LintShadowed
+			}
+		}
+		findings = append(findings, &LintFinding{
+			Kind:   kind,
+			Policy: fmt.Sprintf("[%s] %s", rule.PolicyKind, rule.PolicyName),
+			Rule:   explainAdminRule(rule),
+			Causes: slice.Sort(slice.Map(func(cause *adminPeer) string { return cause.matcher.effectFromMatch.String() }, maps.Values(causes))),
+		})
+	}
+	return findings
+}
+
+// coveringPeers returns the peers which take precedence over peer, and between them match all the traffic
+// which peer matches.  Returns false if there aren't any.
+func coveringPeers(peer *adminPeer, peers []*adminPeer) ([]*adminPeer, bool) {
+	_, _, remaining := resolveAdminPeer(peer.matcher.PeerMatcher)
+	var causes []*adminPeer
+	for _, other := range peers {
+		if !other.precedes(peer) || !isSubjectCovered(other.subject, peer.subject) || !isPeerCovered(other.matcher.PeerMatcher, peer.matcher.PeerMatcher) {
+			continue
+		}
+		_, _, otherPorts := resolveAdminPeer(other.matcher.PeerMatcher)
+		isEmpty, remainingPorts := SubtractPortMatchers(remaining, otherPorts)
+		if remainingPorts != nil && reflect.DeepEqual(remainingPorts, remaining) {
+			// other doesn't match any of the remaining ports
+			continue
+		}
+		causes = append(causes, other)
+		if isEmpty {
+			return causes, true
+		}
+		remaining = remainingPorts
+	}
+	return nil, false
+}
+
+// lintUnreachableBANPRules finds BANP rules for subjects whose pods are all selected by v1 NetPols
+func lintUnreachableBANPRules(targets []*Target, pods []*InternalPeer) LintFindings {
+	var findings LintFindings
+	for _, target := range targets {
+		if _, ok := target.SubjectMatcher.(*SubjectAdmin); !ok {
+			continue
+		}
+		banpRules := slice.Filter(func(m PeerMatcher) bool {
+			matcherAdmin, ok := m.(*PeerMatcherAdmin)
+			return ok && matcherAdmin.effectFromMatch.PolicyKind == BaselineAdminNetworkPolicy
+		}, target.Peers)
+		if len(banpRules) == 0 {
+			continue
+		}
+
+		selectedPods := slice.Filter(target.Matches, pods)
+		v1Names := map[string]bool{}
+		for _, pod := range selectedPods {
+			v1Targets := slice.Filter(func(t *Target) bool {
+				_, isV1 := t.SubjectMatcher.(*SubjectV1)
+				return isV1 && t.Matches(pod)
+			}, targets)
+			if len(v1Targets) == 0 {
+				v1Names = nil
+				break
+			}
+			for _, t := range v1Targets {
+				v1Names[t.v1PolicyNames()] = true
+			}
+		}
+		if len(selectedPods) == 0 || v1Names == nil {
+			continue
+		}
+
+		causes := slice.Sort(slice.Map(func(names string) string { return fmt.Sprintf("[%s] %s", NetworkPolicyV1, names) }, maps.Keys(v1Names)))
+		seen := map[string]bool{}
+		for _, m := range banpRules {
+			rule := m.(*PeerMatcherAdmin).effectFromMatch
+			if seen[rule.RulePath] {
+				continue
+			}
+			seen[rule.RulePath] = true
+			findings = append(findings, &LintFinding{
+				Kind:   LintUnreachable,
+				Policy: fmt.Sprintf("[%s] %s", rule.PolicyKind, rule.PolicyName),
+				Rule:   explainAdminRule(rule),
+				Causes: causes,
+			})
+		}
+	}
+	return findings
+}
+
+// isSubjectCovered returns true if every pod selected by b is also selected by a
+func isSubjectCovered(a *SubjectAdmin, b *SubjectAdmin) bool {
+	switch {
+	case a.subject.Namespaces != nil && b.subject.Namespaces != nil:
+		return isSelectorCovered(*a.subject.Namespaces, *b.subject.Namespaces)
+	case a.subject.Namespaces != nil && b.subject.Pods != nil:
+		return isSelectorCovered(*a.subject.Namespaces, b.subject.Pods.NamespaceSelector)
+	case a.subject.Pods != nil && b.subject.Namespaces != nil:
+		return isSelectorCovered(a.subject.Pods.NamespaceSelector, *b.subject.Namespaces) && kube.IsLabelSelectorEmpty(a.subject.Pods.PodSelector)
+	case a.subject.Pods != nil && b.subject.Pods != nil:
+		return isSelectorCovered(a.subject.Pods.NamespaceSelector, b.subject.Pods.NamespaceSelector) &&
+			isSelectorCovered(a.subject.Pods.PodSelector, b.subject.Pods.PodSelector)
+	default:
+		return false
+	}
+}
+
+// isSelectorCovered returns true if every set of labels matched by b is also matched by a:
+// that is, if a's requirements are a subset of b's
+func isSelectorCovered(a metav1.LabelSelector, b metav1.LabelSelector) bool {
+	for key, value := range a.MatchLabels {
+		if bValue, ok := b.MatchLabels[key]; !ok || bValue != value {
+			return false
+		}
+	}
+	for _, expression := range a.MatchExpressions {
+		if slices.IndexFunc(b.MatchExpressions, func(bExpression metav1.LabelSelectorRequirement) bool {
+			return reflect.DeepEqual(expression, bExpression)
+		}) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isPeerCovered returns true if every peer matched by b is also matched by a, ignoring ports
+func isPeerCovered(a PeerMatcher, b PeerMatcher) bool {
+	switch l := a.(type) {
+	case *PodPeerMatcher:
+		r, ok := b.(*PodPeerMatcher)
+		return ok && isNamespaceCovered(l.Namespace, r.Namespace) && isPodCovered(l.Pod, r.Pod)
+	case *IPPeerMatcher:
+		r, ok := b.(*IPPeerMatcher)
+		return ok && isCIDRCovered(l.IPBlock.CIDR, r.IPBlock.CIDR)
+	case *NodePeerMatcher:
+		r, ok := b.(*NodePeerMatcher)
+		return ok && isSelectorCovered(l.Selector, r.Selector)
+	case *DomainPeerMatcher:
+		r, ok := b.(*DomainPeerMatcher)
+		return ok && isDomainNameCovered(l.DomainName, r.DomainName)
+	default:
+		return false
+	}
+}
+
+func isNamespaceCovered(a NamespaceMatcher, b NamespaceMatcher) bool {
+	switch l := a.(type) {
+	case *AllNamespaceMatcher:
+		return true
+	case *LabelSelectorNamespaceMatcher:
+		r, ok := b.(*LabelSelectorNamespaceMatcher)
+		return ok && isSelectorCovered(l.Selector, r.Selector)
+	default:
+		return a.PrimaryKey() == b.PrimaryKey()
+	}
+}
+
+func isPodCovered(a PodMatcher, b PodMatcher) bool {
+	switch l := a.(type) {
+	case *AllPodMatcher:
+		return true
+	case *LabelSelectorPodMatcher:
+		r, ok := b.(*LabelSelectorPodMatcher)
+		return ok && isSelectorCovered(l.Selector, r.Selector)
+	default:
+		return false
+	}
+}
+
+// isCIDRCovered returns true if CIDR b is within CIDR a.  ANP and BANP networks have no excepts.
+func isCIDRCovered(a string, b string) bool {
+	_, aNet, aErr := net.ParseCIDR(a)
+	_, bNet, bErr := net.ParseCIDR(b)
+	if aErr != nil || bErr != nil {
+		return false
+	}
+	aOnes, aBits := aNet.Mask.Size()
+	bOnes, bBits := bNet.Mask.Size()
+	return aBits == bBits && aOnes <= bOnes && aNet.Contains(bNet.IP)
+}
+
+// isDomainNameCovered returns true if every hostname matched by domain name b is also matched by a
+func isDomainNameCovered(a string, b string) bool {
+	if normalizeHostname(a) == normalizeHostname(b) {
+		return true
+	}
+	// every hostname matched by `*.blog.kubernetes.io` has a label in front of `blog.kubernetes.io`
+	if suffix, isWildcard := strings.CutPrefix(normalizeHostname(b), "*."); isWildcard {
+		return strings.HasPrefix(normalizeHostname(a), "*.") && IsDomainNameMatch(a, suffix)
+	}
+	return IsDomainNameMatch(a, b)
+}
@@ -0,0 +1,285 @@
+package matcher
+
+import (
+	"github.com/mattfenwick/collections/pkg/slice"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
+)
+
+func RunLintTests() {
+	Describe("Lint", func() {
+		anpsYaml := []string{`
+apiVersion: policy.networking.k8s.io/v1alpha1
+kind: AdminNetworkPolicy
+metadata:
+  name: team-a
+spec:
+  priority: 10
+  subject:
+    namespaces: {}
+  ingress:
+  - name: deny-from-x
+    action: Deny
+    from:
+    - namespaces:
+        matchLabels:
+          ns: x
+  - name: allow-web-from-x
+    action: Allow
+    from:
+    - pods:
+        namespaceSelector:
+          matchLabels:
+            ns: x
+        podSelector:
+          matchLabels:
+            pod: a
+    ports:
+    - portNumber:
+        port: 80
+        protocol: TCP
+  - name: allow-web-from-y
+    action: Allow
+    from:
+    - namespaces:
+        matchLabels:
+          ns: y
+    ports:
+    - portNumber:
+        port: 80
+        protocol: TCP`, `
+apiVersion: policy.networking.k8s.io/v1alpha1
+kind: AdminNetworkPolicy
+metadata:
+  name: team-b
+spec:
+  priority: 20
+  subject:
+    pods:
+      namespaceSelector:
+        matchLabels:
+          ns: y
+      podSelector: {}
+  ingress:
+  - name: allow-web-from-y-prod
+    action: Allow
+    from:
+    - namespaces:
+        matchLabels:
+          ns: y
+          env: prod
+    ports:
+    - portNumber:
+        port: 80
+        protocol: TCP
+  - name: deny-https-from-y
+    action: Deny
+    from:
+    - namespaces:
+        matchLabels:
+          ns: y
+    ports:
+    - portNumber:
+        port: 443
+        protocol: TCP`, `
+apiVersion: policy.networking.k8s.io/v1alpha1
+kind: AdminNetworkPolicy
+metadata:
+  name: team-c
+spec:
+  priority: 30
+  subject:
+    namespaces: {}
+  egress:
+  - name: allow-dns
+    action: Allow
+    to:
+    - namespaces: {}
+    ports:
+    - portNumber:
+        port: 53
+        protocol: UDP`, `
+apiVersion: policy.networking.k8s.io/v1alpha1
+kind: AdminNetworkPolicy
+metadata:
+  name: team-d
+spec:
+  priority: 30
+  subject:
+    namespaces: {}
+  egress:
+  - name: deny-dns
+    action: Deny
+    to:
+    - namespaces: {}
+    ports:
+    - portNumber:
+        port: 53
+        protocol: UDP`}
+		banpYaml := `
+apiVersion: policy.networking.k8s.io/v1alpha1
+kind: BaselineAdminNetworkPolicy
+metadata:
+  name: default
+spec:
+  subject:
+    namespaces: {}
+  ingress:
+  - name: deny-from-x
+    action: Deny
+    from:
+    - namespaces:
+        matchLabels:
+          ns: x
+  egress:
+  - name: deny-all
+    action: Deny
+    to:
+    - namespaces: {}`
+		netpolYaml := `
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: deny-egress
+  namespace: x
+spec:
+  podSelector: {}
+  policyTypes:
+  - Egress`
+
+		var anps []*v1alpha1.AdminNetworkPolicy
+		for _, anpYaml := range anpsYaml {
+			anp, err := utils.ParseYaml[v1alpha1.AdminNetworkPolicy]([]byte(anpYaml))
+			utils.DoOrDie(err)
+			anps = append(anps, anp)
+		}
+		banp, err := utils.ParseYaml[v1alpha1.BaselineAdminNetworkPolicy]([]byte(banpYaml))
+		utils.DoOrDie(err)
+		netpol, err := utils.ParseYaml[networkingv1.NetworkPolicy]([]byte(netpolYaml))
+		utils.DoOrDie(err)
+		// team-c's priority collides with team-d's, so neither is valid
+		policy, _ := BuildV1AndV2NetPols(true, []*networkingv1.NetworkPolicy{netpol}, anps, banp)
+		podInX := &InternalPeer{Namespace: "x", NamespaceLabels: map[string]string{"ns": "x"}, PodLabels: map[string]string{"pod": "a"}}
+
+		It("Should find shadowed, redundant and unreachable rules, and colliding priorities", func() {
+			findings := Lint(policy, anps, []*InternalPeer{podInX})
+			Expect(findings).To(Equal(LintFindings{
+				{Kind: LintPriorityCollision, Policy: "[ANP] team-c", Causes: []string{"[ANP] team-d priority 30"}},
+				{Kind: LintPriorityCollision, Policy: "[ANP] team-d", Causes: []string{"[ANP] team-c priority 30"}},
+				{Kind: LintRedundant, Policy: "[ANP] team-a", Rule: "Allow rule 'allow-web-from-x' (ingress[1])",
+					Causes: []string{"[ANP] team-a rule 'deny-from-x' (ingress[0]) priority 10: Deny"}},
+				{Kind: LintShadowed, Policy: "[ANP] team-b", Rule: "Allow rule 'allow-web-from-y-prod' (ingress[0])",
+					Causes: []string{"[ANP] team-a rule 'allow-web-from-y' (ingress[2]) priority 10: Allow"}},
+				{Kind: LintShadowed, Policy: "[BANP] default", Rule: "Deny rule 'deny-from-x' (ingress[0])",
+					Causes: []string{"[ANP] team-a rule 'deny-from-x' (ingress[0]) priority 10: Deny"}},
+				{Kind: LintUnreachable, Policy: "[BANP] default", Rule: "Deny rule 'deny-all' (egress[0])",
+					Causes: []string{"[NPv1] x/deny-egress"}},
+			}))
+		})
+
+		It("Should only find BANP rules unreachable when every pod they apply to is selected by a NetPol", func() {
+			podInY := &InternalPeer{Namespace: "y", NamespaceLabels: map[string]string{"ns": "y"}}
+			Expect(Lint(policy, nil, []*InternalPeer{podInX, podInY})).To(HaveLen(3))
+			Expect(Lint(policy, nil, nil)).To(HaveLen(3))
+		})
+
+		It("Should report a BANP rule which is both shadowed and unreachable only once", func() {
+			denyIngress, err := utils.ParseYaml[networkingv1.NetworkPolicy]([]byte(`
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: deny-ingress
+  namespace: x
+spec:
+  podSelector: {}
+  policyTypes:
+  - Ingress`))
+			utils.DoOrDie(err)
+			withDenyIngress, _ := BuildV1AndV2NetPols(true, []*networkingv1.NetworkPolicy{netpol, denyIngress}, anps, banp)
+			findings := Lint(withDenyIngress, nil, []*InternalPeer{podInX})
+			banpIngressFindings := LintFindings(slice.Filter(func(f *LintFinding) bool {
+				return f.Policy == "[BANP] default" && f.Rule == "Deny rule 'deny-from-x' (ingress[0])"
+			}, findings))
+			Expect(banpIngressFindings).To(Equal(LintFindings{
+				{Kind: LintShadowed, Policy: "[BANP] default", Rule: "Deny rule 'deny-from-x' (ingress[0])",
+					Causes: []string{"[ANP] team-a rule 'deny-from-x' (ingress[0]) priority 10: Deny"}},
+			}))
+		})
+
+		It("Should cover the ports of a rule with those of several rules", func() {
+			anp, err := utils.ParseYaml[v1alpha1.AdminNetworkPolicy]([]byte(`
+apiVersion: policy.networking.k8s.io/v1alpha1
+kind: AdminNetworkPolicy
+metadata:
+  name: web
+spec:
+  priority: 1
+  subject:
+    namespaces: {}
+  egress:
+  - name: allow-http
+    action: Allow
+    to:
+    - networks:
+      - 10.0.0.0/8
+    ports:
+    - portNumber:
+        port: 80
+        protocol: TCP
+  - name: allow-https
+    action: Allow
+    to:
+    - networks:
+      - 0.0.0.0/0
+    ports:
+    - portNumber:
+        port: 443
+        protocol: TCP
+  - name: deny-web
+    action: Deny
+    to:
+    - networks:
+      - 10.1.0.0/16
+    ports:
+    - portNumber:
+        port: 80
+        protocol: TCP
+    - portNumber:
+        port: 443
+        protocol: TCP
+  - name: deny-ssh
+    action: Deny
+    to:
+    - networks:
+      - 10.1.0.0/16
+    ports:
+    - portNumber:
+        port: 22
+        protocol: TCP`))
+			utils.DoOrDie(err)
+			webPolicy, err := BuildV1AndV2NetPols(true, nil, []*v1alpha1.AdminNetworkPolicy{anp}, nil)
+			utils.DoOrDie(err)
+			Expect(Lint(webPolicy, nil, nil)).To(Equal(LintFindings{
+				{Kind: LintRedundant, Policy: "[ANP] web", Rule: "Deny rule 'deny-web' (egress[2])", Causes: []string{
+					"[ANP] web rule 'allow-http' (egress[0]) priority 1: Allow",
+					"[ANP] web rule 'allow-https' (egress[1]) priority 1: Allow",
+				}},
+			}))
+		})
+
+		It("Should check whether peers cover each other", func() {
+			Expect(isCIDRCovered("10.0.0.0/8", "10.1.0.0/16")).To(BeTrue())
+			Expect(isCIDRCovered("10.1.0.0/16", "10.0.0.0/8")).To(BeFalse())
+			Expect(isCIDRCovered("0.0.0.0/0", "fd00::/8")).To(BeFalse())
+
+			Expect(isDomainNameCovered("*.kubernetes.io", "www.kubernetes.io")).To(BeTrue())
+			Expect(isDomainNameCovered("*.kubernetes.io", "*.blog.kubernetes.io")).To(BeTrue())
+			Expect(isDomainNameCovered("*.kubernetes.io", "kubernetes.io")).To(BeFalse())
+			Expect(isDomainNameCovered("kubernetes.io", "*.kubernetes.io")).To(BeFalse())
+			Expect(isDomainNameCovered("Kubernetes.io.", "kubernetes.io")).To(BeTrue())
+		})
+	})
+}
@@ -0,0 +1,35 @@
+package matcher
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodePeerMatcher matches traffic to nodes and host-networked pods.
+// It is only relevant to ANP/BANP egress nodes peers.
+type NodePeerMatcher struct {
+	Selector metav1.LabelSelector
+	Port     PortMatcher
+}
+
+func (n *NodePeerMatcher) PrimaryKey() string {
+	return fmt.Sprintf(`{"type": "node-label-selector", "selector": "%s"}`, kube.SerializeLabelSelector(n.Selector))
+}
+
+func (n *NodePeerMatcher) MarshalJSON() (b []byte, e error) {
+	return json.Marshal(map[string]interface{}{
+		"Type":     "matching nodes by label",
+		"Selector": n.Selector,
+		"Port":     n.Port,
+	})
+}
+
+func (n *NodePeerMatcher) Matches(_, peer *TrafficPeer, portInt int, portName string, protocol v1.Protocol) bool {
+	return peer.IsNode() &&
+		kube.IsLabelsMatchLabelSelector(peer.Node.Labels, n.Selector) &&
+		n.Port.Matches(portInt, portName, protocol)
+}
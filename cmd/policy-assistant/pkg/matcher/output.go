@@ -0,0 +1,234 @@
+package matcher
+
+import (
+	"github.com/mattfenwick/collections/pkg/json"
+	"github.com/mattfenwick/collections/pkg/slice"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
+)
+
+// The *Output types are the stable, machine-readable forms of matchers and results,
+// used for json and yaml output.  Unlike the matchers' own MarshalJSON implementations,
+// which are only meant for debugging, fields are only ever added to these types.
+
+type PolicyOutput struct {
+	Ingress []*TargetOutput `json:"ingress"`
+	Egress  []*TargetOutput `json:"egress"`
+}
+
+type TargetOutput struct {
+	Subject     *SubjectOutput `json:"subject"`
+	SourceRules []NetPolID     `json:"sourceRules"`
+	Peers       []*PeerOutput  `json:"peers"`
+}
+
+// SubjectOutput describes the pods a Target applies to: the pods matching PodSelector,
+// either in Namespace (for v1 NetPols) or in namespaces matching NamespaceSelector (for ANPs/BANPs).
+type SubjectOutput struct {
+	Namespace         string                `json:"namespace,omitempty"`
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	PodSelector       metav1.LabelSelector  `json:"podSelector"`
+}
+
+type PeerType string
+
+const (
+	AllPeerType    PeerType = "all"
+	PodPeerType    PeerType = "pod"
+	IPPeerType     PeerType = "ip"
+	NodePeerType   PeerType = "node"
+	DomainPeerType PeerType = "domain"
+)
+
+// PeerOutput describes a peer and the ports on which it's matched.  Which of the peer fields are set depends on Type:
+// - pod: Namespace or NamespaceSelector, and PodSelector
+// - ip: IPBlock
+// - node: NodeSelector
+// - domain: DomainName
+type PeerOutput struct {
+	Type              PeerType              `json:"type"`
+	Namespace         string                `json:"namespace,omitempty"`
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	PodSelector       *metav1.LabelSelector `json:"podSelector,omitempty"`
+	IPBlock           *networkingv1.IPBlock `json:"ipBlock,omitempty"`
+	NodeSelector      *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+	DomainName        string                `json:"domainName,omitempty"`
+	AllPorts          bool                  `json:"allPorts"`
+	Ports             []*PortOutput         `json:"ports,omitempty"`
+	Effect            *EffectOutput         `json:"effect"`
+}
+
+// PortOutput is a port number or name, or a range of port numbers if EndPort is set.  If Port is nil,
+// all ports on Protocol are matched.  Protocol is empty for ANP/BANP named ports.
+type PortOutput struct {
+	Protocol v1.Protocol         `json:"protocol,omitempty"`
+	Port     *intstr.IntOrString `json:"port,omitempty"`
+	EndPort  *int                `json:"endPort,omitempty"`
+}
+
+type EffectOutput struct {
+	PolicyKind PolicyKind `json:"policyKind"`
+	PolicyName string     `json:"policyName,omitempty"`
+	RuleName   string     `json:"ruleName,omitempty"`
+	RulePath   string     `json:"rulePath,omitempty"`
+	// Priority is only set for ANPs
+	Priority *int    `json:"priority,omitempty"`
+	Verdict  Verdict `json:"verdict"`
+}
+
+func (e Effect) Output() *EffectOutput {
+	out := &EffectOutput{
+		PolicyKind: e.PolicyKind,
+		PolicyName: e.PolicyName,
+		RuleName:   e.RuleName,
+		RulePath:   e.RulePath,
+		Verdict:    e.Verdict,
+	}
+	if e.PolicyKind == AdminNetworkPolicy {
+		priority := e.Priority
+		out.Priority = &priority
+	}
+	return out
+}
+
+func (p *Policy) Output() *PolicyOutput {
+	ingresses, egresses := p.SortedTargets()
+	return &PolicyOutput{
+		Ingress: slice.Map(func(t *Target) *TargetOutput { return t.Output() }, ingresses),
+		Egress:  slice.Map(func(t *Target) *TargetOutput { return t.Output() }, egresses),
+	}
+}
+
+func (t *Target) Output() *TargetOutput {
+	// ANP/BANP rule order matters, so only v1 peers are sorted
+	peers := t.Peers
+	if _, ok := t.SubjectMatcher.(*SubjectV1); ok {
+		peers = slice.SortOn(func(p PeerMatcher) string { return json.MustMarshalToString(p) }, peers)
+	}
+	return &TargetOutput{
+		Subject:     subjectOutput(t.SubjectMatcher),
+		SourceRules: slice.Sort(t.SourceRules),
+		Peers:       slice.Map(peerOutput, peers),
+	}
+}
+
+func subjectOutput(s SubjectMatcher) *SubjectOutput {
+	switch subject := s.(type) {
+	case *SubjectV1:
+		return &SubjectOutput{Namespace: subject.namespace, PodSelector: subject.podSelector}
+	case *SubjectAdmin:
+		return adminSubjectOutput(subject.subject)
+	default:
+		panic(errors.Errorf("invalid SubjectMatcher type %T", s))
+	}
+}
+
+func adminSubjectOutput(subject *v1alpha1.AdminNetworkPolicySubject) *SubjectOutput {
+	if subject.Namespaces != nil {
+		return &SubjectOutput{NamespaceSelector: subject.Namespaces}
+	}
+	return &SubjectOutput{NamespaceSelector: &subject.Pods.NamespaceSelector, PodSelector: subject.Pods.PodSelector}
+}
+
+func peerOutput(m PeerMatcher) *PeerOutput {
+	allowedByV1 := NewV1Effect(true).Output()
+	switch t := m.(type) {
+	case *AllPeersMatcher:
+		return &PeerOutput{Type: AllPeerType, AllPorts: true, Effect: allowedByV1}
+	case *PortsForAllPeersMatcher:
+		out := &PeerOutput{Type: AllPeerType, Effect: allowedByV1}
+		setPortsOutput(out, t.Port)
+		return out
+	case *PeerMatcherAdmin:
+		out := peerOutput(t.PeerMatcher)
+		out.Effect = t.effectFromMatch.Output()
+		return out
+	case *PodPeerMatcher:
+		out := &PeerOutput{Type: PodPeerType, Effect: allowedByV1}
+		switch ns := t.Namespace.(type) {
+		case *AllNamespaceMatcher:
+			out.NamespaceSelector = &metav1.LabelSelector{}
+		case *LabelSelectorNamespaceMatcher:
+			out.NamespaceSelector = &ns.Selector
+		case *ExactNamespaceMatcher:
+			out.Namespace = ns.Namespace
+		default:
+			panic(errors.Errorf("invalid NamespaceMatcher type %T", ns))
+		}
+		switch pod := t.Pod.(type) {
+		case *AllPodMatcher:
+			out.PodSelector = &metav1.LabelSelector{}
+		case *LabelSelectorPodMatcher:
+			out.PodSelector = &pod.Selector
+		default:
+			panic(errors.Errorf("invalid PodMatcher type %T", pod))
+		}
+		setPortsOutput(out, t.Port)
+		return out
+	case *IPPeerMatcher:
+		out := &PeerOutput{Type: IPPeerType, IPBlock: t.IPBlock, Effect: allowedByV1}
+		setPortsOutput(out, t.Port)
+		return out
+	case *NodePeerMatcher:
+		out := &PeerOutput{Type: NodePeerType, NodeSelector: &t.Selector, Effect: allowedByV1}
+		setPortsOutput(out, t.Port)
+		return out
+	case *DomainPeerMatcher:
+		out := &PeerOutput{Type: DomainPeerType, DomainName: t.DomainName, Effect: allowedByV1}
+		setPortsOutput(out, t.Port)
+		return out
+	default:
+		panic(errors.Errorf("invalid PeerMatcher type %T", m))
+	}
+}
+
+func setPortsOutput(out *PeerOutput, pm PortMatcher) {
+	switch port := pm.(type) {
+	case *AllPortMatcher:
+		out.AllPorts = true
+	case *SpecificPortMatcher:
+		for _, pp := range port.Ports {
+			out.Ports = append(out.Ports, &PortOutput{Protocol: pp.Protocol, Port: pp.Port})
+		}
+		for _, pr := range port.PortRanges {
+			from, to := intstr.FromInt(pr.From), pr.To
+			out.Ports = append(out.Ports, &PortOutput{Protocol: pr.Protocol, Port: &from, EndPort: &to})
+		}
+	default:
+		panic(errors.Errorf("invalid PortMatcher type %T", port))
+	}
+}
+
+type DirectionResultOutput struct {
+	// Effects are the effects of all the rules which applied to the traffic, including those which didn't match it
+	Effects []*EffectOutput `json:"effects"`
+	// Flow is the path of the traffic through ANPs, v1 NetPols and BANPs, e.g. "[ANP] Pass -> [NPv1] Allow"
+	Flow    string `json:"flow"`
+	Allowed bool   `json:"allowed"`
+}
+
+type AllowedResultOutput struct {
+	Ingress *DirectionResultOutput `json:"ingress"`
+	Egress  *DirectionResultOutput `json:"egress"`
+	Allowed bool                   `json:"allowed"`
+}
+
+func (d DirectionResult) Output() *DirectionResultOutput {
+	return &DirectionResultOutput{
+		Effects: slice.Map(func(e Effect) *EffectOutput { return e.Output() }, d),
+		Flow:    d.Flow(),
+		Allowed: d.IsAllowed(),
+	}
+}
+
+func (ar *AllowedResult) Output() *AllowedResultOutput {
+	return &AllowedResultOutput{
+		Ingress: ar.Ingress.Output(),
+		Egress:  ar.Egress.Output(),
+		Allowed: ar.IsAllowed(),
+	}
+}
@@ -0,0 +1,87 @@
+package matcher
+
+import (
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
+)
+
+func RunOutputTests() {
+	Describe("Output", func() {
+		anpYaml := `
+apiVersion: policy.networking.k8s.io/v1alpha1
+kind: AdminNetworkPolicy
+metadata:
+  name: team-a
+spec:
+  priority: 10
+  subject:
+    namespaces:
+      matchLabels:
+        team: a
+  egress:
+  - name: deny-to-db
+    action: Deny
+    to:
+    - networks:
+      - 10.0.0.0/8
+    ports:
+    - portNumber:
+        port: 5432
+        protocol: TCP`
+		anp, err := utils.ParseYaml[v1alpha1.AdminNetworkPolicy]([]byte(anpYaml))
+		utils.DoOrDie(err)
+		policy, err := BuildV1AndV2NetPols(true, nil, []*v1alpha1.AdminNetworkPolicy{anp}, nil)
+		utils.DoOrDie(err)
+
+		priority := 10
+		denyToDB := &EffectOutput{PolicyKind: AdminNetworkPolicy, PolicyName: "team-a", RuleName: "deny-to-db", RulePath: "egress[0]", Priority: &priority, Verdict: Deny}
+
+		It("describes targets, peers and effects", func() {
+			port := intstr.FromInt(5432)
+			Expect(policy.Output()).To(Equal(&PolicyOutput{
+				Ingress: []*TargetOutput{},
+				Egress: []*TargetOutput{
+					{
+						Subject:     &SubjectOutput{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}},
+						SourceRules: []NetPolID{netPolID(anp)},
+						Peers: []*PeerOutput{
+							{
+								Type:    IPPeerType,
+								IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"},
+								Ports:   []*PortOutput{{Protocol: v1.ProtocolTCP, Port: &port}},
+								Effect:  denyToDB,
+							},
+						},
+					},
+				},
+			}))
+		})
+
+		It("describes the effects and final verdict of traffic", func() {
+			result := policy.IsTrafficAllowed(&Traffic{
+				Source: &TrafficPeer{
+					Internal: &InternalPeer{
+						PodLabels:       map[string]string{"pod": "a"},
+						NamespaceLabels: map[string]string{"team": "a"},
+						Namespace:       "x",
+					},
+					IP: "10.1.2.3",
+				},
+				Destination:  &TrafficPeer{IP: "10.2.3.4"},
+				ResolvedPort: 5432,
+				Protocol:     v1.ProtocolTCP,
+			})
+			Expect(result.Output()).To(Equal(&AllowedResultOutput{
+				Ingress: &DirectionResultOutput{Effects: []*EffectOutput{}, Flow: "", Allowed: true},
+				Egress:  &DirectionResultOutput{Effects: []*EffectOutput{denyToDB}, Flow: "[ANP] Deny", Allowed: false},
+				Allowed: false,
+			}))
+		})
+	})
+}
@@ -22,8 +22,11 @@ These are the original PeerMatcher implementations made for v1 NetPol:
 All PeerMatcher implementations (except AllPeersMatcher) use a PortMatcher.
 If the traffic doesn't match the port matcher, then Matches() will be false.
 
-Now we also have PeerMatcherAdmin, a wrapper for PodPeerMatcher to model ANP and BANP.
-We also made NamespaceMatcher objects for SameLabels and NotSameLabels.
+Now we also have PeerMatcherAdmin, a wrapper to model ANP and BANP around one of:
+- PodPeerMatcher
+- IPPeerMatcher
+- NodePeerMatcher
+- DomainPeerMatcher
 */
 type PeerMatcher interface {
 	Matches(subject, peer *TrafficPeer, portInt int, portName string, protocol v1.Protocol) bool
@@ -1,50 +1,92 @@
 package matcher
 
 import (
-	"github.com/pkg/errors"
+	"fmt"
+
 	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
 )
 
 // PeerMatcherAdmin models an ANP or BANP rule, incorporating an ANP/BANP action and an ANP priority.
-// NOTE: we only use the PodPeerMatcher out of all the PeerMatcher imlementations.
-// This is because ANP and BANP only deal with Pod to Pod traffic, and do not deal with external IPs.
+// NOTE: the wrapped PeerMatcher is either:
+// - a PodPeerMatcher, for namespaces and pods peers
+// - an IPPeerMatcher, for egress networks peers
+// - a NodePeerMatcher, for egress nodes peers
+// - a DomainPeerMatcher, for ANP egress domainNames peers
 type PeerMatcherAdmin struct {
-	*PodPeerMatcher
+	PeerMatcher
 	Name            string
 	effectFromMatch Effect
 }
 
-// NewPeerMatcherANP creates a PeerMatcherAdmin for an ANP rule
-func NewPeerMatcherANP(peer *PodPeerMatcher, v Verdict, priority int, source string) *PeerMatcherAdmin {
+// NewPeerMatcherANP creates a PeerMatcherAdmin for an ANP rule.  rulePath locates the rule in the ANP, e.g. "ingress[2]".
+func NewPeerMatcherANP(peer PeerMatcher, v Verdict, priority int, source string, ruleName string, rulePath string) *PeerMatcherAdmin {
 	return &PeerMatcherAdmin{
-		PodPeerMatcher: peer,
-		Name:           source,
+		PeerMatcher: peer,
+		Name:        source,
 		effectFromMatch: Effect{
 			PolicyKind: AdminNetworkPolicy,
 			Priority:   priority,
 			Verdict:    v,
+			PolicyName: source,
+			RuleName:   ruleName,
+			RulePath:   rulePath,
 		},
 	}
 }
 
-// NewPeerMatcherBANP creates a new PeerMatcherAdmin for a BANP rule
-func NewPeerMatcherBANP(peer *PodPeerMatcher, v Verdict, source string) *PeerMatcherAdmin {
+// NewPeerMatcherBANP creates a new PeerMatcherAdmin for a BANP rule.  rulePath locates the rule in the BANP, e.g. "egress[0]".
+func NewPeerMatcherBANP(peer PeerMatcher, v Verdict, source string, ruleName string, rulePath string) *PeerMatcherAdmin {
 	return &PeerMatcherAdmin{
-		PodPeerMatcher: peer,
-		Name:           source,
+		PeerMatcher: peer,
+		Name:        source,
 		effectFromMatch: Effect{
 			PolicyKind: BaselineAdminNetworkPolicy,
 			Verdict:    v,
+			PolicyName: source,
+			RuleName:   ruleName,
+			RulePath:   rulePath,
 		},
 	}
 }
 
+// Effect returns the Effect of the rule on traffic matching the wrapped PeerMatcher
+func (p *PeerMatcherAdmin) Effect() Effect {
+	return p.effectFromMatch
+}
+
 // Effect models the effect of one or more v1/v2 NetPol rules on a peer
 type Effect struct {
 	PolicyKind
 	// Priority is only used for ANP (there can only be one BANP)
 	Priority int
 	Verdict
+	// PolicyName is the ANP or BANP, or the namespace/name of the v1 NetPol.  When the v1 NetPols of a Target
+	// drop traffic, it's every NetPol of the Target.
+	PolicyName string
+	// RuleName is only set for ANP and BANP
+	RuleName string
+	// RulePath locates the rule in its policy, e.g. "ingress[2]".  It's not set when v1 NetPols drop traffic,
+	// since no rule matched.
+	RulePath string
+}
+
+// String describes an Effect, e.g. "[ANP] team-a rule 'deny-to-db' (ingress[2]) priority 3: Deny"
+func (e Effect) String() string {
+	str := fmt.Sprintf("[%s]", e.PolicyKind)
+	if e.PolicyName != "" {
+		str += " " + e.PolicyName
+	}
+	if e.RuleName != "" {
+		str += fmt.Sprintf(" rule '%s'", e.RuleName)
+	}
+	if e.RulePath != "" {
+		str += fmt.Sprintf(" (%s)", e.RulePath)
+	}
+	// ANPs which didn't match the traffic have no priority
+	if e.PolicyKind == AdminNetworkPolicy && e.PolicyName != "" {
+		str += fmt.Sprintf(" priority %d", e.Priority)
+	}
+	return fmt.Sprintf("%s: %s", str, e.Verdict)
 }
 
 type PolicyKind string
@@ -57,9 +99,9 @@ const (
 
 func NewV1Effect(allow bool) Effect {
 	if allow {
-		return Effect{NetworkPolicyV1, 0, Allow}
+		return Effect{PolicyKind: NetworkPolicyV1, Verdict: Allow}
 	}
-	return Effect{NetworkPolicyV1, 0, None}
+	return Effect{PolicyKind: NetworkPolicyV1, Verdict: None}
 }
 
 type Verdict string
@@ -78,6 +120,8 @@ const (
 	Pass Verdict = "Pass"
 )
 
+// AdminActionToVerdict returns None for an action which isn't an ANP action; builders report those
+// as validation errors, so such a verdict never reaches a Target.
 func AdminActionToVerdict(action v1alpha1.AdminNetworkPolicyRuleAction) Verdict {
 	switch action {
 	case v1alpha1.AdminNetworkPolicyRuleActionAllow:
@@ -87,10 +131,11 @@ func AdminActionToVerdict(action v1alpha1.AdminNetworkPolicyRuleAction) Verdict
 	case v1alpha1.AdminNetworkPolicyRuleActionPass:
 		return Pass
 	default:
-		panic(errors.Errorf("unsupported ANP action %s", action))
+		return None
 	}
 }
 
+// BaselineAdminActionToVerdict returns None for an action which isn't a BANP action.
 func BaselineAdminActionToVerdict(action v1alpha1.BaselineAdminNetworkPolicyRuleAction) Verdict {
 	switch action {
 	case v1alpha1.BaselineAdminNetworkPolicyRuleActionAllow:
@@ -98,6 +143,6 @@ func BaselineAdminActionToVerdict(action v1alpha1.BaselineAdminNetworkPolicyRule
 	case v1alpha1.BaselineAdminNetworkPolicyRuleActionDeny:
 		return Deny
 	default:
-		panic(errors.Errorf("unsupported BANP action %s", action))
+		return None
 	}
 }
@@ -3,7 +3,6 @@ package matcher
 import (
 	"encoding/json"
 	"fmt"
-	"strings"
 
 	"github.com/mattfenwick/cyclonus/pkg/kube"
 	v1 "k8s.io/api/core/v1"
@@ -12,6 +11,7 @@ import (
 
 // PodPeerMatcher matches a Peer in Pod to Pod traffic against an ANP, BANP, or v1 NetPol rule.
 // It accounts for Namespace, Pod, and Port/Protocol.
+// Nodes and host-networked pods are never matched.
 type PodPeerMatcher struct {
 	Namespace NamespaceMatcher
 	Pod       PodMatcher
@@ -23,7 +23,7 @@ func (ppm *PodPeerMatcher) PrimaryKey() string {
 }
 
 func (ppm *PodPeerMatcher) Matches(subject, peer *TrafficPeer, portInt int, portName string, protocol v1.Protocol) bool {
-	return !peer.IsExternal() &&
+	return peer.Internal != nil && !peer.IsNode() &&
 		ppm.Namespace.Matches(peer.Internal.Namespace, peer.Internal.NamespaceLabels, subject.Internal.NamespaceLabels) &&
 		ppm.Pod.Matches(peer.Internal.PodLabels) &&
 		ppm.Port.Matches(portInt, portName, protocol)
@@ -168,79 +168,3 @@ func (a *AllNamespaceMatcher) MarshalJSON() (b []byte, e error) {
 func (a *AllNamespaceMatcher) PrimaryKey() string {
 	return `{"type": "all-namespaces"}`
 }
-
-type SameLabelsNamespaceMatcher struct {
-	labels []string
-}
-
-func (s *SameLabelsNamespaceMatcher) Matches(_ string, namespaceLabels, subjectNamespaceLabels map[string]string) bool {
-	if len(s.labels) == 0 {
-		return false
-	}
-
-	for _, k := range s.labels {
-		v, ok := namespaceLabels[k]
-		if !ok {
-			return false
-		}
-
-		v2, ok := subjectNamespaceLabels[k]
-		if !ok || v != v2 {
-			return false
-		}
-	}
-
-	return true
-}
-
-func (s *SameLabelsNamespaceMatcher) MarshalJSON() (b []byte, e error) {
-	return json.Marshal(map[string]interface{}{
-		"Type":   "same labels",
-		"Labels": s.labels,
-	})
-}
-
-func (s *SameLabelsNamespaceMatcher) PrimaryKey() string {
-	return fmt.Sprintf(`{"type": "same-labels", "labels": "%s"}`, strings.Join(s.labels, ","))
-}
-
-type NotSameLabelsNamespaceMatcher struct {
-	labels []string
-}
-
-func (s *NotSameLabelsNamespaceMatcher) Matches(_ string, namespaceLabels, subjectNamespaceLabels map[string]string) bool {
-	if len(s.labels) == 0 {
-		return false
-	}
-
-	different := false
-
-	for _, k := range s.labels {
-		v, ok := namespaceLabels[k]
-		if !ok {
-			return false
-		}
-
-		v2, ok := subjectNamespaceLabels[k]
-		if !ok {
-			return false
-		}
-
-		if v != v2 {
-			different = true
-		}
-	}
-
-	return different
-}
-
-func (s *NotSameLabelsNamespaceMatcher) MarshalJSON() (b []byte, e error) {
-	return json.Marshal(map[string]interface{}{
-		"Type":   "not same labels",
-		"Labels": s.labels,
-	})
-}
-
-func (s *NotSameLabelsNamespaceMatcher) PrimaryKey() string {
-	return fmt.Sprintf(`{"type": "not-same-labels", "labels": "%s"}`, strings.Join(s.labels, ","))
-}
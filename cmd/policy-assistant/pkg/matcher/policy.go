@@ -7,6 +7,7 @@ import (
 	"github.com/mattfenwick/collections/pkg/slice"
 	"github.com/olekukonko/tablewriter"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -191,21 +192,24 @@ func (d DirectionResult) Resolve() (*Effect, *Effect, *Effect) {
 	}
 
 	// 2. v1 NetPol rules
-	haveV1NetPols := false
+	var v1PolicyNames []string
 	for _, e := range d {
 		if e.PolicyKind != NetworkPolicyV1 {
 			continue
 		}
 
-		haveV1NetPols = true
+		if !slices.Contains(v1PolicyNames, e.PolicyName) {
+			v1PolicyNames = append(v1PolicyNames, e.PolicyName)
+		}
 		if e.Verdict == Allow {
 			eCopy := e
 			return anpEffect, &eCopy, nil
 		}
 	}
 
-	if haveV1NetPols {
+	if len(v1PolicyNames) > 0 {
 		v1NoMatch := NewV1Effect(false)
+		v1NoMatch.PolicyName = strings.Join(v1PolicyNames, ", ")
 		return anpEffect, &v1NoMatch, nil
 	}
 
@@ -232,6 +236,20 @@ func (d DirectionResult) Resolve() (*Effect, *Effect, *Effect) {
 	return anpEffect, nil, banpEffect
 }
 
+// Winner returns the Effect which decided the traffic: an ANP's Allow or Deny, else the v1 NetPols' verdict,
+// else the BANP's verdict (which may be a No-Op), else an ANP's Pass or No-Op.
+// Returns nil if no policies apply to the traffic.
+func (d DirectionResult) Winner() *Effect {
+	anp, npv1, banp := d.Resolve()
+	if npv1 != nil {
+		return npv1
+	}
+	if banp != nil {
+		return banp
+	}
+	return anp
+}
+
 // AllowedResult contains information to calculate the final result taken on traffic in a cluster
 // taking into account all ANPs/BANPs and v1 NetPols.
 type AllowedResult struct {
@@ -239,25 +257,44 @@ type AllowedResult struct {
 	Egress  DirectionResult
 }
 
+// Table shows, for ingress and egress, the flow through ANP, v1 NetPol, and BANP, the Effect which decided
+// the traffic, and every Effect of the policies applying to the traffic
 func (ar *AllowedResult) Table() string {
 	tableString := &strings.Builder{}
 	table := tablewriter.NewWriter(tableString)
+	table.SetAutoWrapText(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
 	table.SetRowLine(true)
-	table.SetAutoMergeCells(true)
-	table.SetHeader([]string{"Type", "Action", "Target"})
-	// NOTE: broken. This function only used by 1) "cyclonus analyze explain --mode=query-target" and 2) a trace-level log in "cyclonus generate"
-	// Broken since we're changing the DirectionResult struct to use Effects instead of Allowing/DenyingTargets
-	// addTargetsToTable(table, "Ingress", "Allow", ar.Ingress.AllowingTargets)
-	// addTargetsToTable(table, "Ingress", "Deny", ar.Ingress.DenyingTargets)
-	table.Append([]string{"", "", ""})
-	// addTargetsToTable(table, "Egress", "Allow", ar.Egress.AllowingTargets)
-	// addTargetsToTable(table, "Egress", "Deny", ar.Egress.DenyingTargets)
-	table.SetFooter([]string{"Is allowed?", fmt.Sprintf("%t", ar.IsAllowed()), ""})
+	table.SetAutoMergeCellsByColumnIndex([]int{0, 1, 2})
+	table.SetHeader([]string{"Direction", "Flow", "Decided by", "Kind", "Priority", "Verdict", "Policy", "Rule", "Rule path"})
+	addDirectionResultToTable(table, "Ingress", ar.Ingress)
+	addDirectionResultToTable(table, "Egress", ar.Egress)
+	table.SetFooter([]string{"Is allowed?", fmt.Sprintf("%t", ar.IsAllowed()), "", "", "", "", "", "", ""})
 
 	table.Render()
 	return tableString.String()
 }
 
+func addDirectionResultToTable(table *tablewriter.Table, direction string, d DirectionResult) {
+	if len(d) == 0 {
+		table.Append([]string{direction, "no policies", "", "", "", "", "", "", ""})
+		return
+	}
+	flow := d.Flow()
+	winner := ""
+	if w := d.Winner(); w != nil {
+		winner = w.String()
+	}
+	for _, e := range d {
+		priority := ""
+		// ANPs which didn't match the traffic have no priority
+		if e.PolicyKind == AdminNetworkPolicy && e.PolicyName != "" {
+			priority = fmt.Sprintf("%d", e.Priority)
+		}
+		table.Append([]string{direction, flow, winner, string(e.PolicyKind), priority, string(e.Verdict), e.PolicyName, e.RuleName, e.RulePath})
+	}
+}
+
 func (ar *AllowedResult) IsAllowed() bool {
 	return ar.Ingress.IsAllowed() && ar.Egress.IsAllowed()
 }
@@ -283,10 +320,13 @@ func (p *Policy) IsIngressOrEgressAllowed(traffic *Traffic, isIngress bool) Dire
 		subject = traffic.Source
 		peer = traffic.Destination
 	}
+	subject = subject.ResolveHostNetworking()
+	peer = peer.ResolveHostNetworking()
 
 	// 1. if target is external to cluster -> allow
 	//   this is because we can't stop external hosts from sending or receiving traffic
-	if subject.Internal == nil {
+	//   likewise, nodes and host-networked pods are never selected as policy subjects
+	if subject.Internal == nil || subject.IsNode() {
 		return nil
 	}
 
@@ -300,12 +340,21 @@ func (p *Policy) IsIngressOrEgressAllowed(traffic *Traffic, isIngress bool) Dire
 	// 3. Check if any matching targets allow this traffic
 	effects := make([]Effect, 0)
 	for _, target := range matchingTargets {
+		// v1 NetPols' rules are kept apart from their combined peers, so that each effect points to its rule
+		if len(target.V1Rules) > 0 {
+			for _, rule := range target.V1Rules {
+				effects = append(effects, rule.Effect(subject, peer, traffic.ResolvedPort, traffic.ResolvedPortName, traffic.Protocol))
+			}
+			continue
+		}
 		for _, m := range target.Peers {
 			// check if m is a PeerMatcherAdmin
 			e := NewV1Effect(true)
 			matcherAdmin, ok := m.(*PeerMatcherAdmin)
 			if ok {
 				e = matcherAdmin.effectFromMatch
+			} else {
+				e.PolicyName = target.v1PolicyNames()
 			}
 
 			if !m.Matches(subject, peer, traffic.ResolvedPort, traffic.ResolvedPortName, traffic.Protocol) {
@@ -1,11 +1,14 @@
 package matcher
 
 import (
+	"strings"
+
 	"github.com/mattfenwick/cyclonus/pkg/utils"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
 )
 
 func RunPolicyTests() {
@@ -24,7 +27,8 @@ spec:
   - Ingress`
 	allowAllOnSCTPSerializedPolicy, err := utils.ParseYaml[networkingv1.NetworkPolicy]([]byte(allowAllOnSCTPSerializedYaml))
 	utils.DoOrDie(err)
-	allowAllOnSCTP := BuildNetworkPolicies(true, []*networkingv1.NetworkPolicy{allowAllOnSCTPSerializedPolicy})
+	allowAllOnSCTP, err := BuildNetworkPolicies(true, []*networkingv1.NetworkPolicy{allowAllOnSCTPSerializedPolicy})
+	utils.DoOrDie(err)
 
 	Describe("Allowing a protocol should implicitly deny other protocols from pods", func() {
 		It("should not allow TCP", func() {
@@ -50,6 +54,8 @@ spec:
 				Protocol: v1.ProtocolTCP,
 			})
 			Expect(tcpAllowed.IsAllowed()).To(BeFalse())
+			Expect(tcpAllowed.Ingress.Winner()).To(Equal(&Effect{PolicyKind: NetworkPolicyV1, Verdict: None, PolicyName: "x/policy-207"}))
+			Expect(tcpAllowed.Egress.Winner()).To(BeNil())
 		})
 
 		It("should allow SCTP", func() {
@@ -75,6 +81,71 @@ spec:
 				Protocol: v1.ProtocolSCTP,
 			})
 			Expect(sctpAllowed.IsAllowed()).To(BeTrue())
+			Expect(sctpAllowed.Ingress.Winner().String()).To(Equal("[NPv1] x/policy-207 (ingress[0]): Allow"))
+		})
+	})
+
+	Describe("Tracing v1 effects to their rules", func() {
+		netpolYaml := `
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: two-rules
+  namespace: x
+spec:
+  podSelector: {}
+  policyTypes:
+  - Ingress
+  ingress:
+  - ports:
+    - protocol: SCTP
+  - ports:
+    - protocol: TCP
+      port: 80`
+		otherYaml := `
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: udp
+  namespace: x
+spec:
+  podSelector: {}
+  policyTypes:
+  - Ingress
+  ingress:
+  - ports:
+    - protocol: UDP`
+		twoRules, err := utils.ParseYaml[networkingv1.NetworkPolicy]([]byte(netpolYaml))
+		utils.DoOrDie(err)
+		other, err := utils.ParseYaml[networkingv1.NetworkPolicy]([]byte(otherYaml))
+		utils.DoOrDie(err)
+		policy, err := BuildNetworkPolicies(true, []*networkingv1.NetworkPolicy{twoRules, other})
+		utils.DoOrDie(err)
+
+		traffic := func(port int) *Traffic {
+			return &Traffic{
+				Source:       &TrafficPeer{Internal: &InternalPeer{Namespace: "y"}, IP: "1.2.3.4"},
+				Destination:  &TrafficPeer{Internal: &InternalPeer{Namespace: "x"}, IP: "1.2.3.5"},
+				ResolvedPort: port,
+				Protocol:     v1.ProtocolTCP,
+			}
+		}
+
+		It("should have an effect for each rule of each NetPol, even when combined and simplified", func() {
+			ingress := policy.IsTrafficAllowed(traffic(80)).Ingress
+			Expect(ingress).To(ConsistOf(
+				Effect{PolicyKind: NetworkPolicyV1, Verdict: None, PolicyName: "x/two-rules", RulePath: "ingress[0]"},
+				Effect{PolicyKind: NetworkPolicyV1, Verdict: Allow, PolicyName: "x/two-rules", RulePath: "ingress[1]"},
+				Effect{PolicyKind: NetworkPolicyV1, Verdict: None, PolicyName: "x/udp", RulePath: "ingress[0]"},
+			))
+			Expect(ingress.Winner().String()).To(Equal("[NPv1] x/two-rules (ingress[1]): Allow"))
+		})
+
+		It("should name every NetPol when they drop traffic", func() {
+			winner := policy.IsTrafficAllowed(traffic(81)).Ingress.Winner()
+			Expect(winner.Verdict).To(Equal(None))
+			Expect(winner.RulePath).To(BeEmpty())
+			Expect(strings.Split(winner.PolicyName, ", ")).To(ConsistOf("x/two-rules", "x/udp"))
 		})
 	})
 
@@ -149,7 +220,8 @@ spec:
   - Egress`
 		kubePolicy, err := utils.ParseYaml[networkingv1.NetworkPolicy]([]byte(policyYaml))
 		utils.DoOrDie(err)
-		policy := BuildNetworkPolicies(true, []*networkingv1.NetworkPolicy{kubePolicy})
+		policy, err := BuildNetworkPolicies(true, []*networkingv1.NetworkPolicy{kubePolicy})
+		utils.DoOrDie(err)
 
 		It("Should allow ips in cidr", func() {
 			Expect(policy.IsTrafficAllowed(&Traffic{
@@ -195,7 +267,8 @@ spec:
   - Ingress`
 		kubePolicy, err := utils.ParseYaml[networkingv1.NetworkPolicy]([]byte(policyYaml))
 		utils.DoOrDie(err)
-		policy := BuildNetworkPolicies(true, []*networkingv1.NetworkPolicy{kubePolicy})
+		policy, err := BuildNetworkPolicies(true, []*networkingv1.NetworkPolicy{kubePolicy})
+		utils.DoOrDie(err)
 
 		It("Should allow access to named port", func() {
 			Expect(policy.IsTrafficAllowed(&Traffic{
@@ -216,4 +289,230 @@ spec:
 			}).IsAllowed()).To(BeTrue())
 		})
 	})
+	Describe("ANP and BANP egress to networks", func() {
+		anpYaml := `
+apiVersion: policy.networking.k8s.io/v1alpha1
+kind: AdminNetworkPolicy
+metadata:
+  name: pass-internal-deny-external
+spec:
+  priority: 10
+  subject:
+    namespaces: {}
+  egress:
+  - name: pass-to-cluster-cidr
+    action: Pass
+    to:
+    - networks:
+      - 10.0.0.0/8
+  - name: deny-to-everything-else
+    action: Deny
+    to:
+    - networks:
+      - 0.0.0.0/0
+    ports:
+    - portNumber:
+        port: 80
+        protocol: TCP`
+		banpYaml := `
+apiVersion: policy.networking.k8s.io/v1alpha1
+kind: BaselineAdminNetworkPolicy
+metadata:
+  name: default
+spec:
+  subject:
+    namespaces: {}
+  egress:
+  - name: deny-to-cluster-cidr
+    action: Deny
+    to:
+    - networks:
+      - 10.0.0.0/8`
+		anp, err := utils.ParseYaml[v1alpha1.AdminNetworkPolicy]([]byte(anpYaml))
+		utils.DoOrDie(err)
+		banp, err := utils.ParseYaml[v1alpha1.BaselineAdminNetworkPolicy]([]byte(banpYaml))
+		utils.DoOrDie(err)
+		policy, err := BuildV1AndV2NetPols(true, nil, []*v1alpha1.AdminNetworkPolicy{anp}, banp)
+		utils.DoOrDie(err)
+
+		trafficTo := func(ip string, port int) *Traffic {
+			return &Traffic{
+				Source: &TrafficPeer{
+					Internal: &InternalPeer{
+						PodLabels:       map[string]string{"pod": "a"},
+						NamespaceLabels: map[string]string{"ns": "x"},
+						Namespace:       "x",
+					},
+					IP: "10.1.2.3",
+				},
+				Destination:  &TrafficPeer{IP: ip},
+				ResolvedPort: port,
+				Protocol:     v1.ProtocolTCP,
+			}
+		}
+
+		It("Should pass traffic to the cluster cidr to the BANP", func() {
+			result := policy.IsTrafficAllowed(trafficTo("10.2.3.4", 80))
+			Expect(result.Egress.Flow()).To(Equal("[ANP] Pass -> [BANP] Deny"))
+			Expect(result.IsAllowed()).To(BeFalse())
+			Expect(result.Egress.Winner().String()).To(Equal("[BANP] default rule 'deny-to-cluster-cidr' (egress[0]): Deny"))
+		})
+
+		It("Should deny traffic to external ips on the denied port", func() {
+			result := policy.IsTrafficAllowed(trafficTo("8.8.8.8", 80))
+			Expect(result.Egress.Flow()).To(Equal("[ANP] Deny"))
+			Expect(result.IsAllowed()).To(BeFalse())
+			Expect(result.Egress.Winner().String()).To(Equal("[ANP] pass-internal-deny-external rule 'deny-to-everything-else' (egress[1]) priority 10: Deny"))
+		})
+
+		It("Should allow traffic to external ips on other ports", func() {
+			result := policy.IsTrafficAllowed(trafficTo("8.8.8.8", 443))
+			Expect(result.IsAllowed()).To(BeTrue())
+			// neither policy has a matching rule
+			Expect(result.Egress.Winner()).To(Equal(&Effect{PolicyKind: BaselineAdminNetworkPolicy, Verdict: None}))
+		})
+
+		It("Should show the flow, the deciding effect and every effect in its table", func() {
+			table := policy.IsTrafficAllowed(trafficTo("10.2.3.4", 80)).Table()
+			Expect(table).To(MatchRegexp(`\| Ingress\s+\| no policies\s+\|`))
+			for _, row := range []string{
+				"| Egress      | [ANP] Pass -> [BANP] Deny | [BANP] default rule 'deny-to-cluster-cidr' (egress[0]): Deny | ANP  | 10       | Pass    | pass-internal-deny-external | pass-to-cluster-cidr    | egress[0] |",
+				"|             |                           |                                                              | ANP  | 10       | Deny    | pass-internal-deny-external | deny-to-everything-else | egress[1] |",
+				"|             |                           |                                                              | BANP |          | Deny    | default                     | deny-to-cluster-cidr    | egress[0] |",
+			} {
+				Expect(table).To(ContainSubstring(row))
+			}
+			Expect(table).To(MatchRegexp(`IS ALLOWED\?\s+\|\s+FALSE\s+\|`))
+		})
+	})
+	Describe("ANP egress to nodes", func() {
+		anpYaml := `
+apiVersion: policy.networking.k8s.io/v1alpha1
+kind: AdminNetworkPolicy
+metadata:
+  name: deny-to-control-plane
+spec:
+  priority: 10
+  subject:
+    namespaces: {}
+  egress:
+  - name: deny-to-control-plane
+    action: Deny
+    to:
+    - nodes:
+        matchExpressions:
+        - key: node-role.kubernetes.io/control-plane
+          operator: Exists`
+		anp, err := utils.ParseYaml[v1alpha1.AdminNetworkPolicy]([]byte(anpYaml))
+		utils.DoOrDie(err)
+		policy, err := BuildV1AndV2NetPols(true, nil, []*v1alpha1.AdminNetworkPolicy{anp}, nil)
+		utils.DoOrDie(err)
+
+		controlPlaneLabels := map[string]string{"node-role.kubernetes.io/control-plane": ""}
+		trafficTo := func(pod *PodNetworking) *Traffic {
+			return &Traffic{
+				Source: &TrafficPeer{
+					Internal: &InternalPeer{
+						PodLabels:       map[string]string{"pod": "a"},
+						NamespaceLabels: map[string]string{"ns": "x"},
+						Namespace:       "x",
+					},
+					IP: "10.1.2.3",
+				},
+				Destination: &TrafficPeer{
+					Internal: &InternalPeer{
+						PodLabels:       map[string]string{"component": "etcd"},
+						NamespaceLabels: map[string]string{"ns": "kube-system"},
+						Namespace:       "kube-system",
+						Pods:            []*PodNetworking{pod},
+					},
+					IP: pod.IP,
+				},
+				ResolvedPort: 2379,
+				Protocol:     v1.ProtocolTCP,
+			}
+		}
+
+		It("Should deny traffic to a host-networked pod on a matching node", func() {
+			traffic := trafficTo(&PodNetworking{IP: "172.18.0.2", IsHostNetworking: true, NodeName: "control-plane", NodeLabels: controlPlaneLabels})
+			Expect(policy.IsTrafficAllowed(traffic).IsAllowed()).To(BeFalse())
+		})
+
+		It("Should allow traffic to a host-networked pod on another node", func() {
+			traffic := trafficTo(&PodNetworking{IP: "172.18.0.3", IsHostNetworking: true, NodeName: "worker", NodeLabels: map[string]string{}})
+			Expect(policy.IsTrafficAllowed(traffic).IsAllowed()).To(BeTrue())
+		})
+
+		It("Should allow traffic to a pod which isn't host-networked, even on a matching node", func() {
+			traffic := trafficTo(&PodNetworking{IP: "10.244.0.5", NodeName: "control-plane"})
+			Expect(policy.IsTrafficAllowed(traffic).IsAllowed()).To(BeTrue())
+		})
+
+		It("Should resolve host-networked pods to their node", func() {
+			peer := trafficTo(&PodNetworking{IP: "172.18.0.2", IsHostNetworking: true, NodeName: "control-plane", NodeLabels: controlPlaneLabels}).Destination
+			Expect(peer.ResolveHostNetworking().Node).To(Equal(&NodePeer{Name: "control-plane", Labels: controlPlaneLabels, IPs: []string{"172.18.0.2"}}))
+			Expect(peer.IsNode()).To(BeFalse())
+		})
+	})
+	Describe("ANP allowlist of domain names", func() {
+		anpYaml := `
+apiVersion: policy.networking.k8s.io/v1alpha1
+kind: AdminNetworkPolicy
+metadata:
+  name: allowlist
+spec:
+  priority: 10
+  subject:
+    namespaces: {}
+  egress:
+  - name: allow-to-kubernetes-io
+    action: Allow
+    to:
+    - domainNames:
+      - kubernetes.io
+      - "*.kubernetes.io"
+  - name: deny-everything-else
+    action: Deny
+    to:
+    - networks:
+      - 0.0.0.0/0`
+		anp, err := utils.ParseYaml[v1alpha1.AdminNetworkPolicy]([]byte(anpYaml))
+		utils.DoOrDie(err)
+		policy, err := BuildV1AndV2NetPols(true, nil, []*v1alpha1.AdminNetworkPolicy{anp}, nil)
+		utils.DoOrDie(err)
+		resolver := NewStaticDomainResolver(map[string][]string{
+			"www.kubernetes.io": {"1.2.3.4"},
+			"wikipedia.org":     {"5.6.7.8"},
+		})
+
+		trafficTo := func(hostname string, ip string) *Traffic {
+			traffic := &Traffic{
+				Source: &TrafficPeer{
+					Internal: &InternalPeer{
+						PodLabels:       map[string]string{"pod": "a"},
+						NamespaceLabels: map[string]string{"ns": "x"},
+						Namespace:       "x",
+					},
+					IP: "10.1.2.3",
+				},
+				Destination:  &TrafficPeer{Hostname: hostname, IP: ip},
+				ResolvedPort: 443,
+				Protocol:     v1.ProtocolTCP,
+			}
+			utils.DoOrDie(traffic.ResolveDestination(resolver))
+			return traffic
+		}
+
+		It("Should allow traffic to allowlisted domains", func() {
+			Expect(policy.IsTrafficAllowed(trafficTo("www.kubernetes.io", "")).IsAllowed()).To(BeTrue())
+		})
+
+		It("Should deny traffic to other domains", func() {
+			Expect(policy.IsTrafficAllowed(trafficTo("wikipedia.org", "")).IsAllowed()).To(BeFalse())
+		})
+
+		It("Should deny traffic to allowlisted IPs without a hostname", func() {
+			Expect(policy.IsTrafficAllowed(trafficTo("", "1.2.3.4")).IsAllowed()).To(BeFalse())
+		})
+	})
 }
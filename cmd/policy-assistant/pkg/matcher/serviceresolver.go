@@ -0,0 +1,300 @@
+package matcher
+
+import (
+	"fmt"
+
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// ServicePeer is a Service which traffic is addressed to.  Port is the port of the Service, by name or number;
+// if NodePort is set, Port is instead the Service's node port number.
+type ServicePeer struct {
+	Namespace string
+	Name      string
+	Port      intstr.IntOrString
+	NodePort  bool
+}
+
+func (s *ServicePeer) String() string {
+	if s.NodePort {
+		return fmt.Sprintf("svc %s/%s nodePort %s", s.Namespace, s.Name, s.Port.String())
+	}
+	return fmt.Sprintf("svc %s/%s:%s", s.Namespace, s.Name, s.Port.String())
+}
+
+// ServiceResolver resolves traffic to a Service into traffic to each of the Service's backend pods,
+// the same way kube-proxy would: through the Service's EndpointSlices if there are any, and otherwise
+// through its selector and the pods' container ports.
+type ServiceResolver struct {
+	Services       []*v1.Service
+	EndpointSlices []*discoveryv1.EndpointSlice
+	Pods           []*v1.Pod
+	// NamespaceLabels are the labels of each namespace; namespaces which aren't found
+	// default to the 'kubernetes.io/metadata.name' label
+	NamespaceLabels map[string]map[string]string
+}
+
+func NewServiceResolver(services *kube.Services) *ServiceResolver {
+	namespaceLabels := map[string]map[string]string{}
+	for _, ns := range services.Namespaces {
+		namespaceLabels[ns.Name] = ns.Labels
+	}
+	return &ServiceResolver{
+		Services:        services.Services,
+		EndpointSlices:  services.EndpointSlices,
+		Pods:            services.Pods,
+		NamespaceLabels: namespaceLabels,
+	}
+}
+
+// ResolveService resolves traffic addressed to a Service -- either by ServicePeer, or by the Service's ClusterIP --
+// into traffic to each backend pod, with the service port translated to each backend's target port.
+// Named target ports may resolve to different numbers on different backends.
+// Other traffic is returned as is.
+func (t *Traffic) ResolveService(resolver *ServiceResolver) ([]*Traffic, error) {
+	servicePeer := t.Destination.Service
+	if servicePeer == nil && t.Destination.IsExternal() {
+		servicePeer = resolver.serviceForClusterIP(t.Destination.IP, t.ResolvedPort)
+	}
+	if servicePeer == nil {
+		return []*Traffic{t}, nil
+	}
+
+	service, err := resolver.getService(servicePeer.Namespace, servicePeer.Name)
+	if err != nil {
+		return nil, err
+	}
+	servicePort, err := findServicePort(service, servicePeer, t.Protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	backends, err := resolver.backends(service, servicePort)
+	if err != nil {
+		return nil, err
+	}
+	if len(backends) == 0 {
+		return nil, errors.Errorf("%s has no backends", servicePeer)
+	}
+
+	var traffics []*Traffic
+	for _, backend := range backends {
+		traffics = append(traffics, &Traffic{
+			Source: t.Source,
+			Destination: &TrafficPeer{
+				Internal: &InternalPeer{
+					PodLabels:       backend.Pod.Labels,
+					NamespaceLabels: resolver.namespaceLabels(backend.Pod.Namespace),
+					Namespace:       backend.Pod.Namespace,
+				},
+				IP:      backend.IP,
+				Service: servicePeer,
+			},
+			ResolvedPort:     backend.Port,
+			ResolvedPortName: backend.PortName,
+			Protocol:         servicePort.Protocol,
+		})
+	}
+	return traffics, nil
+}
+
+type serviceBackend struct {
+	Pod      *v1.Pod
+	IP       string
+	Port     int
+	PortName string
+}
+
+func (s *ServiceResolver) serviceForClusterIP(ip string, port int) *ServicePeer {
+	if ip == "" || ip == v1.ClusterIPNone {
+		return nil
+	}
+	for _, service := range s.Services {
+		if service.Spec.ClusterIP == ip || slices.Contains(service.Spec.ClusterIPs, ip) {
+			return &ServicePeer{Namespace: service.Namespace, Name: service.Name, Port: intstr.FromInt(port)}
+		}
+	}
+	return nil
+}
+
+func (s *ServiceResolver) getService(namespace string, name string) (*v1.Service, error) {
+	for _, service := range s.Services {
+		if service.Namespace == namespace && service.Name == name {
+			return service, nil
+		}
+	}
+	return nil, errors.Errorf("unable to find service %s/%s", namespace, name)
+}
+
+func (s *ServiceResolver) getPod(namespace string, name string) (*v1.Pod, error) {
+	for _, pod := range s.Pods {
+		if pod.Namespace == namespace && pod.Name == name {
+			return pod, nil
+		}
+	}
+	return nil, errors.Errorf("unable to find pod %s/%s", namespace, name)
+}
+
+func (s *ServiceResolver) namespaceLabels(namespace string) map[string]string {
+	if nsLabels, ok := s.NamespaceLabels[namespace]; ok {
+		return nsLabels
+	}
+	return map[string]string{v1.LabelMetadataName: namespace}
+}
+
+// findServicePort finds the port of the service which the traffic is addressed to.  If the traffic has no protocol,
+// it defaults to the port's protocol.
+func findServicePort(service *v1.Service, servicePeer *ServicePeer, protocol v1.Protocol) (*v1.ServicePort, error) {
+	for i := range service.Spec.Ports {
+		port := &service.Spec.Ports[i]
+		portProtocol := port.Protocol
+		if portProtocol == "" {
+			portProtocol = v1.ProtocolTCP
+		}
+		if protocol != "" && protocol != portProtocol {
+			continue
+		}
+		var isMatch bool
+		switch {
+		case servicePeer.NodePort:
+			isMatch = servicePeer.Port.Type == intstr.Int && int32(servicePeer.Port.IntValue()) == port.NodePort && port.NodePort != 0
+		case servicePeer.Port.Type == intstr.String:
+			isMatch = servicePeer.Port.StrVal == port.Name
+		default:
+			isMatch = int32(servicePeer.Port.IntValue()) == port.Port
+		}
+		if isMatch {
+			return &v1.ServicePort{Name: port.Name, Protocol: portProtocol, Port: port.Port, TargetPort: port.TargetPort, NodePort: port.NodePort}, nil
+		}
+	}
+	return nil, errors.Errorf("%s has no matching port for protocol '%s'", servicePeer, protocol)
+}
+
+func (s *ServiceResolver) backends(service *v1.Service, servicePort *v1.ServicePort) ([]*serviceBackend, error) {
+	var endpointSlices []*discoveryv1.EndpointSlice
+	for _, endpointSlice := range s.EndpointSlices {
+		if endpointSlice.Namespace == service.Namespace && endpointSlice.Labels[discoveryv1.LabelServiceName] == service.Name {
+			endpointSlices = append(endpointSlices, endpointSlice)
+		}
+	}
+	if len(endpointSlices) > 0 {
+		return s.backendsFromEndpointSlices(endpointSlices, servicePort)
+	}
+	if len(service.Spec.Selector) == 0 {
+		return nil, errors.Errorf("service %s/%s has no selector and no EndpointSlices", service.Namespace, service.Name)
+	}
+	return s.backendsFromSelector(service, servicePort), nil
+}
+
+// backendsFromEndpointSlices uses the target port numbers from the EndpointSlices, whose ports are named after
+// the service ports.  Endpoints which aren't ready are skipped.
+func (s *ServiceResolver) backendsFromEndpointSlices(endpointSlices []*discoveryv1.EndpointSlice, servicePort *v1.ServicePort) ([]*serviceBackend, error) {
+	var backends []*serviceBackend
+	for _, endpointSlice := range endpointSlices {
+		var port *discoveryv1.EndpointPort
+		for i, p := range endpointSlice.Ports {
+			name, protocol := "", v1.ProtocolTCP
+			if p.Name != nil {
+				name = *p.Name
+			}
+			if p.Protocol != nil {
+				protocol = *p.Protocol
+			}
+			if name == servicePort.Name && protocol == servicePort.Protocol && p.Port != nil {
+				port = &endpointSlice.Ports[i]
+				break
+			}
+		}
+		if port == nil {
+			logrus.Debugf("endpoint slice %s/%s has no port %s", endpointSlice.Namespace, endpointSlice.Name, servicePort.Name)
+			continue
+		}
+
+		for _, endpoint := range endpointSlice.Endpoints {
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				continue
+			}
+			if endpoint.TargetRef == nil || endpoint.TargetRef.Kind != "Pod" || len(endpoint.Addresses) == 0 {
+				logrus.Debugf("skipping endpoint %+v of endpoint slice %s/%s: not a pod", endpoint.Addresses, endpointSlice.Namespace, endpointSlice.Name)
+				continue
+			}
+			podNamespace := endpoint.TargetRef.Namespace
+			if podNamespace == "" {
+				podNamespace = endpointSlice.Namespace
+			}
+			pod, err := s.getPod(podNamespace, endpoint.TargetRef.Name)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "unable to resolve endpoint slice %s/%s", endpointSlice.Namespace, endpointSlice.Name)
+			}
+			backends = append(backends, &serviceBackend{
+				Pod:      pod,
+				IP:       endpoint.Addresses[0],
+				Port:     int(*port.Port),
+				PortName: targetPortName(servicePort),
+			})
+		}
+	}
+	return backends, nil
+}
+
+// backendsFromSelector translates the target port on each selected pod.  Pods which don't serve a named
+// target port are skipped, as they would be by the EndpointSlice controller.
+func (s *ServiceResolver) backendsFromSelector(service *v1.Service, servicePort *v1.ServicePort) []*serviceBackend {
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+	var backends []*serviceBackend
+	for _, pod := range s.Pods {
+		if pod.Namespace != service.Namespace || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		port, err := resolveTargetPort(pod, servicePort)
+		if err != nil {
+			logrus.Debugf("skipping pod %s/%s for service %s/%s: %+v", pod.Namespace, pod.Name, service.Namespace, service.Name, err)
+			continue
+		}
+		backends = append(backends, &serviceBackend{
+			Pod:      pod,
+			IP:       pod.Status.PodIP,
+			Port:     port,
+			PortName: targetPortName(servicePort),
+		})
+	}
+	return backends
+}
+
+func resolveTargetPort(pod *v1.Pod, servicePort *v1.ServicePort) (int, error) {
+	switch servicePort.TargetPort.Type {
+	case intstr.String:
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				protocol := containerPort.Protocol
+				if protocol == "" {
+					protocol = v1.ProtocolTCP
+				}
+				if containerPort.Name == servicePort.TargetPort.StrVal && protocol == servicePort.Protocol {
+					return int(containerPort.ContainerPort), nil
+				}
+			}
+		}
+		return 0, errors.Errorf("no container port named %s on protocol %s", servicePort.TargetPort.StrVal, servicePort.Protocol)
+	default:
+		// an unset target port defaults to the service port
+		if servicePort.TargetPort.IntValue() == 0 {
+			return int(servicePort.Port), nil
+		}
+		return servicePort.TargetPort.IntValue(), nil
+	}
+}
+
+func targetPortName(servicePort *v1.ServicePort) string {
+	if servicePort.TargetPort.Type == intstr.String {
+		return servicePort.TargetPort.StrVal
+	}
+	return ""
+}
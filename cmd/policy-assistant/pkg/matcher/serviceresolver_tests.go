@@ -0,0 +1,105 @@
+package matcher
+
+import (
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func RunServiceResolverTests() {
+	Describe("ServiceResolver", func() {
+		services, err := kube.ReadServicesFromPath("../../examples/services.yaml")
+		utils.DoOrDie(err)
+		resolver := NewServiceResolver(services)
+
+		source := &TrafficPeer{
+			Internal: &InternalPeer{PodLabels: map[string]string{"pod": "a"}, NamespaceLabels: map[string]string{"ns": "x"}, Namespace: "x"},
+			IP:       "192.168.1.1",
+		}
+		toService := func(port intstr.IntOrString, nodePort bool) *Traffic {
+			return &Traffic{
+				Source:      source,
+				Destination: &TrafficPeer{Service: &ServicePeer{Namespace: "demo", Name: "web", Port: port, NodePort: nodePort}},
+				Protocol:    v1.ProtocolTCP,
+			}
+		}
+		backendPorts := func(traffics []*Traffic) map[string]int {
+			ports := map[string]int{}
+			for _, t := range traffics {
+				ports[t.Destination.IP] = t.ResolvedPort
+			}
+			return ports
+		}
+
+		It("Should translate a named target port to each backend's port", func() {
+			traffics, err := toService(intstr.FromString("http"), false).ResolveService(resolver)
+			Expect(err).To(Succeed())
+			// web-3 doesn't serve 'http', so it isn't a backend
+			Expect(backendPorts(traffics)).To(Equal(map[string]int{"192.168.2.1": 8080, "192.168.2.2": 8081}))
+			Expect(traffics[0].ResolvedPortName).To(Equal("http"))
+			Expect(traffics[0].Destination.Internal).To(Equal(&InternalPeer{
+				PodLabels:       map[string]string{"app": "web"},
+				NamespaceLabels: map[string]string{"kubernetes.io/metadata.name": "demo", "team": "web"},
+				Namespace:       "demo",
+			}))
+		})
+
+		It("Should find service ports by number and by node port", func() {
+			byNumber, err := toService(intstr.FromInt(80), false).ResolveService(resolver)
+			Expect(err).To(Succeed())
+			byNodePort, err := toService(intstr.FromInt(30080), true).ResolveService(resolver)
+			Expect(err).To(Succeed())
+			Expect(backendPorts(byNumber)).To(Equal(backendPorts(byNodePort)))
+
+			_, err = toService(intstr.FromInt(8080), false).ResolveService(resolver)
+			Expect(err).ToNot(Succeed())
+			_, err = toService(intstr.FromInt(80), true).ResolveService(resolver)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("Should use ready EndpointSlice endpoints, and resolve ClusterIPs", func() {
+			traffics, err := (&Traffic{Source: source, Destination: &TrafficPeer{IP: "10.96.0.20"}, ResolvedPort: 5432, Protocol: v1.ProtocolTCP}).ResolveService(resolver)
+			Expect(err).To(Succeed())
+			Expect(backendPorts(traffics)).To(Equal(map[string]int{"192.168.2.11": 15432}))
+			Expect(traffics[0].Destination.Service).To(Equal(&ServicePeer{Namespace: "demo", Name: "db", Port: intstr.FromInt(5432)}))
+		})
+
+		It("Should leave traffic which isn't to a service alone", func() {
+			traffic := &Traffic{Source: source, Destination: &TrafficPeer{IP: "8.8.8.8"}, ResolvedPort: 53, Protocol: v1.ProtocolUDP}
+			traffics, err := traffic.ResolveService(resolver)
+			Expect(err).To(Succeed())
+			Expect(traffics).To(Equal([]*Traffic{traffic}))
+		})
+
+		It("Should apply policies to each backend's target port", func() {
+			allowIngressOn8080, err := utils.ParseYaml[networkingv1.NetworkPolicy]([]byte(`
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: allow-ingress-on-8080
+  namespace: demo
+spec:
+  podSelector: {}
+  ingress:
+  - ports:
+    - port: 8080
+  policyTypes:
+  - Ingress`))
+			utils.DoOrDie(err)
+			policy, err := BuildNetworkPolicies(true, []*networkingv1.NetworkPolicy{allowIngressOn8080})
+			utils.DoOrDie(err)
+
+			traffics, err := toService(intstr.FromString("http"), false).ResolveService(resolver)
+			Expect(err).To(Succeed())
+			allowed := map[string]bool{}
+			for _, t := range traffics {
+				allowed[t.Destination.IP] = policy.IsTrafficAllowed(t).IsAllowed()
+			}
+			Expect(allowed).To(Equal(map[string]bool{"192.168.2.1": true, "192.168.2.2": false}))
+		})
+	})
+}
@@ -12,5 +12,9 @@ func TestMatcher(t *testing.T) {
 	RunBuilderTests()
 	RunPolicyTests()
 	RunSimplifierTests()
+	RunDomainPeerMatcherTests()
+	RunOutputTests()
+	RunServiceResolverTests()
+	RunLintTests()
 	RunSpecs(t, "network policy matcher suite")
 }
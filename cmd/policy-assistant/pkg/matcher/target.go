@@ -2,8 +2,10 @@ package matcher
 
 import (
 	"fmt"
+	"github.com/mattfenwick/collections/pkg/slice"
 	"github.com/mattfenwick/cyclonus/pkg/kube"
 	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -50,12 +52,56 @@ type Target struct {
 	// Order matters for rules in the same ANP or BANP.
 	// Priority matters for rules in different ANPs.
 	Peers []PeerMatcher
+	// V1Rules are the rules of a v1 Target's NetPols.  Unlike Peers, they aren't combined or simplified,
+	// so that the effect of each rule can be traced back to it.
+	V1Rules []*V1Rule
+}
+
+// V1Rule is a single ingress or egress rule of a v1 NetPol
+type V1Rule struct {
+	// PolicyName is the namespace/name of the NetPol
+	PolicyName string
+	// Path locates the rule in the NetPol, e.g. "ingress[2]"
+	Path  string
+	Peers []PeerMatcher
+}
+
+// Effect returns the Effect of the rule on traffic
+func (r *V1Rule) Effect(subject, peer *TrafficPeer, portInt int, portName string, protocol v1.Protocol) Effect {
+	effect := Effect{PolicyKind: NetworkPolicyV1, Verdict: None, PolicyName: r.PolicyName, RulePath: r.Path}
+	for _, m := range r.Peers {
+		if m.Matches(subject, peer, portInt, portName, protocol) {
+			effect.Verdict = Allow
+			break
+		}
+	}
+	return effect
+}
+
+// NewV1Target creates the ingress or egress Target of a v1 NetPol from its rules
+func NewV1Target(namespace string, podSelector metav1.LabelSelector, source NetPolID, rules []*V1Rule) *Target {
+	target := &Target{
+		SubjectMatcher: NewSubjectV1(namespace, podSelector),
+		SourceRules:    []NetPolID{source},
+		V1Rules:        rules,
+	}
+	for _, rule := range rules {
+		target.Peers = append(target.Peers, rule.Peers...)
+	}
+	return target
 }
 
 func (t *Target) String() string {
 	return t.GetPrimaryKey()
 }
 
+// v1PolicyNames returns the namespace/name of each NetPol of a v1 Target, sorted and comma-separated
+func (t *Target) v1PolicyNames() string {
+	prefix := fmt.Sprintf("[%s] ", NetworkPolicyV1)
+	names := slice.Map(func(id NetPolID) string { return strings.TrimPrefix(string(id), prefix) }, t.SourceRules)
+	return strings.Join(slice.Sort(names), ", ")
+}
+
 func (t *Target) Simplify() {
 	t.Peers = Simplify(t.Peers)
 }
@@ -74,6 +120,7 @@ func (t *Target) Combine(other *Target) *Target {
 		SubjectMatcher: t.SubjectMatcher,
 		Peers:          append(t.Peers, other.Peers...),
 		SourceRules:    sets.New(t.SourceRules...).Insert(other.SourceRules...).UnsortedList(),
+		V1Rules:        append(append([]*V1Rule{}, t.V1Rules...), other.V1Rules...),
 	}
 }
 
@@ -87,10 +134,12 @@ func CombineTargetsIgnoringPrimaryKey(namespace string, podSelector metav1.Label
 		SubjectMatcher: NewSubjectV1(namespace, podSelector),
 		Peers:          targets[0].Peers,
 		SourceRules:    targets[0].SourceRules,
+		V1Rules:        targets[0].V1Rules,
 	}
 	for _, t := range targets[1:] {
 		target.Peers = append(target.Peers, t.Peers...)
 		target.SourceRules = append(target.SourceRules, t.SourceRules...)
+		target.V1Rules = append(target.V1Rules, t.V1Rules...)
 	}
 	return target
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/olekukonko/tablewriter"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	v1 "k8s.io/api/core/v1"
 )
 
@@ -29,25 +30,10 @@ func (t *Traffic) Table() string {
 	table.SetAutoMergeCells(true)
 
 	pp := fmt.Sprintf("%d (%s) on %s", t.ResolvedPort, t.ResolvedPortName, t.Protocol)
-	table.SetHeader([]string{"Port/Protocol", "Source/Dest", "Pod IP", "Namespace", "NS Labels", "Pod Labels"})
+	table.SetHeader([]string{"Port/Protocol", "Source/Dest", "Pod IP", "Namespace", "NS Labels", "Pod Labels", "Node", "Node Labels"})
 
-	source := []string{pp, "source", t.Source.IP}
-	if t.Source.Internal != nil {
-		i := t.Source.Internal
-		source = append(source, i.Namespace, labelsToString(i.NamespaceLabels), labelsToString(i.PodLabels))
-	} else {
-		source = append(source, "", "", "")
-	}
-	table.Append(source)
-
-	dest := []string{pp, "destination", t.Destination.IP}
-	if t.Destination.Internal != nil {
-		i := t.Destination.Internal
-		dest = append(dest, i.Namespace, labelsToString(i.NamespaceLabels), labelsToString(i.PodLabels))
-	} else {
-		dest = append(dest, "", "", "")
-	}
-	table.Append(dest)
+	table.Append(append([]string{pp, "source"}, t.Source.tableCells()...))
+	table.Append(append([]string{pp, "destination"}, t.Destination.tableCells()...))
 
 	table.Render()
 	return tableString.String()
@@ -60,8 +46,45 @@ func labelsToString(labels map[string]string) string {
 
 type TrafficPeer struct {
 	Internal *InternalPeer
+	// Node is set if the peer is a node, or host-networked pods running on a node; see ResolveHostNetworking
+	Node *NodePeer
 	// IP external to cluster
 	IP string
+	// Hostname is optional, and is used to match ANP egress domainNames peers
+	Hostname string
+	// Service is optional, and is set for traffic addressed to a Service; see Traffic.ResolveService
+	Service *ServicePeer
+}
+
+// NodePeer models a node as a traffic peer.
+// Host-networked pods share the network of their node, so they resolve to a NodePeer.
+type NodePeer struct {
+	Name   string
+	Labels map[string]string
+	IPs    []string
+}
+
+func (p *TrafficPeer) tableCells() []string {
+	ip := p.IP
+	if p.Hostname != "" {
+		ip = fmt.Sprintf("%s\n(%s)", p.IP, p.Hostname)
+	}
+	if p.Service != nil {
+		ip = fmt.Sprintf("%s\n(%s)", ip, p.Service)
+	}
+	cells := []string{ip}
+	if p.Internal != nil {
+		i := p.Internal
+		cells = append(cells, i.Namespace, labelsToString(i.NamespaceLabels), labelsToString(i.PodLabels))
+	} else {
+		cells = append(cells, "", "", "")
+	}
+	if p.Node != nil {
+		cells = append(cells, p.Node.Name, labelsToString(p.Node.Labels))
+	} else {
+		cells = append(cells, "", "")
+	}
+	return cells
 }
 
 func (p *TrafficPeer) Namespace() string {
@@ -72,7 +95,33 @@ func (p *TrafficPeer) Namespace() string {
 }
 
 func (p *TrafficPeer) IsExternal() bool {
-	return p.Internal == nil
+	return p.Internal == nil && p.Node == nil
+}
+
+// IsNode returns true if the peer is a node, or host-networked pods running on a node.
+func (p *TrafficPeer) IsNode() bool {
+	return p.Node != nil
+}
+
+// ResolveHostNetworking returns the peer as the node its pods run on, if they're all host-networked pods
+// on the same node, since traffic to or from them is traffic to or from that node.
+// Otherwise, it returns the peer unchanged.
+func (p *TrafficPeer) ResolveHostNetworking() *TrafficPeer {
+	if p.Internal == nil || p.Node != nil || len(p.Internal.Pods) == 0 {
+		return p
+	}
+	node := &NodePeer{Name: p.Internal.Pods[0].NodeName, Labels: p.Internal.Pods[0].NodeLabels}
+	for _, pod := range p.Internal.Pods {
+		if !pod.IsHostNetworking || pod.NodeName != node.Name {
+			return p
+		}
+		if pod.IP != "" && !slices.Contains(node.IPs, pod.IP) {
+			node.IPs = append(node.IPs, pod.IP)
+		}
+	}
+	resolved := *p
+	resolved.Node = node
+	return &resolved
 }
 
 func (p *TrafficPeer) Translate() TrafficPeer {
@@ -83,6 +132,7 @@ func (p *TrafficPeer) Translate() TrafficPeer {
 	var workloadOwner string
 	var workloadKind string
 	var internalPeer InternalPeer
+	nodeLabels := map[string]map[string]string{}
 	workloadOwnerExists := false
 	workloadMetadata := strings.Split(strings.ToLower(p.Internal.Workload), "/")
 	if len(workloadMetadata) != 3 || (workloadMetadata[0] == "" || workloadMetadata[1] == "" || workloadMetadata[2] == "") || (workloadMetadata[1] != "daemonset" && workloadMetadata[1] != "statefulset" && workloadMetadata[1] != "replicaset" && workloadMetadata[1] != "deployment" && workloadMetadata[1] != "pod") {
@@ -118,7 +168,17 @@ func (p *TrafficPeer) Translate() TrafficPeer {
 			podLabels = pod.Labels
 			namespaceLabels = ns.Labels
 			podNetworking := PodNetworking{
-				IP: pod.Status.PodIP,
+				IP:               pod.Status.PodIP,
+				IsHostNetworking: pod.Spec.HostNetwork,
+				NodeName:         pod.Spec.NodeName,
+			}
+			if pod.Spec.HostNetwork && pod.Spec.NodeName != "" {
+				if _, ok := nodeLabels[pod.Spec.NodeName]; !ok {
+					node, err := kubeClient.GetNode(pod.Spec.NodeName)
+					utils.DoOrDie(err)
+					nodeLabels[pod.Spec.NodeName] = node.Labels
+				}
+				podNetworking.NodeLabels = nodeLabels[pod.Spec.NodeName]
 			}
 			podsNetworking = append(podsNetworking, &podNetworking)
 			workloadOwnerExists = true
@@ -336,7 +396,9 @@ type InternalPeer struct {
 
 type PodNetworking struct {
 	IP string
-	// don't worry about populating below fields right now
+	// IsHostNetworking is true for pods running in their node's network, see TrafficPeer.ResolveHostNetworking
 	IsHostNetworking bool
-	NodeLabels       []string
+	NodeName         string
+	// NodeLabels are only set for host-networked pods
+	NodeLabels map[string]string
 }
@@ -0,0 +1,79 @@
+package matcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidationError describes why a policy could not be built into matchers.
+type ValidationError struct {
+	PolicyID NetPolID `json:"policyId"`
+	// Field is the path to the invalid field, e.g. spec.ingress[0].from[1]
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+func (v *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", v.PolicyID, v.Field, v.Reason)
+}
+
+// ValidationErrors aggregates ValidationErrors across any number of policies.
+type ValidationErrors []*ValidationError
+
+func NewValidationErrors(policyID NetPolID, fieldErrors field.ErrorList) ValidationErrors {
+	var errs ValidationErrors
+	for _, fieldError := range fieldErrors {
+		errs = append(errs, &ValidationError{
+			PolicyID: policyID,
+			Field:    fieldError.Field,
+			Reason:   fieldError.ErrorBody(),
+		})
+	}
+	return errs
+}
+
+func (v ValidationErrors) Error() string {
+	lines := make([]string, len(v))
+	for i, e := range v {
+		lines[i] = e.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// InvalidPolicies returns the unique IDs of the invalid policies, in the order they were found.
+func (v ValidationErrors) InvalidPolicies() []NetPolID {
+	seen := map[NetPolID]bool{}
+	var ids []NetPolID
+	for _, e := range v {
+		if !seen[e.PolicyID] {
+			seen[e.PolicyID] = true
+			ids = append(ids, e.PolicyID)
+		}
+	}
+	return ids
+}
+
+func (v ValidationErrors) Table() string {
+	tableString := &strings.Builder{}
+	table := tablewriter.NewWriter(tableString)
+	table.SetAutoWrapText(false)
+	table.SetRowLine(true)
+	table.SetAutoMergeCells(true)
+	table.SetHeader([]string{"Policy", "Field", "Reason"})
+	for _, e := range v {
+		table.Append([]string{string(e.PolicyID), e.Field, e.Reason})
+	}
+	table.Render()
+	return tableString.String()
+}
+
+// toError avoids returning a non-nil error interface wrapping an empty ValidationErrors
+func (v ValidationErrors) toError() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
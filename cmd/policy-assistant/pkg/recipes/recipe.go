@@ -28,8 +28,14 @@ func (r *Recipe) Policies() []*networkingv1.NetworkPolicy {
 	return policies
 }
 
+func (r *Recipe) Policy() *matcher.Policy {
+	policy, err := matcher.BuildNetworkPolicies(true, r.Policies())
+	utils.DoOrDie(err)
+	return policy
+}
+
 func (r *Recipe) RunProbe() *probe.Table {
-	runner := probe.NewSimulatedRunner(matcher.BuildNetworkPolicies(true, r.Policies()), &probe.JobBuilder{TimeoutSeconds: 5})
+	runner := probe.NewSimulatedRunner(r.Policy(), &probe.JobBuilder{TimeoutSeconds: 5})
 	return runner.RunProbeForConfig(generator.NewProbeConfig(intstr.FromInt(r.Port), r.Protocol, generator.ProbeModeServiceName), r.Resources)
 }
 
@@ -55,7 +61,7 @@ func Run() {
 	for _, recipe := range AllRecipes {
 		table := recipe.RunProbe()
 
-		fmt.Printf("Policies:\n%s\n", matcher.BuildNetworkPolicies(true, recipe.Policies()).ExplainTable())
+		fmt.Printf("Policies:\n%s\n", recipe.Policy().ExplainTable())
 
 		fmt.Printf("resources:\n%s\n", recipe.Resources.RenderTable())
 
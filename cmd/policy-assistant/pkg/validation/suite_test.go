@@ -0,0 +1,14 @@
+package validation
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestValidation(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunValidationTests()
+	RunSpecs(t, "admin network policy validation suite")
+}
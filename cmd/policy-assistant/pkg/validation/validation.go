@@ -0,0 +1,318 @@
+package validation
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
+)
+
+// These mirror the kubebuilder validations on the v1alpha1 types, which end up in the CRDs under config/crd.
+const (
+	MinPriority = 0
+	MaxPriority = 1000
+
+	MaxRules      = 100
+	MaxPeers      = 100
+	MaxPorts      = 100
+	MaxNetworks   = 25
+	MaxDomains    = 25
+	MaxRuleName   = 100
+	MaxCIDRLength = 43
+
+	MinPort = 1
+	MaxPort = 65535
+
+	BaselineAdminNetworkPolicyName = "default"
+
+	// messages copied verbatim from the CEL rules
+	cidrFamilyMessage                 = "CIDR must be either an IPv4 or IPv6 address. IPv4 address embedded in IPv6 addresses are not supported"
+	namedPortWithNetworksOrNodesMsg   = "networks/nodes peer cannot be set with namedPorts since there are no namedPorts for networks/nodes"
+	baselineAdminNetworkPolicyNameMsg = "Only one baseline admin network policy with metadata.name=\"default\" can be created in the cluster"
+)
+
+var (
+	// domainNamePattern is copied verbatim from the DomainName type, including the 'A-z' range
+	domainNamePattern = `^(\*\.)?([a-zA-z0-9]([-a-zA-Z0-9_]*[a-zA-Z0-9])?\.)+[a-zA-z0-9]([-a-zA-Z0-9_]*[a-zA-Z0-9])?\.?$`
+	domainNameRegexp  = regexp.MustCompile(domainNamePattern)
+
+	anpActions  = []string{string(v1alpha1.AdminNetworkPolicyRuleActionAllow), string(v1alpha1.AdminNetworkPolicyRuleActionDeny), string(v1alpha1.AdminNetworkPolicyRuleActionPass)}
+	banpActions = []string{string(v1alpha1.BaselineAdminNetworkPolicyRuleActionAllow), string(v1alpha1.BaselineAdminNetworkPolicyRuleActionDeny)}
+)
+
+// ValidateAdminNetworkPolicy checks an ANP against the same OpenAPI and CEL rules that the CRD enforces,
+// so that problems can be found without a cluster.
+func ValidateAdminNetworkPolicy(anp *v1alpha1.AdminNetworkPolicy) field.ErrorList {
+	specPath := field.NewPath("spec")
+	var errs field.ErrorList
+
+	if anp.Spec.Priority < MinPriority || anp.Spec.Priority > MaxPriority {
+		errs = append(errs, field.Invalid(specPath.Child("priority"), anp.Spec.Priority, fmt.Sprintf("must be between %d and %d, inclusive", MinPriority, MaxPriority)))
+	}
+	errs = append(errs, validateSubject(anp.Spec.Subject, specPath.Child("subject"))...)
+
+	ingressPath := specPath.Child("ingress")
+	if len(anp.Spec.Ingress) > MaxRules {
+		errs = append(errs, field.TooMany(ingressPath, len(anp.Spec.Ingress), MaxRules))
+	}
+	for i, rule := range anp.Spec.Ingress {
+		rulePath := ingressPath.Index(i)
+		errs = append(errs, validateRuleName(rule.Name, rulePath.Child("name"))...)
+		errs = append(errs, ValidateANPAction(rule.Action, rulePath.Child("action"))...)
+		errs = append(errs, validateIngressPeers(rule.From, rulePath.Child("from"))...)
+		errs = append(errs, validatePorts(rule.Ports, rulePath.Child("ports"))...)
+	}
+
+	egressPath := specPath.Child("egress")
+	if len(anp.Spec.Egress) > MaxRules {
+		errs = append(errs, field.TooMany(egressPath, len(anp.Spec.Egress), MaxRules))
+	}
+	for i, rule := range anp.Spec.Egress {
+		rulePath := egressPath.Index(i)
+		errs = append(errs, validateRuleName(rule.Name, rulePath.Child("name"))...)
+		errs = append(errs, ValidateANPAction(rule.Action, rulePath.Child("action"))...)
+
+		toPath := rulePath.Child("to")
+		errs = append(errs, validatePeerCount(len(rule.To), toPath)...)
+		hasNetworksOrNodes := false
+		for j, peer := range rule.To {
+			peerPath := toPath.Index(j)
+			errs = append(errs, validateExactlyOneProperty(peerPath, peer.Namespaces != nil, peer.Pods != nil, peer.Nodes != nil, peer.Networks != nil, peer.DomainNames != nil)...)
+			errs = append(errs, validateNetworks(peer.Networks, peerPath.Child("networks"))...)
+			errs = append(errs, validateDomainNames(peer.DomainNames, peerPath.Child("domainNames"))...)
+			hasNetworksOrNodes = hasNetworksOrNodes || peer.Networks != nil || peer.Nodes != nil
+		}
+		errs = append(errs, validatePorts(rule.Ports, rulePath.Child("ports"))...)
+		errs = append(errs, validateNoNamedPortsWithNetworksOrNodes(hasNetworksOrNodes, rule.Ports, rulePath)...)
+	}
+
+	return errs
+}
+
+// ValidateBaselineAdminNetworkPolicy checks a BANP against the same OpenAPI and CEL rules that the CRD enforces,
+// so that problems can be found without a cluster.
+func ValidateBaselineAdminNetworkPolicy(banp *v1alpha1.BaselineAdminNetworkPolicy) field.ErrorList {
+	specPath := field.NewPath("spec")
+	var errs field.ErrorList
+
+	if banp.Name != BaselineAdminNetworkPolicyName {
+		errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), banp.Name, baselineAdminNetworkPolicyNameMsg))
+	}
+	errs = append(errs, validateSubject(banp.Spec.Subject, specPath.Child("subject"))...)
+
+	ingressPath := specPath.Child("ingress")
+	if len(banp.Spec.Ingress) > MaxRules {
+		errs = append(errs, field.TooMany(ingressPath, len(banp.Spec.Ingress), MaxRules))
+	}
+	for i, rule := range banp.Spec.Ingress {
+		rulePath := ingressPath.Index(i)
+		errs = append(errs, validateRuleName(rule.Name, rulePath.Child("name"))...)
+		errs = append(errs, ValidateBANPAction(rule.Action, rulePath.Child("action"))...)
+		errs = append(errs, validateIngressPeers(rule.From, rulePath.Child("from"))...)
+		errs = append(errs, validatePorts(rule.Ports, rulePath.Child("ports"))...)
+	}
+
+	egressPath := specPath.Child("egress")
+	if len(banp.Spec.Egress) > MaxRules {
+		errs = append(errs, field.TooMany(egressPath, len(banp.Spec.Egress), MaxRules))
+	}
+	for i, rule := range banp.Spec.Egress {
+		rulePath := egressPath.Index(i)
+		errs = append(errs, validateRuleName(rule.Name, rulePath.Child("name"))...)
+		errs = append(errs, ValidateBANPAction(rule.Action, rulePath.Child("action"))...)
+
+		toPath := rulePath.Child("to")
+		errs = append(errs, validatePeerCount(len(rule.To), toPath)...)
+		hasNetworksOrNodes := false
+		for j, peer := range rule.To {
+			peerPath := toPath.Index(j)
+			errs = append(errs, validateExactlyOneProperty(peerPath, peer.Namespaces != nil, peer.Pods != nil, peer.Nodes != nil, peer.Networks != nil)...)
+			errs = append(errs, validateNetworks(peer.Networks, peerPath.Child("networks"))...)
+			hasNetworksOrNodes = hasNetworksOrNodes || peer.Networks != nil || peer.Nodes != nil
+		}
+		errs = append(errs, validatePorts(rule.Ports, rulePath.Child("ports"))...)
+		errs = append(errs, validateNoNamedPortsWithNetworksOrNodes(hasNetworksOrNodes, rule.Ports, rulePath)...)
+	}
+
+	return errs
+}
+
+func validateSubject(subject v1alpha1.AdminNetworkPolicySubject, fldPath *field.Path) field.ErrorList {
+	return validateExactlyOneProperty(fldPath, subject.Namespaces != nil, subject.Pods != nil)
+}
+
+func validateIngressPeers(peers []v1alpha1.AdminNetworkPolicyIngressPeer, fldPath *field.Path) field.ErrorList {
+	errs := validatePeerCount(len(peers), fldPath)
+	for i, peer := range peers {
+		errs = append(errs, validateExactlyOneProperty(fldPath.Index(i), peer.Namespaces != nil, peer.Pods != nil)...)
+	}
+	return errs
+}
+
+func validatePeerCount(count int, fldPath *field.Path) field.ErrorList {
+	if count == 0 {
+		return field.ErrorList{field.Required(fldPath, "must have at least 1 peer")}
+	}
+	if count > MaxPeers {
+		return field.ErrorList{field.TooMany(fldPath, count, MaxPeers)}
+	}
+	return nil
+}
+
+// validateExactlyOneProperty mirrors MinProperties=1 and MaxProperties=1
+func validateExactlyOneProperty(fldPath *field.Path, isSet ...bool) field.ErrorList {
+	count := 0
+	for _, s := range isSet {
+		if s {
+			count++
+		}
+	}
+	if count == 0 {
+		return field.ErrorList{field.Required(fldPath, "exactly one field must be set")}
+	}
+	if count > 1 {
+		return field.ErrorList{field.Invalid(fldPath, fmt.Sprintf("%d fields", count), "exactly one field must be set")}
+	}
+	return nil
+}
+
+func validateRuleName(name string, fldPath *field.Path) field.ErrorList {
+	if len(name) > MaxRuleName {
+		return field.ErrorList{field.TooLong(fldPath, name, MaxRuleName)}
+	}
+	return nil
+}
+
+// ValidateANPAction checks that an ANP rule's action is one of the enum values; they're case-sensitive.
+func ValidateANPAction(action v1alpha1.AdminNetworkPolicyRuleAction, fldPath *field.Path) field.ErrorList {
+	return validateAction(string(action), anpActions, fldPath)
+}
+
+// ValidateBANPAction checks that a BANP rule's action is one of the enum values; they're case-sensitive.
+func ValidateBANPAction(action v1alpha1.BaselineAdminNetworkPolicyRuleAction, fldPath *field.Path) field.ErrorList {
+	return validateAction(string(action), banpActions, fldPath)
+}
+
+func validateAction(action string, allowed []string, fldPath *field.Path) field.ErrorList {
+	for _, a := range allowed {
+		if action == a {
+			return nil
+		}
+	}
+	return field.ErrorList{field.NotSupported(fldPath, action, allowed)}
+}
+
+func validatePorts(ports *[]v1alpha1.AdminNetworkPolicyPort, fldPath *field.Path) field.ErrorList {
+	if ports == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if len(*ports) > MaxPorts {
+		errs = append(errs, field.TooMany(fldPath, len(*ports), MaxPorts))
+	}
+	for i, port := range *ports {
+		portPath := fldPath.Index(i)
+		errs = append(errs, validateExactlyOneProperty(portPath, port.PortNumber != nil, port.NamedPort != nil, port.PortRange != nil)...)
+		if port.PortNumber != nil {
+			errs = append(errs, validatePortNumber(port.PortNumber.Port, portPath.Child("portNumber", "port"))...)
+		}
+		if port.PortRange != nil {
+			rangePath := portPath.Child("portRange")
+			errs = append(errs, validatePortNumber(port.PortRange.Start, rangePath.Child("start"))...)
+			errs = append(errs, validatePortNumber(port.PortRange.End, rangePath.Child("end"))...)
+			if port.PortRange.Start >= port.PortRange.End {
+				errs = append(errs, field.Invalid(rangePath, fmt.Sprintf("%d-%d", port.PortRange.Start, port.PortRange.End), "start must be less than end"))
+			}
+		}
+	}
+	return errs
+}
+
+func validatePortNumber(port int32, fldPath *field.Path) field.ErrorList {
+	if port < MinPort || port > MaxPort {
+		return field.ErrorList{field.Invalid(fldPath, port, fmt.Sprintf("must be between %d and %d, inclusive", MinPort, MaxPort))}
+	}
+	return nil
+}
+
+// validateNoNamedPortsWithNetworksOrNodes mirrors the CEL rule on egress rules
+func validateNoNamedPortsWithNetworksOrNodes(hasNetworksOrNodes bool, ports *[]v1alpha1.AdminNetworkPolicyPort, rulePath *field.Path) field.ErrorList {
+	if !hasNetworksOrNodes || ports == nil {
+		return nil
+	}
+	for _, port := range *ports {
+		if port.NamedPort != nil {
+			return field.ErrorList{field.Forbidden(rulePath, namedPortWithNetworksOrNodesMsg)}
+		}
+	}
+	return nil
+}
+
+func validateNetworks(networks []v1alpha1.CIDR, fldPath *field.Path) field.ErrorList {
+	if networks == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if len(networks) == 0 {
+		errs = append(errs, field.Required(fldPath, "must have at least 1 item"))
+	}
+	if len(networks) > MaxNetworks {
+		errs = append(errs, field.TooMany(fldPath, len(networks), MaxNetworks))
+	}
+	seen := map[v1alpha1.CIDR]bool{}
+	for i, cidr := range networks {
+		if seen[cidr] {
+			errs = append(errs, field.Duplicate(fldPath.Index(i), cidr))
+		}
+		seen[cidr] = true
+		errs = append(errs, ValidateNetworkCIDR(string(cidr), fldPath.Index(i))...)
+	}
+	return errs
+}
+
+// ValidateNetworkCIDR mirrors the CIDR type's CEL and MaxLength rules, for ANP/BANP networks peers.  In addition, as
+// the type requires of implementations, it checks that the CIDR can be parsed by net.ParseCIDR.
+func ValidateNetworkCIDR(cidr string, fldPath *field.Path) field.ErrorList {
+	if len(cidr) > MaxCIDRLength {
+		return field.ErrorList{field.TooLong(fldPath, cidr, MaxCIDRLength)}
+	}
+	if strings.Contains(cidr, ":") == strings.Contains(cidr, ".") {
+		return field.ErrorList{field.Invalid(fldPath, cidr, cidrFamilyMessage)}
+	}
+	return ValidateCIDR(cidr, fldPath)
+}
+
+// ValidateCIDR checks that the CIDR can be parsed by net.ParseCIDR, which is all that NetworkPolicy ipBlocks require.
+func ValidateCIDR(cidr string, fldPath *field.Path) field.ErrorList {
+	if _, _, err := net.ParseCIDR(cidr); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, cidr, "must be a valid CIDR")}
+	}
+	return nil
+}
+
+func validateDomainNames(domainNames []v1alpha1.DomainName, fldPath *field.Path) field.ErrorList {
+	if domainNames == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if len(domainNames) == 0 {
+		errs = append(errs, field.Required(fldPath, "must have at least 1 item"))
+	}
+	if len(domainNames) > MaxDomains {
+		errs = append(errs, field.TooMany(fldPath, len(domainNames), MaxDomains))
+	}
+	seen := map[v1alpha1.DomainName]bool{}
+	for i, domainName := range domainNames {
+		if seen[domainName] {
+			errs = append(errs, field.Duplicate(fldPath.Index(i), domainName))
+		}
+		seen[domainName] = true
+		if !domainNameRegexp.MatchString(string(domainName)) {
+			errs = append(errs, field.Invalid(fldPath.Index(i), domainName, fmt.Sprintf("should match '%s'", domainNamePattern)))
+		}
+	}
+	return errs
+}
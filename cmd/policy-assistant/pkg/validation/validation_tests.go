@@ -0,0 +1,178 @@
+package validation
+
+import (
+	"github.com/mattfenwick/collections/pkg/slice"
+	"github.com/mattfenwick/cyclonus/examples"
+	"github.com/mattfenwick/cyclonus/pkg/kube"
+	"github.com/mattfenwick/cyclonus/pkg/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
+)
+
+func errorFields(errs field.ErrorList) []string {
+	return slice.Map(func(e *field.Error) string { return e.Field }, errs)
+}
+
+func validateANPYaml(anpYaml string) field.ErrorList {
+	anp, err := utils.ParseYaml[v1alpha1.AdminNetworkPolicy]([]byte(anpYaml))
+	utils.DoOrDie(err)
+	return ValidateAdminNetworkPolicy(anp)
+}
+
+func validateBANPYaml(banpYaml string) field.ErrorList {
+	banp, err := utils.ParseYaml[v1alpha1.BaselineAdminNetworkPolicy]([]byte(banpYaml))
+	utils.DoOrDie(err)
+	return ValidateBaselineAdminNetworkPolicy(banp)
+}
+
+func RunValidationTests() {
+	Describe("Valid policies", func() {
+		It("should accept the example policies", func() {
+			for _, anp := range append(examples.CoreGressRulesCombinedANB, examples.SimpleANPs...) {
+				Expect(ValidateAdminNetworkPolicy(anp)).To(BeEmpty(), anp.Name)
+			}
+			Expect(ValidateBaselineAdminNetworkPolicy(examples.CoreGressRulesCombinedBANB)).To(BeEmpty())
+		})
+
+		It("should accept the admin policies on disk", func() {
+			policies, err := kube.ReadPoliciesFromPath("../../networkpolicies/admin")
+			Expect(err).To(BeNil())
+			for _, anp := range policies.AdminNetworkPolicies {
+				Expect(ValidateAdminNetworkPolicy(anp)).To(BeEmpty(), anp.Name)
+			}
+			for _, banp := range policies.BaselineAdminNetworkPolicies {
+				Expect(ValidateBaselineAdminNetworkPolicy(banp)).To(BeEmpty(), banp.Name)
+			}
+		})
+	})
+
+	Describe("Invalid AdminNetworkPolicies", func() {
+		It("should reject out of bounds priorities, subjects with 0 or 2 fields, and unknown actions", func() {
+			errs := validateANPYaml(`
+metadata:
+  name: abc
+spec:
+  priority: 1001
+  subject: {}
+  ingress:
+  - name: xyz
+    action: Reject
+    from:
+    - namespaces: {}
+      pods:
+        namespaceSelector: {}
+        podSelector: {}`)
+			Expect(errorFields(errs)).To(Equal([]string{
+				"spec.priority",
+				"spec.subject",
+				"spec.ingress[0].action",
+				"spec.ingress[0].from[0]",
+			}))
+		})
+
+		It("should reject missing peers, bad ports, and too-long rule names", func() {
+			errs := validateANPYaml(`
+metadata:
+  name: abc
+spec:
+  priority: 3
+  subject:
+    namespaces: {}
+  ingress:
+  - name: aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa
+    action: Allow
+    from: []
+    ports:
+    - portNumber:
+        port: 0
+        protocol: TCP
+    - portRange:
+        start: 90
+        end: 80
+    - {}`)
+			Expect(errorFields(errs)).To(Equal([]string{
+				"spec.ingress[0].name",
+				"spec.ingress[0].from",
+				"spec.ingress[0].ports[0].portNumber.port",
+				"spec.ingress[0].ports[1].portRange",
+				"spec.ingress[0].ports[2]",
+			}))
+		})
+
+		It("should reject bad and duplicate networks and domain names", func() {
+			errs := validateANPYaml(`
+metadata:
+  name: abc
+spec:
+  priority: 3
+  subject:
+    namespaces: {}
+  egress:
+  - action: Deny
+    to:
+    - networks:
+      - 10.0.0.0/8
+      - 10.0.0.0/8
+      - ::ffff:1.2.3.4/128
+      - 10.0.0.0/33
+  - action: Allow
+    to:
+    - domainNames:
+      - "*.kubernetes.io"
+      - "www.*.io"
+      - nodots`)
+			Expect(errorFields(errs)).To(Equal([]string{
+				"spec.egress[0].to[0].networks[1]",
+				"spec.egress[0].to[0].networks[2]",
+				"spec.egress[0].to[0].networks[3]",
+				"spec.egress[1].to[0].domainNames[1]",
+				"spec.egress[1].to[0].domainNames[2]",
+			}))
+		})
+
+		It("should reject named ports with networks or nodes peers", func() {
+			errs := validateANPYaml(`
+metadata:
+  name: abc
+spec:
+  priority: 3
+  subject:
+    namespaces: {}
+  egress:
+  - action: Deny
+    to:
+    - nodes: {}
+    ports:
+    - namedPort: http`)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.egress[0]"))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+		})
+	})
+
+	Describe("Invalid BaselineAdminNetworkPolicies", func() {
+		It("should reject names other than 'default' and Pass actions", func() {
+			errs := validateBANPYaml(`
+metadata:
+  name: not-default
+spec:
+  subject:
+    namespaces: {}
+  egress:
+  - action: Pass
+    to:
+    - networks:
+      - 10.0.0.0/8
+      pods:
+        namespaceSelector: {}
+        podSelector: {}`)
+			Expect(errorFields(errs)).To(Equal([]string{
+				"metadata.name",
+				"spec.egress[0].action",
+				"spec.egress[0].to[0]",
+			}))
+		})
+	})
+}
@@ -94,79 +94,85 @@ func TestExplain(t *testing.T) {
 			"|         |    all pods        |                                                                            |                      |                         |                           |\n" +
 			"+---------+--------------------+----------------------------------------------------------------------------+----------------------+-------------------------+---------------------------+\n" +
 			""
-		policies := matcher.BuildV1AndV2NetPols(true, netpol.AllExamples, nil, nil)
+		policies, err := matcher.BuildV1AndV2NetPols(true, netpol.AllExamples, nil, nil)
+		require.NoError(t, err)
 		require.Equal(t, expected, policies.ExplainTable())
 	})
 
 	t.Run("prints network ANPs and BANPs", func(t *testing.T) {
-		expected := "+---------+------------------------------------------+-----------------------------+------------------------------------------------------------------------+------------------------------------------------------------------+----------------------------+\n" +
-			"|  TYPE   |                 SUBJECT                  |        SOURCE RULES         |                                  PEER                                  |                              ACTION                              |       PORT/PROTOCOL        |\n" +
-			"+---------+------------------------------------------+-----------------------------+------------------------------------------------------------------------+------------------------------------------------------------------+----------------------------+\n" +
-			"| Ingress | Namespace:                               | [ANP] default/example-anp   | Namespace:                                                             | ANP:                                                             | all ports, all protocols   |\n" +
-			"|         |    kubernetes.io/metadata.name Exists [] | [ANP] default/example-anp-2 |    kubernetes.io/metadata.name = network-policy-conformance-hufflepuff |    pri=16 (example-anp-2): Deny                                  |                            |\n" +
-			"|         |                                          | [BANP] default/default      | Pod:                                                                   |    pri=20 (example-anp): Deny                                    |                            |\n" +
-			"|         |                                          |                             |    all                                                                 | BANP:                                                            |                            |\n" +
-			"|         |                                          |                             |                                                                        |    Deny                                                          |                            |\n" +
-			"+         +                                          +                             +------------------------------------------------------------------------+------------------------------------------------------------------+                            +\n" +
-			"|         |                                          |                             | Namespace:                                                             | ANP:                                                             |                            |\n" +
-			"|         |                                          |                             |    kubernetes.io/metadata.name = network-policy-conformance-ravenclaw  |    pri=16 (example-anp-2): Allow (ineffective rules: Deny, Pass) |                            |\n" +
-			"|         |                                          |                             | Pod:                                                                   |    pri=20 (example-anp): Allow (ineffective rules: Deny, Pass)   |                            |\n" +
-			"|         |                                          |                             |    all                                                                 | BANP:                                                            |                            |\n" +
-			"|         |                                          |                             |                                                                        |    Allow                                                         |                            |\n" +
-			"+         +                                          +                             +------------------------------------------------------------------------+------------------------------------------------------------------+----------------------------+\n" +
-			"|         |                                          |                             | Namespace:                                                             | ANP:                                                             | port 80 on protocol TCP    |\n" +
-			"|         |                                          |                             |    kubernetes.io/metadata.name = network-policy-conformance-slytherin  |    pri=16 (example-anp-2): Deny (ineffective rules: Pass)        | port 53 on protocol UDP    |\n" +
-			"|         |                                          |                             | Pod:                                                                   |    pri=20 (example-anp): Deny (ineffective rules: Pass)          | port 9003 on protocol SCTP |\n" +
-			"|         |                                          |                             |    all                                                                 | BANP:                                                            |                            |\n" +
-			"|         |                                          |                             |                                                                        |    Deny                                                          |                            |\n" +
-			"+         +                                          +                             +------------------------------------------------------------------------+------------------------------------------------------------------+----------------------------+\n" +
-			"|         |                                          |                             | Namespace:                                                             | ANP:                                                             | port 80 on protocol TCP    |\n" +
-			"|         |                                          |                             |    kubernetes.io/metadata.name = network-policy-conformance-hufflepuff |    pri=16 (example-anp-2): Allow                                 | port 5353 on protocol UDP  |\n" +
-			"|         |                                          |                             | Pod:                                                                   |    pri=20 (example-anp): Allow                                   | port 9003 on protocol SCTP |\n" +
-			"|         |                                          |                             |    all                                                                 | BANP:                                                            |                            |\n" +
-			"|         |                                          |                             |                                                                        |    Allow                                                         |                            |\n" +
-			"+---------+------------------------------------------+-----------------------------+------------------------------------------------------------------------+------------------------------------------------------------------+----------------------------+\n" +
-			"|         |                                          |                             |                                                                        |                                                                  |                            |\n" +
-			"+---------+------------------------------------------+-----------------------------+------------------------------------------------------------------------+------------------------------------------------------------------+----------------------------+\n" +
-			"| Egress  | Namespace:                               | [ANP] default/example-anp   | Namespace:                                                             | BANP:                                                            | all ports, all protocols   |\n" +
-			"|         |    kubernetes.io/metadata.name Exists [] | [ANP] default/example-anp-2 |    Not Same labels - Test1                                             |    Deny                                                          |                            |\n" +
-			"|         |                                          | [BANP] default/default      | Pod:                                                                   |                                                                  |                            |\n" +
-			"|         |                                          |                             |    all                                                                 |                                                                  |                            |\n" +
-			"+         +                                          +                             +------------------------------------------------------------------------+------------------------------------------------------------------+                            +\n" +
-			"|         |                                          |                             | Namespace:                                                             | BANP:                                                            |                            |\n" +
-			"|         |                                          |                             |    Same labels - Test                                                  |    Allow                                                         |                            |\n" +
-			"|         |                                          |                             | Pod:                                                                   |                                                                  |                            |\n" +
-			"|         |                                          |                             |    all                                                                 |                                                                  |                            |\n" +
-			"+         +                                          +                             +------------------------------------------------------------------------+------------------------------------------------------------------+                            +\n" +
-			"|         |                                          |                             | Namespace:                                                             | ANP:                                                             |                            |\n" +
-			"|         |                                          |                             |    kubernetes.io/metadata.name = network-policy-conformance-hufflepuff |    pri=16 (example-anp-2): Deny                                  |                            |\n" +
-			"|         |                                          |                             | Pod:                                                                   |    pri=20 (example-anp): Deny                                    |                            |\n" +
-			"|         |                                          |                             |    all                                                                 | BANP:                                                            |                            |\n" +
-			"|         |                                          |                             |                                                                        |    Deny                                                          |                            |\n" +
-			"+         +                                          +                             +------------------------------------------------------------------------+------------------------------------------------------------------+                            +\n" +
-			"|         |                                          |                             | Namespace:                                                             | ANP:                                                             |                            |\n" +
-			"|         |                                          |                             |    kubernetes.io/metadata.name = network-policy-conformance-ravenclaw  |    pri=16 (example-anp-2): Allow (ineffective rules: Deny, Pass) |                            |\n" +
-			"|         |                                          |                             | Pod:                                                                   |    pri=20 (example-anp): Allow (ineffective rules: Deny, Pass)   |                            |\n" +
-			"|         |                                          |                             |    all                                                                 |                                                                  |                            |\n" +
-			"+         +                                          +                             +------------------------------------------------------------------------+------------------------------------------------------------------+----------------------------+\n" +
-			"|         |                                          |                             | Namespace:                                                             | ANP:                                                             | port 80 on protocol TCP    |\n" +
-			"|         |                                          |                             |    kubernetes.io/metadata.name = network-policy-conformance-slytherin  |    pri=16 (example-anp-2): Deny (ineffective rules: Pass)        | port 53 on protocol UDP    |\n" +
-			"|         |                                          |                             | Pod:                                                                   |    pri=20 (example-anp): Deny (ineffective rules: Pass)          | port 9003 on protocol SCTP |\n" +
-			"|         |                                          |                             |    all                                                                 |                                                                  |                            |\n" +
-			"+         +                                          +                             +------------------------------------------------------------------------+------------------------------------------------------------------+                            +\n" +
-			"|         |                                          |                             | Namespace:                                                             | BANP:                                                            |                            |\n" +
-			"|         |                                          |                             |    kubernetes.io/metadata.name Exists []                               |    Deny                                                          |                            |\n" +
-			"|         |                                          |                             | Pod:                                                                   |                                                                  |                            |\n" +
-			"|         |                                          |                             |    all                                                                 |                                                                  |                            |\n" +
-			"+         +                                          +                             +------------------------------------------------------------------------+------------------------------------------------------------------+----------------------------+\n" +
-			"|         |                                          |                             | Namespace:                                                             | ANP:                                                             | port 8080 on protocol TCP  |\n" +
-			"|         |                                          |                             |    kubernetes.io/metadata.name = network-policy-conformance-hufflepuff |    pri=16 (example-anp-2): Allow                                 | port 5353 on protocol UDP  |\n" +
-			"|         |                                          |                             | Pod:                                                                   |    pri=20 (example-anp): Allow                                   | port 9003 on protocol SCTP |\n" +
-			"|         |                                          |                             |    all                                                                 | BANP:                                                            |                            |\n" +
-			"|         |                                          |                             |                                                                        |    Allow                                                         |                            |\n" +
-			"+---------+------------------------------------------+-----------------------------+------------------------------------------------------------------------+------------------------------------------------------------------+----------------------------+\n" +
-			""
-		policies := matcher.BuildV1AndV2NetPols(false, nil, examples.CoreGressRulesCombinedANB, examples.CoreGressRulesCombinedBANB)
+		expected := "+---------+------------------------------------------+-----------------------------+------------------------------------------------------------------------+---------------------------------------------------------------------------------------------------+----------------------------+\n" +
+			"|  TYPE   |                 SUBJECT                  |        SOURCE RULES         |                                  PEER                                  |                                              ACTION                                               |       PORT/PROTOCOL        |\n" +
+			"+---------+------------------------------------------+-----------------------------+------------------------------------------------------------------------+---------------------------------------------------------------------------------------------------+----------------------------+\n" +
+			"| Ingress | Namespace:                               | [ANP] default/example-anp   | Namespace:                                                             | ANP:                                                                                              | all ports, all protocols   |\n" +
+			"|         |    kubernetes.io/metadata.name Exists [] | [ANP] default/example-anp-2 |    kubernetes.io/metadata.name = network-policy-conformance-hufflepuff |    pri=16 (example-anp-2): Deny rule 'deny-from-hufflepuff-everything-else-2' (ingress[6])        |                            |\n" +
+			"|         |                                          | [BANP] default/default      | Pod:                                                                   |    pri=20 (example-anp): Deny rule 'deny-from-hufflepuff-everything-else' (ingress[6])            |                            |\n" +
+			"|         |                                          |                             |    all                                                                 | BANP:                                                                                             |                            |\n" +
+			"|         |                                          |                             |                                                                        |    Deny rule 'deny-from-hufflepuff-everything-else' (ingress[3])                                  |                            |\n" +
+			"+         +                                          +                             +------------------------------------------------------------------------+---------------------------------------------------------------------------------------------------+                            +\n" +
+			"|         |                                          |                             | Namespace:                                                             | ANP:                                                                                              |                            |\n" +
+			"|         |                                          |                             |    kubernetes.io/metadata.name = network-policy-conformance-ravenclaw  |    pri=16 (example-anp-2): Allow rule 'allow-from-ravenclaw-everything-2' (ingress[0])            |                            |\n" +
+			"|         |                                          |                             | Pod:                                                                   |       ineffective: Deny rule 'deny-from-ravenclaw-everything-2' (ingress[1])                      |                            |\n" +
+			"|         |                                          |                             |    all                                                                 |       ineffective: Pass rule 'pass-from-ravenclaw-everything-2' (ingress[2])                      |                            |\n" +
+			"|         |                                          |                             |                                                                        |    pri=20 (example-anp): Allow rule 'allow-from-ravenclaw-everything' (ingress[0])                |                            |\n" +
+			"|         |                                          |                             |                                                                        |       ineffective: Deny rule 'deny-from-ravenclaw-everything' (ingress[1])                        |                            |\n" +
+			"|         |                                          |                             |                                                                        |       ineffective: Pass rule 'pass-from-ravenclaw-everything' (ingress[2])                        |                            |\n" +
+			"|         |                                          |                             |                                                                        | BANP:                                                                                             |                            |\n" +
+			"|         |                                          |                             |                                                                        |    Allow rule 'allow-from-ravenclaw-everything' (ingress[0])                                      |                            |\n" +
+			"+         +                                          +                             +------------------------------------------------------------------------+---------------------------------------------------------------------------------------------------+----------------------------+\n" +
+			"|         |                                          |                             | Namespace:                                                             | ANP:                                                                                              | port 80 on protocol TCP    |\n" +
+			"|         |                                          |                             |    kubernetes.io/metadata.name = network-policy-conformance-slytherin  |    pri=16 (example-anp-2): Deny rule 'deny-from-slytherin-at-port-80-53-9003-2' (ingress[3])      | port 53 on protocol UDP    |\n" +
+			"|         |                                          |                             | Pod:                                                                   |       ineffective: Pass rule 'pass-from-slytherin-at-port-80-53-9003-2' (ingress[4])              | port 9003 on protocol SCTP |\n" +
+			"|         |                                          |                             |    all                                                                 |    pri=20 (example-anp): Deny rule 'deny-from-slytherin-at-port-80-53-9003' (ingress[3])          |                            |\n" +
+			"|         |                                          |                             |                                                                        |       ineffective: Pass rule 'pass-from-slytherin-at-port-80-53-9003' (ingress[4])                |                            |\n" +
+			"|         |                                          |                             |                                                                        | BANP:                                                                                             |                            |\n" +
+			"|         |                                          |                             |                                                                        |    Deny rule 'deny-from-slytherin-at-port-80-53-9003' (ingress[1])                                |                            |\n" +
+			"+         +                                          +                             +------------------------------------------------------------------------+---------------------------------------------------------------------------------------------------+----------------------------+\n" +
+			"|         |                                          |                             | Namespace:                                                             | ANP:                                                                                              | port 80 on protocol TCP    |\n" +
+			"|         |                                          |                             |    kubernetes.io/metadata.name = network-policy-conformance-hufflepuff |    pri=16 (example-anp-2): Allow rule 'allow-from-hufflepuff-at-port-80-5353-9003-2' (ingress[5]) | port 5353 on protocol UDP  |\n" +
+			"|         |                                          |                             | Pod:                                                                   |    pri=20 (example-anp): Allow rule 'allow-from-hufflepuff-at-port-80-5353-9003' (ingress[5])     | port 9003 on protocol SCTP |\n" +
+			"|         |                                          |                             |    all                                                                 | BANP:                                                                                             |                            |\n" +
+			"|         |                                          |                             |                                                                        |    Allow rule 'allow-from-hufflepuff-at-port-80-5353-9003' (ingress[2])                           |                            |\n" +
+			"+---------+------------------------------------------+-----------------------------+------------------------------------------------------------------------+---------------------------------------------------------------------------------------------------+----------------------------+\n" +
+			"|         |                                          |                             |                                                                        |                                                                                                   |                            |\n" +
+			"+---------+------------------------------------------+-----------------------------+------------------------------------------------------------------------+---------------------------------------------------------------------------------------------------+----------------------------+\n" +
+			"| Egress  | Namespace:                               | [ANP] default/example-anp   | Namespace:                                                             | ANP:                                                                                              | all ports, all protocols   |\n" +
+			"|         |    kubernetes.io/metadata.name Exists [] | [ANP] default/example-anp-2 |    kubernetes.io/metadata.name = network-policy-conformance-hufflepuff |    pri=16 (example-anp-2): Deny rule 'deny-to-hufflepuff-everything-else-2' (egress[6])           |                            |\n" +
+			"|         |                                          | [BANP] default/default      | Pod:                                                                   |    pri=20 (example-anp): Deny rule 'deny-to-hufflepuff-everything-else' (egress[6])               |                            |\n" +
+			"|         |                                          |                             |    all                                                                 | BANP:                                                                                             |                            |\n" +
+			"|         |                                          |                             |                                                                        |    Deny rule 'deny-to-hufflepuff-everything-else' (egress[4])                                     |                            |\n" +
+			"+         +                                          +                             +------------------------------------------------------------------------+---------------------------------------------------------------------------------------------------+                            +\n" +
+			"|         |                                          |                             | Namespace:                                                             | ANP:                                                                                              |                            |\n" +
+			"|         |                                          |                             |    kubernetes.io/metadata.name = network-policy-conformance-ravenclaw  |    pri=16 (example-anp-2): Allow rule 'allow-to-ravenclaw-everything-2' (egress[0])               |                            |\n" +
+			"|         |                                          |                             | Pod:                                                                   |       ineffective: Deny rule 'deny-to-ravenclaw-everything-2' (egress[1])                         |                            |\n" +
+			"|         |                                          |                             |    all                                                                 |       ineffective: Pass rule 'pass-to-ravenclaw-everything-2' (egress[2])                         |                            |\n" +
+			"|         |                                          |                             |                                                                        |    pri=20 (example-anp): Allow rule 'allow-to-ravenclaw-everything' (egress[0])                   |                            |\n" +
+			"|         |                                          |                             |                                                                        |       ineffective: Deny rule 'deny-to-ravenclaw-everything' (egress[1])                           |                            |\n" +
+			"|         |                                          |                             |                                                                        |       ineffective: Pass rule 'pass-to-ravenclaw-everything' (egress[2])                           |                            |\n" +
+			"+         +                                          +                             +------------------------------------------------------------------------+---------------------------------------------------------------------------------------------------+                            +\n" +
+			"|         |                                          |                             | Namespace:                                                             | BANP:                                                                                             |                            |\n" +
+			"|         |                                          |                             |    kubernetes.io/metadata.name = ravenclaw                             |    Allow rule 'allow-to-ravenclaw-everything' (egress[0])                                         |                            |\n" +
+			"|         |                                          |                             | Pod:                                                                   |       ineffective: Deny rule 'deny-to-ravenclaw-everything' (egress[1])                           |                            |\n" +
+			"|         |                                          |                             |    all                                                                 |                                                                                                   |                            |\n" +
+			"+         +                                          +                             +------------------------------------------------------------------------+---------------------------------------------------------------------------------------------------+----------------------------+\n" +
+			"|         |                                          |                             | Namespace:                                                             | ANP:                                                                                              | port 80 on protocol TCP    |\n" +
+			"|         |                                          |                             |    kubernetes.io/metadata.name = network-policy-conformance-slytherin  |    pri=16 (example-anp-2): Deny rule 'deny-to-slytherin-at-ports-80-53-9003-2' (egress[3])        | port 53 on protocol UDP    |\n" +
+			"|         |                                          |                             | Pod:                                                                   |       ineffective: Pass rule 'pass-to-slytherin-at-port-80-53-9003-2' (egress[4])                 | port 9003 on protocol SCTP |\n" +
+			"|         |                                          |                             |    all                                                                 |    pri=20 (example-anp): Deny rule 'deny-to-slytherin-at-ports-80-53-9003' (egress[3])            |                            |\n" +
+			"|         |                                          |                             |                                                                        |       ineffective: Pass rule 'pass-to-slytherin-at-port-80-53-9003' (egress[4])                   |                            |\n" +
+			"+         +                                          +                             +------------------------------------------------------------------------+---------------------------------------------------------------------------------------------------+                            +\n" +
+			"|         |                                          |                             | Namespace:                                                             | BANP:                                                                                             |                            |\n" +
+			"|         |                                          |                             |    kubernetes.io/metadata.name Exists []                               |    Deny rule 'deny-to-slytherin-at-ports-80-53-9003' (egress[2])                                  |                            |\n" +
+			"|         |                                          |                             | Pod:                                                                   |                                                                                                   |                            |\n" +
+			"|         |                                          |                             |    all                                                                 |                                                                                                   |                            |\n" +
+			"+         +                                          +                             +------------------------------------------------------------------------+---------------------------------------------------------------------------------------------------+----------------------------+\n" +
+			"|         |                                          |                             | Namespace:                                                             | ANP:                                                                                              | port 8080 on protocol TCP  |\n" +
+			"|         |                                          |                             |    kubernetes.io/metadata.name = network-policy-conformance-hufflepuff |    pri=16 (example-anp-2): Allow rule 'allow-to-hufflepuff-at-ports-8080-5353-2' (egress[5])      | port 5353 on protocol UDP  |\n" +
+			"|         |                                          |                             | Pod:                                                                   |    pri=20 (example-anp): Allow rule 'allow-to-hufflepuff-at-ports-8080-5353' (egress[5])          | port 9003 on protocol SCTP |\n" +
+			"|         |                                          |                             |    all                                                                 | BANP:                                                                                             |                            |\n" +
+			"|         |                                          |                             |                                                                        |    Allow rule 'allow-to-hufflepuff-at-ports-8080-5353' (egress[3])                                |                            |\n" +
+			"+---------+------------------------------------------+-----------------------------+------------------------------------------------------------------------+---------------------------------------------------------------------------------------------------+----------------------------+\n"
+		policies, err := matcher.BuildV1AndV2NetPols(false, nil, examples.CoreGressRulesCombinedANB, examples.CoreGressRulesCombinedBANB)
+		require.NoError(t, err)
 		require.Equal(t, expected, policies.ExplainTable())
 	})
 }
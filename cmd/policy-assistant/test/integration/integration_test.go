@@ -124,7 +124,7 @@ func TestANPConnectivity(t *testing.T) {
 						Spec: v1alpha1.AdminNetworkPolicySpec{
 							Priority: 100,
 							Subject: v1alpha1.AdminNetworkPolicySubject{
-								Pods: &v1alpha1.NamespacedPodSubject{
+								Pods: &v1alpha1.NamespacedPod{
 									NamespaceSelector: metav1.LabelSelector{
 										MatchLabels: map[string]string{"ns": "x"},
 									},
@@ -136,13 +136,11 @@ func TestANPConnectivity(t *testing.T) {
 							Egress: []v1alpha1.AdminNetworkPolicyEgressRule{
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionDeny,
-									To: []v1alpha1.AdminNetworkPolicyPeer{
+									To: []v1alpha1.AdminNetworkPolicyEgressPeer{
 										{
-											Pods: &v1alpha1.NamespacedPodPeer{
-												Namespaces: v1alpha1.NamespacedPeer{
-													NamespaceSelector: &metav1.LabelSelector{
-														MatchLabels: map[string]string{"ns": "x"},
-													},
+											Pods: &v1alpha1.NamespacedPod{
+												NamespaceSelector: metav1.LabelSelector{
+													MatchLabels: map[string]string{"ns": "x"},
 												},
 												PodSelector: metav1.LabelSelector{
 													MatchLabels: map[string]string{"pod": "b"},
@@ -178,7 +176,7 @@ func TestANPConnectivity(t *testing.T) {
 						Spec: v1alpha1.AdminNetworkPolicySpec{
 							Priority: 100,
 							Subject: v1alpha1.AdminNetworkPolicySubject{
-								Pods: &v1alpha1.NamespacedPodSubject{
+								Pods: &v1alpha1.NamespacedPod{
 									NamespaceSelector: metav1.LabelSelector{
 										MatchLabels: map[string]string{"ns": "x"},
 									},
@@ -190,13 +188,11 @@ func TestANPConnectivity(t *testing.T) {
 							Ingress: []v1alpha1.AdminNetworkPolicyIngressRule{
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionDeny,
-									From: []v1alpha1.AdminNetworkPolicyPeer{
+									From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 										{
-											Pods: &v1alpha1.NamespacedPodPeer{
-												Namespaces: v1alpha1.NamespacedPeer{
-													NamespaceSelector: &metav1.LabelSelector{
-														MatchLabels: map[string]string{"ns": "x"},
-													},
+											Pods: &v1alpha1.NamespacedPod{
+												NamespaceSelector: metav1.LabelSelector{
+													MatchLabels: map[string]string{"ns": "x"},
 												},
 												PodSelector: metav1.LabelSelector{
 													MatchLabels: map[string]string{"pod": "b"},
@@ -232,7 +228,7 @@ func TestANPConnectivity(t *testing.T) {
 						Spec: v1alpha1.AdminNetworkPolicySpec{
 							Priority: 100,
 							Subject: v1alpha1.AdminNetworkPolicySubject{
-								Pods: &v1alpha1.NamespacedPodSubject{
+								Pods: &v1alpha1.NamespacedPod{
 									NamespaceSelector: metav1.LabelSelector{
 										MatchLabels: map[string]string{"ns": "x"},
 									},
@@ -244,13 +240,11 @@ func TestANPConnectivity(t *testing.T) {
 							Ingress: []v1alpha1.AdminNetworkPolicyIngressRule{
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionDeny,
-									From: []v1alpha1.AdminNetworkPolicyPeer{
+									From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 										{
-											Pods: &v1alpha1.NamespacedPodPeer{
-												Namespaces: v1alpha1.NamespacedPeer{
-													NamespaceSelector: &metav1.LabelSelector{
-														MatchLabels: map[string]string{"ns": "x"},
-													},
+											Pods: &v1alpha1.NamespacedPod{
+												NamespaceSelector: metav1.LabelSelector{
+													MatchLabels: map[string]string{"ns": "x"},
 												},
 												PodSelector: metav1.LabelSelector{
 													MatchLabels: map[string]string{"pod": "b"},
@@ -271,7 +265,7 @@ func TestANPConnectivity(t *testing.T) {
 			},
 		},
 		{
-			name:                   "ingress same labels port range",
+			name:                   "ingress same namespace port range",
 			defaultIngressBehavior: probe.ConnectivityAllowed,
 			defaultEgressBehavior:  probe.ConnectivityAllowed,
 			nonDefaultIngress: []flow{
@@ -289,7 +283,7 @@ func TestANPConnectivity(t *testing.T) {
 						Spec: v1alpha1.AdminNetworkPolicySpec{
 							Priority: 100,
 							Subject: v1alpha1.AdminNetworkPolicySubject{
-								Pods: &v1alpha1.NamespacedPodSubject{
+								Pods: &v1alpha1.NamespacedPod{
 									NamespaceSelector: metav1.LabelSelector{
 										MatchLabels: map[string]string{"ns": "x"},
 									},
@@ -310,11 +304,11 @@ func TestANPConnectivity(t *testing.T) {
 											},
 										},
 									}),
-									From: []v1alpha1.AdminNetworkPolicyPeer{
+									From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 										{
-											Pods: &v1alpha1.NamespacedPodPeer{
-												Namespaces: v1alpha1.NamespacedPeer{
-													SameLabels: []string{"ns"},
+											Pods: &v1alpha1.NamespacedPod{
+												NamespaceSelector: metav1.LabelSelector{
+													MatchLabels: map[string]string{"ns": "x"},
 												},
 												PodSelector: metav1.LabelSelector{},
 											},
@@ -328,7 +322,7 @@ func TestANPConnectivity(t *testing.T) {
 			},
 		},
 		{
-			name:                   "not same labels",
+			name:                   "other namespace",
 			defaultIngressBehavior: probe.ConnectivityAllowed,
 			defaultEgressBehavior:  probe.ConnectivityAllowed,
 			nonDefaultIngress: []flow{
@@ -342,7 +336,7 @@ func TestANPConnectivity(t *testing.T) {
 						Spec: v1alpha1.AdminNetworkPolicySpec{
 							Priority: 100,
 							Subject: v1alpha1.AdminNetworkPolicySubject{
-								Pods: &v1alpha1.NamespacedPodSubject{
+								Pods: &v1alpha1.NamespacedPod{
 									NamespaceSelector: metav1.LabelSelector{
 										MatchLabels: map[string]string{"ns": "x"},
 									},
@@ -354,10 +348,10 @@ func TestANPConnectivity(t *testing.T) {
 							Ingress: []v1alpha1.AdminNetworkPolicyIngressRule{
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionDeny,
-									From: []v1alpha1.AdminNetworkPolicyPeer{
+									From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 										{
-											Namespaces: &v1alpha1.NamespacedPeer{
-												NotSameLabels: []string{"ns"},
+											Namespaces: &metav1.LabelSelector{
+												MatchLabels: map[string]string{"ns": "y"},
 											},
 										},
 									},
@@ -390,7 +384,7 @@ func TestANPConnectivity(t *testing.T) {
 						Spec: v1alpha1.AdminNetworkPolicySpec{
 							Priority: 100,
 							Subject: v1alpha1.AdminNetworkPolicySubject{
-								Pods: &v1alpha1.NamespacedPodSubject{
+								Pods: &v1alpha1.NamespacedPod{
 									NamespaceSelector: metav1.LabelSelector{
 										MatchLabels: map[string]string{"ns": "x"},
 									},
@@ -402,13 +396,11 @@ func TestANPConnectivity(t *testing.T) {
 							Ingress: []v1alpha1.AdminNetworkPolicyIngressRule{
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionAllow,
-									From: []v1alpha1.AdminNetworkPolicyPeer{
+									From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 										{
-											Pods: &v1alpha1.NamespacedPodPeer{
-												Namespaces: v1alpha1.NamespacedPeer{
-													NamespaceSelector: &metav1.LabelSelector{
-														MatchLabels: map[string]string{"ns": "y"},
-													},
+											Pods: &v1alpha1.NamespacedPod{
+												NamespaceSelector: metav1.LabelSelector{
+													MatchLabels: map[string]string{"ns": "y"},
 												},
 												PodSelector: metav1.LabelSelector{
 													MatchLabels: map[string]string{"pod": "a"},
@@ -427,10 +419,10 @@ func TestANPConnectivity(t *testing.T) {
 								},
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionDeny,
-									From: []v1alpha1.AdminNetworkPolicyPeer{
+									From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 										{
-											Namespaces: &v1alpha1.NamespacedPeer{
-												NotSameLabels: []string{"ns"},
+											Namespaces: &metav1.LabelSelector{
+												MatchLabels: map[string]string{"ns": "y"},
 											},
 										},
 									},
@@ -464,7 +456,7 @@ func TestANPConnectivity(t *testing.T) {
 						Spec: v1alpha1.AdminNetworkPolicySpec{
 							Priority: 100,
 							Subject: v1alpha1.AdminNetworkPolicySubject{
-								Pods: &v1alpha1.NamespacedPodSubject{
+								Pods: &v1alpha1.NamespacedPod{
 									NamespaceSelector: metav1.LabelSelector{
 										MatchLabels: map[string]string{"ns": "x"},
 									},
@@ -476,10 +468,10 @@ func TestANPConnectivity(t *testing.T) {
 							Ingress: []v1alpha1.AdminNetworkPolicyIngressRule{
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionDeny,
-									From: []v1alpha1.AdminNetworkPolicyPeer{
+									From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 										{
-											Namespaces: &v1alpha1.NamespacedPeer{
-												NotSameLabels: []string{"ns"},
+											Namespaces: &metav1.LabelSelector{
+												MatchLabels: map[string]string{"ns": "y"},
 											},
 										},
 									},
@@ -494,13 +486,11 @@ func TestANPConnectivity(t *testing.T) {
 								},
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionAllow,
-									From: []v1alpha1.AdminNetworkPolicyPeer{
+									From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 										{
-											Pods: &v1alpha1.NamespacedPodPeer{
-												Namespaces: v1alpha1.NamespacedPeer{
-													NamespaceSelector: &metav1.LabelSelector{
-														MatchLabels: map[string]string{"ns": "y"},
-													},
+											Pods: &v1alpha1.NamespacedPod{
+												NamespaceSelector: metav1.LabelSelector{
+													MatchLabels: map[string]string{"ns": "y"},
 												},
 												PodSelector: metav1.LabelSelector{
 													MatchLabels: map[string]string{"pod": "a"},
@@ -539,11 +529,9 @@ func TestANPConnectivity(t *testing.T) {
 							Egress: []v1alpha1.AdminNetworkPolicyEgressRule{
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionDeny,
-									To: []v1alpha1.AdminNetworkPolicyPeer{
+									To: []v1alpha1.AdminNetworkPolicyEgressPeer{
 										{
-											Namespaces: &v1alpha1.NamespacedPeer{
-												NamespaceSelector: &metav1.LabelSelector{},
-											},
+											Namespaces: &metav1.LabelSelector{},
 										},
 									},
 									Ports: &([]v1alpha1.AdminNetworkPolicyPort{
@@ -585,11 +573,9 @@ func TestANPConnectivity(t *testing.T) {
 							Egress: []v1alpha1.AdminNetworkPolicyEgressRule{
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionAllow,
-									To: []v1alpha1.AdminNetworkPolicyPeer{
+									To: []v1alpha1.AdminNetworkPolicyEgressPeer{
 										{
-											Namespaces: &v1alpha1.NamespacedPeer{
-												NamespaceSelector: &metav1.LabelSelector{},
-											},
+											Namespaces: &metav1.LabelSelector{},
 										},
 									},
 									Ports: &([]v1alpha1.AdminNetworkPolicyPort{
@@ -621,11 +607,9 @@ func TestANPConnectivity(t *testing.T) {
 							Egress: []v1alpha1.AdminNetworkPolicyEgressRule{
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionDeny,
-									To: []v1alpha1.AdminNetworkPolicyPeer{
+									To: []v1alpha1.AdminNetworkPolicyEgressPeer{
 										{
-											Namespaces: &v1alpha1.NamespacedPeer{
-												NamespaceSelector: &metav1.LabelSelector{},
-											},
+											Namespaces: &metav1.LabelSelector{},
 										},
 									},
 									Ports: &([]v1alpha1.AdminNetworkPolicyPort{
@@ -667,11 +651,9 @@ func TestANPConnectivity(t *testing.T) {
 							Egress: []v1alpha1.AdminNetworkPolicyEgressRule{
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionAllow,
-									To: []v1alpha1.AdminNetworkPolicyPeer{
+									To: []v1alpha1.AdminNetworkPolicyEgressPeer{
 										{
-											Namespaces: &v1alpha1.NamespacedPeer{
-												NamespaceSelector: &metav1.LabelSelector{},
-											},
+											Namespaces: &metav1.LabelSelector{},
 										},
 									},
 									Ports: &([]v1alpha1.AdminNetworkPolicyPort{
@@ -703,11 +685,9 @@ func TestANPConnectivity(t *testing.T) {
 							Egress: []v1alpha1.AdminNetworkPolicyEgressRule{
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionDeny,
-									To: []v1alpha1.AdminNetworkPolicyPeer{
+									To: []v1alpha1.AdminNetworkPolicyEgressPeer{
 										{
-											Namespaces: &v1alpha1.NamespacedPeer{
-												NamespaceSelector: &metav1.LabelSelector{},
-											},
+											Namespaces: &metav1.LabelSelector{},
 										},
 									},
 									Ports: &([]v1alpha1.AdminNetworkPolicyPort{
@@ -759,7 +739,7 @@ func TestBANPConnectivity(t *testing.T) {
 				banp: &v1alpha1.BaselineAdminNetworkPolicy{
 					Spec: v1alpha1.BaselineAdminNetworkPolicySpec{
 						Subject: v1alpha1.AdminNetworkPolicySubject{
-							Pods: &v1alpha1.NamespacedPodSubject{
+							Pods: &v1alpha1.NamespacedPod{
 								NamespaceSelector: metav1.LabelSelector{
 									MatchLabels: map[string]string{"ns": "x"},
 								},
@@ -771,12 +751,10 @@ func TestBANPConnectivity(t *testing.T) {
 						Egress: []v1alpha1.BaselineAdminNetworkPolicyEgressRule{
 							{
 								Action: v1alpha1.BaselineAdminNetworkPolicyRuleActionDeny,
-								To: []v1alpha1.AdminNetworkPolicyPeer{
+								To: []v1alpha1.BaselineAdminNetworkPolicyEgressPeer{
 									{
-										Pods: &v1alpha1.NamespacedPodPeer{
-											Namespaces: v1alpha1.NamespacedPeer{
-												NamespaceSelector: &metav1.LabelSelector{},
-											},
+										Pods: &v1alpha1.NamespacedPod{
+											NamespaceSelector: metav1.LabelSelector{},
 											PodSelector: metav1.LabelSelector{
 												MatchLabels: map[string]string{"pod": "b"},
 											},
@@ -808,7 +786,7 @@ func TestBANPConnectivity(t *testing.T) {
 				banp: &v1alpha1.BaselineAdminNetworkPolicy{
 					Spec: v1alpha1.BaselineAdminNetworkPolicySpec{
 						Subject: v1alpha1.AdminNetworkPolicySubject{
-							Pods: &v1alpha1.NamespacedPodSubject{
+							Pods: &v1alpha1.NamespacedPod{
 								NamespaceSelector: metav1.LabelSelector{
 									MatchLabels: map[string]string{"ns": "x"},
 								},
@@ -820,12 +798,10 @@ func TestBANPConnectivity(t *testing.T) {
 						Ingress: []v1alpha1.BaselineAdminNetworkPolicyIngressRule{
 							{
 								Action: v1alpha1.BaselineAdminNetworkPolicyRuleActionDeny,
-								From: []v1alpha1.AdminNetworkPolicyPeer{
+								From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 									{
-										Pods: &v1alpha1.NamespacedPodPeer{
-											Namespaces: v1alpha1.NamespacedPeer{
-												NamespaceSelector: &metav1.LabelSelector{},
-											},
+										Pods: &v1alpha1.NamespacedPod{
+											NamespaceSelector: metav1.LabelSelector{},
 											PodSelector: metav1.LabelSelector{
 												MatchLabels: map[string]string{"pod": "b"},
 											},
@@ -858,13 +834,11 @@ func TestBANPConnectivity(t *testing.T) {
 						Ingress: []v1alpha1.BaselineAdminNetworkPolicyIngressRule{
 							{
 								Action: v1alpha1.BaselineAdminNetworkPolicyRuleActionAllow,
-								From: []v1alpha1.AdminNetworkPolicyPeer{
+								From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 									{
-										Pods: &v1alpha1.NamespacedPodPeer{
-											Namespaces: v1alpha1.NamespacedPeer{
-												NamespaceSelector: &metav1.LabelSelector{
-													MatchLabels: map[string]string{"ns": "y"},
-												},
+										Pods: &v1alpha1.NamespacedPod{
+											NamespaceSelector: metav1.LabelSelector{
+												MatchLabels: map[string]string{"ns": "y"},
 											},
 											PodSelector: metav1.LabelSelector{
 												MatchLabels: map[string]string{"pod": "b"},
@@ -875,11 +849,9 @@ func TestBANPConnectivity(t *testing.T) {
 							},
 							{
 								Action: v1alpha1.BaselineAdminNetworkPolicyRuleActionDeny,
-								From: []v1alpha1.AdminNetworkPolicyPeer{
+								From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 									{
-										Namespaces: &v1alpha1.NamespacedPeer{
-											NamespaceSelector: &metav1.LabelSelector{},
-										},
+										Namespaces: &metav1.LabelSelector{},
 									},
 								},
 							},
@@ -902,23 +874,19 @@ func TestBANPConnectivity(t *testing.T) {
 						Ingress: []v1alpha1.BaselineAdminNetworkPolicyIngressRule{
 							{
 								Action: v1alpha1.BaselineAdminNetworkPolicyRuleActionDeny,
-								From: []v1alpha1.AdminNetworkPolicyPeer{
+								From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 									{
-										Namespaces: &v1alpha1.NamespacedPeer{
-											NamespaceSelector: &metav1.LabelSelector{},
-										},
+										Namespaces: &metav1.LabelSelector{},
 									},
 								},
 							},
 							{
 								Action: v1alpha1.BaselineAdminNetworkPolicyRuleActionDeny,
-								From: []v1alpha1.AdminNetworkPolicyPeer{
+								From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 									{
-										Pods: &v1alpha1.NamespacedPodPeer{
-											Namespaces: v1alpha1.NamespacedPeer{
-												NamespaceSelector: &metav1.LabelSelector{
-													MatchLabels: map[string]string{"ns": "y"},
-												},
+										Pods: &v1alpha1.NamespacedPod{
+											NamespaceSelector: metav1.LabelSelector{
+												MatchLabels: map[string]string{"ns": "y"},
 											},
 											PodSelector: metav1.LabelSelector{
 												MatchLabels: map[string]string{"pod": "b"},
@@ -979,11 +947,9 @@ func TestANPWithNetPolV1(t *testing.T) {
 							Ingress: []v1alpha1.AdminNetworkPolicyIngressRule{
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionAllow,
-									From: []v1alpha1.AdminNetworkPolicyPeer{
+									From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 										{
-											Namespaces: &v1alpha1.NamespacedPeer{
-												NamespaceSelector: &metav1.LabelSelector{},
-											},
+											Namespaces: &metav1.LabelSelector{},
 										},
 									},
 									Ports: &([]v1alpha1.AdminNetworkPolicyPort{
@@ -1057,12 +1023,10 @@ func TestANPWithNetPolV1(t *testing.T) {
 							Ingress: []v1alpha1.AdminNetworkPolicyIngressRule{
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionAllow,
-									From: []v1alpha1.AdminNetworkPolicyPeer{
+									From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 										{
-											Namespaces: &v1alpha1.NamespacedPeer{
-												NamespaceSelector: &metav1.LabelSelector{
-													MatchLabels: map[string]string{"ns": "x"},
-												},
+											Namespaces: &metav1.LabelSelector{
+												MatchLabels: map[string]string{"ns": "x"},
 											},
 										},
 									},
@@ -1144,11 +1108,9 @@ func TestBANPWithNetPolV1(t *testing.T) {
 						Ingress: []v1alpha1.BaselineAdminNetworkPolicyIngressRule{
 							{
 								Action: v1alpha1.BaselineAdminNetworkPolicyRuleActionDeny,
-								From: []v1alpha1.AdminNetworkPolicyPeer{
+								From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 									{
-										Namespaces: &v1alpha1.NamespacedPeer{
-											NamespaceSelector: &metav1.LabelSelector{},
-										},
+										Namespaces: &metav1.LabelSelector{},
 									},
 								},
 							},
@@ -1179,7 +1141,7 @@ func TestANPWithBANP(t *testing.T) {
 						Spec: v1alpha1.AdminNetworkPolicySpec{
 							Priority: 100,
 							Subject: v1alpha1.AdminNetworkPolicySubject{
-								Pods: &v1alpha1.NamespacedPodSubject{
+								Pods: &v1alpha1.NamespacedPod{
 									NamespaceSelector: metav1.LabelSelector{
 										MatchLabels: map[string]string{"ns": "x"},
 									},
@@ -1191,10 +1153,10 @@ func TestANPWithBANP(t *testing.T) {
 							Ingress: []v1alpha1.AdminNetworkPolicyIngressRule{
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionAllow,
-									From: []v1alpha1.AdminNetworkPolicyPeer{
+									From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 										{
-											Namespaces: &v1alpha1.NamespacedPeer{
-												SameLabels: []string{"ns"},
+											Namespaces: &metav1.LabelSelector{
+												MatchLabels: map[string]string{"ns": "x"},
 											},
 										},
 									},
@@ -1219,11 +1181,9 @@ func TestANPWithBANP(t *testing.T) {
 						Ingress: []v1alpha1.BaselineAdminNetworkPolicyIngressRule{
 							{
 								Action: v1alpha1.BaselineAdminNetworkPolicyRuleActionDeny,
-								From: []v1alpha1.AdminNetworkPolicyPeer{
+								From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 									{
-										Namespaces: &v1alpha1.NamespacedPeer{
-											NamespaceSelector: &metav1.LabelSelector{},
-										},
+										Namespaces: &metav1.LabelSelector{},
 									},
 								},
 							},
@@ -1247,7 +1207,7 @@ func TestANPWithBANP(t *testing.T) {
 						Spec: v1alpha1.AdminNetworkPolicySpec{
 							Priority: 100,
 							Subject: v1alpha1.AdminNetworkPolicySubject{
-								Pods: &v1alpha1.NamespacedPodSubject{
+								Pods: &v1alpha1.NamespacedPod{
 									NamespaceSelector: metav1.LabelSelector{
 										MatchLabels: map[string]string{"ns": "x"},
 									},
@@ -1259,10 +1219,10 @@ func TestANPWithBANP(t *testing.T) {
 							Ingress: []v1alpha1.AdminNetworkPolicyIngressRule{
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionPass,
-									From: []v1alpha1.AdminNetworkPolicyPeer{
+									From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 										{
-											Namespaces: &v1alpha1.NamespacedPeer{
-												SameLabels: []string{"ns"},
+											Namespaces: &metav1.LabelSelector{
+												MatchLabels: map[string]string{"ns": "x"},
 											},
 										},
 									},
@@ -1287,11 +1247,9 @@ func TestANPWithBANP(t *testing.T) {
 							Ingress: []v1alpha1.AdminNetworkPolicyIngressRule{
 								{
 									Action: v1alpha1.AdminNetworkPolicyRuleActionAllow,
-									From: []v1alpha1.AdminNetworkPolicyPeer{
+									From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 										{
-											Namespaces: &v1alpha1.NamespacedPeer{
-												NamespaceSelector: &metav1.LabelSelector{},
-											},
+											Namespaces: &metav1.LabelSelector{},
 										},
 									},
 								},
@@ -1307,11 +1265,144 @@ func TestANPWithBANP(t *testing.T) {
 						Ingress: []v1alpha1.BaselineAdminNetworkPolicyIngressRule{
 							{
 								Action: v1alpha1.BaselineAdminNetworkPolicyRuleActionDeny,
-								From: []v1alpha1.AdminNetworkPolicyPeer{
+								From: []v1alpha1.AdminNetworkPolicyIngressPeer{
 									{
-										Namespaces: &v1alpha1.NamespacedPeer{
-											NamespaceSelector: &metav1.LabelSelector{},
+										Namespaces: &metav1.LabelSelector{},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	runConnectivityTests(t, tests...)
+}
+
+func TestAdminEgressToNodes(t *testing.T) {
+	resources := getResources(t, []string{"x", "y"}, []string{"a"}, []int{80}, []v1.Protocol{v1.ProtocolTCP})
+	resources.Nodes = []*probe.Node{
+		{
+			Name:   "cp",
+			Labels: map[string]string{"node-role.kubernetes.io/control-plane": ""},
+			IP:     "172.18.0.2",
+			Ports:  []*probe.ServedPort{{Port: 10250, Protocol: v1.ProtocolTCP}},
+		},
+		{
+			Name:   "worker",
+			Labels: map[string]string{},
+			IP:     "172.18.0.3",
+			Ports:  []*probe.ServedPort{{Port: 10250, Protocol: v1.ProtocolTCP}},
+		},
+	}
+
+	tests := []connectivityTest{
+		{
+			name:                   "ANP deny to control plane kubelet",
+			defaultIngressBehavior: probe.ConnectivityAllowed,
+			defaultEgressBehavior:  probe.ConnectivityAllowed,
+			nonDefaultEgress: []flow{
+				{"x/a", "node:cp", 10250, v1.ProtocolTCP},
+				{"y/a", "node:cp", 10250, v1.ProtocolTCP},
+			},
+			args: args{
+				resources: resources,
+				anps: []*v1alpha1.AdminNetworkPolicy{
+					{
+						Spec: v1alpha1.AdminNetworkPolicySpec{
+							Priority: 10,
+							Subject: v1alpha1.AdminNetworkPolicySubject{
+								Namespaces: &metav1.LabelSelector{},
+							},
+							Egress: []v1alpha1.AdminNetworkPolicyEgressRule{
+								{
+									Action: v1alpha1.AdminNetworkPolicyRuleActionDeny,
+									To: []v1alpha1.AdminNetworkPolicyEgressPeer{
+										{
+											Nodes: &metav1.LabelSelector{
+												MatchExpressions: []metav1.LabelSelectorRequirement{
+													{
+														Key:      "node-role.kubernetes.io/control-plane",
+														Operator: metav1.LabelSelectorOpExists,
+													},
+												},
+											},
+										},
+									},
+									Ports: &([]v1alpha1.AdminNetworkPolicyPort{
+										{
+											PortNumber: &v1alpha1.Port{
+												Protocol: v1.ProtocolTCP,
+												Port:     10250,
+											},
 										},
+									}),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:                   "ANP namespaces peer does not match nodes",
+			defaultIngressBehavior: probe.ConnectivityAllowed,
+			defaultEgressBehavior:  probe.ConnectivityAllowed,
+			nonDefaultEgress: []flow{
+				{"x/a", "x/a", 80, v1.ProtocolTCP},
+				{"x/a", "y/a", 80, v1.ProtocolTCP},
+				{"y/a", "x/a", 80, v1.ProtocolTCP},
+				{"y/a", "y/a", 80, v1.ProtocolTCP},
+			},
+			args: args{
+				resources: resources,
+				anps: []*v1alpha1.AdminNetworkPolicy{
+					{
+						Spec: v1alpha1.AdminNetworkPolicySpec{
+							Priority: 10,
+							Subject: v1alpha1.AdminNetworkPolicySubject{
+								Namespaces: &metav1.LabelSelector{},
+							},
+							Egress: []v1alpha1.AdminNetworkPolicyEgressRule{
+								{
+									Action: v1alpha1.AdminNetworkPolicyRuleActionDeny,
+									To: []v1alpha1.AdminNetworkPolicyEgressPeer{
+										{
+											Namespaces: &metav1.LabelSelector{},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:                   "BANP deny to all nodes",
+			defaultIngressBehavior: probe.ConnectivityAllowed,
+			defaultEgressBehavior:  probe.ConnectivityAllowed,
+			nonDefaultEgress: []flow{
+				{"x/a", "node:cp", 10250, v1.ProtocolTCP},
+				{"x/a", "node:worker", 10250, v1.ProtocolTCP},
+				{"y/a", "node:cp", 10250, v1.ProtocolTCP},
+				{"y/a", "node:worker", 10250, v1.ProtocolTCP},
+			},
+			args: args{
+				resources: resources,
+				banp: &v1alpha1.BaselineAdminNetworkPolicy{
+					Spec: v1alpha1.BaselineAdminNetworkPolicySpec{
+						Subject: v1alpha1.AdminNetworkPolicySubject{
+							Namespaces: &metav1.LabelSelector{},
+						},
+						Egress: []v1alpha1.BaselineAdminNetworkPolicyEgressRule{
+							{
+								Action: v1alpha1.BaselineAdminNetworkPolicyRuleActionDeny,
+								To: []v1alpha1.BaselineAdminNetworkPolicyEgressPeer{
+									{
+										Nodes: &metav1.LabelSelector{},
 									},
 								},
 							},
@@ -1351,7 +1442,8 @@ func runConnectivityTests(t *testing.T, tests ...connectivityTest) {
 				}
 			}
 
-			parsedPolicy := matcher.BuildV1AndV2NetPols(false, tt.args.netpols, tt.args.anps, tt.args.banp)
+			parsedPolicy, err := matcher.BuildV1AndV2NetPols(false, tt.args.netpols, tt.args.anps, tt.args.banp)
+			require.NoError(t, err)
 			jobBuilder := &probe.JobBuilder{TimeoutSeconds: 3}
 			simRunner := probe.NewSimulatedRunner(parsedPolicy, jobBuilder)
 			simTable := simRunner.RunProbeForConfig(generator.ProbeAllAvailable, tt.args.resources)
@@ -1381,7 +1473,7 @@ func runConnectivityTests(t *testing.T, tests ...connectivityTest) {
 
 func getResources(t *testing.T, namespaces, podNames []string, ports []int, protocols []v1.Protocol) *probe.Resources {
 	kubernetes := kube.NewMockKubernetes(1.0)
-	resources, err := probe.NewDefaultResources(kubernetes, namespaces, podNames, ports, protocols, []string{}, 5, false, "registry.k8s.io")
+	resources, err := probe.NewDefaultResources(kubernetes, namespaces, podNames, ports, protocols, nil, 5, false, "registry.k8s.io")
 	require.Nil(t, err, "failed to create resources")
 	return resources
 }
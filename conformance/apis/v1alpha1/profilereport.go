@@ -16,6 +16,10 @@ limitations under the License.
 
 package v1alpha1
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // ProfileReport is the generated report for the test results of a specific
 // named conformance profile.
 type ProfileReport struct {
@@ -67,4 +71,57 @@ type Status struct {
 	// FailedTests indicates which tests were failing during the execution of
 	// test suite.
 	FailedTests []string `json:"failedTests,omitempty"`
+
+	// Tests includes the results of each test which was run or skipped,
+	// sorted by name, as evidence for the statistics.
+	Tests []TestReport `json:"tests,omitempty"`
+}
+
+// TestReport is the result of a single conformance test.
+type TestReport struct {
+	// Name is the short name of the test.
+	Name string `json:"name"`
+
+	// Result indicates whether the test passed, failed or was skipped.
+	Result TestResult `json:"result"`
+
+	// Duration is how long the test took to run, including applying and
+	// cleaning up its resources.
+	Duration metav1.Duration `json:"duration"`
+
+	// Failures lists why the test failed. Failed connectivity checks are
+	// listed with the subtest which made them and what went wrong; if the
+	// test failed without a failed connectivity check, a single failure
+	// refers to the test log.
+	Failures []TestFailure `json:"failures,omitempty"`
+
+	// Convergence summarizes the connectivity checks of the test and of its
+	// subtests; those of the profile's support level add them all up. It is
+	// unset if the test made no connectivity checks.
+	Convergence *ConvergenceStatistics `json:"convergence,omitempty"`
+}
+
+// TestResult is the outcome of a single conformance test.
+type TestResult string
+
+var (
+	// TestPassed indicates that the test ran and all its checks passed.
+	TestPassed TestResult = "passed"
+
+	// TestFailed indicates that one or more checks of the test failed.
+	TestFailed TestResult = "failed"
+
+	// TestSkipped indicates that the test was explicitly disabled in the test
+	// suite.
+	TestSkipped TestResult = "skipped"
+)
+
+// TestFailure is a failure of a conformance test.
+type TestFailure struct {
+	// Subtest is the name of the failed subtest, relative to the test, or
+	// empty if the failure was in the test itself.
+	Subtest string `json:"subtest,omitempty"`
+
+	// Message describes the failure.
+	Message string `json:"message,omitempty"`
 }
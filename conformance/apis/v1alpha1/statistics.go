@@ -16,6 +16,10 @@ limitations under the License.
 
 package v1alpha1
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // Statistics includes numerical summaries of the number of conformance tests
 // that passed, failed or were intentionally skipped.
 type Statistics struct {
@@ -29,4 +33,28 @@ type Statistics struct {
 
 	// Failed indicates how many tests were unsuccessful.
 	Failed uint32
+
+	// Convergence summarizes how long the connectivity checks of the tests
+	// took to match expectations once their policies were applied. It is
+	// unset if the tests made no connectivity checks.
+	Convergence *ConvergenceStatistics `json:",omitempty"`
+}
+
+// ConvergenceStatistics summarizes connectivity checks. Implementations may
+// program policies asynchronously, so a check retries until the connectivity
+// matches expectations or the policy convergence timeout expires.
+type ConvergenceStatistics struct {
+	// Checks indicates how many connectivity checks were made.
+	Checks uint32
+
+	// Converged indicates how many connectivity checks matched expectations
+	// before the policy convergence timeout expired.
+	Converged uint32
+
+	// MaxAttempts is the most connection attempts made by a single check.
+	MaxAttempts uint32
+
+	// MaxDuration is the longest time a single check took, from its first
+	// connection attempt until it matched expectations or gave up.
+	MaxDuration metav1.Duration
 }
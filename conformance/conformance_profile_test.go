@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
 	confv1a1 "sigs.k8s.io/network-policy-api/conformance/apis/v1alpha1"
 	"sigs.k8s.io/network-policy-api/conformance/tests"
+	confconfig "sigs.k8s.io/network-policy-api/conformance/utils/config"
 	"sigs.k8s.io/network-policy-api/conformance/utils/flags"
 	"sigs.k8s.io/network-policy-api/conformance/utils/suite"
 )
@@ -111,6 +112,7 @@ func testConformance(t *testing.T) {
 				SupportedFeatures:          supportedFeatures,
 				ExemptFeatures:             exemptFeatures,
 				EnableAllSupportedFeatures: *flags.EnableAllSupportedFeatures,
+				TimeoutConfig:              confconfig.TimeoutConfig{PolicyConvergence: *flags.PolicyConvergenceTimeout},
 			},
 			Implementation:      *implementation,
 			ConformanceProfiles: conformanceProfiles,
@@ -125,10 +127,12 @@ func testConformance(t *testing.T) {
 	if err != nil {
 		t.Fatalf("error generating conformance profile report: %v", err)
 	}
-	writeReport(t.Logf, *report, *flags.ReportOutput)
+	if err = writeReport(t.Logf, *report, *flags.ReportOutput, *flags.JUnitOutput); err != nil {
+		t.Fatalf("error writing conformance profile report: %v", err)
+	}
 }
 
-func writeReport(logf func(string, ...any), report confv1a1.ConformanceReport, output string) error {
+func writeReport(logf func(string, ...any), report confv1a1.ConformanceReport, output, junitOutput string) error {
 	rawReport, err := yaml.Marshal(report)
 	if err != nil {
 		return err
@@ -141,5 +145,15 @@ func writeReport(logf func(string, ...any), report confv1a1.ConformanceReport, o
 	}
 	logf("Conformance report:\n%s", string(rawReport))
 
+	if junitOutput != "" {
+		rawJUnit, err := suite.MarshalJUnit(report)
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(junitOutput, rawJUnit, 0600); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
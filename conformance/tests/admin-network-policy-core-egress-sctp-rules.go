@@ -26,7 +26,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
-	"sigs.k8s.io/network-policy-api/conformance/utils/kubernetes"
 	"sigs.k8s.io/network-policy-api/conformance/utils/suite"
 )
 
@@ -59,12 +58,12 @@ var AdminNetworkPolicyEgressSCTP = suite.ConformanceTest{
 			// luna-lovegood-0 is our client pod in ravenclaw namespace
 			// ensure egress is ALLOWED to gryffindor from ravenclaw
 			// egressRule at index0 will take precedence over egressRule at index1; thus ALLOW takes precedence over DENY since rules are ordered
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "sctp",
-				serverPod.Status.PodIP, int32(9003), s.TimeoutConfig.RequestTimeout, true)
+			success := s.PokeServer(t, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "sctp",
+				serverPod.Status.PodIP, int32(9003), true)
 			assert.True(t, success)
 			// luna-lovegood-1 is our client pod in ravenclaw namespace
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "sctp",
-				serverPod.Status.PodIP, int32(9005), s.TimeoutConfig.RequestTimeout, true)
+			success = s.PokeServer(t, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "sctp",
+				serverPod.Status.PodIP, int32(9005), true)
 			assert.True(t, success)
 		})
 
@@ -81,13 +80,13 @@ var AdminNetworkPolicyEgressSCTP = suite.ConformanceTest{
 			require.NoErrorf(t, err, "unable to fetch the server pod")
 			// luna-lovegood-0 is our client pod in ravenclaw namespace
 			// ensure egress is ALLOWED to hufflepuff from ravenclaw at port 9003; egressRule at index5 should take effect
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "sctp",
-				serverPod.Status.PodIP, int32(9003), s.TimeoutConfig.RequestTimeout, true)
+			success := s.PokeServer(t, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "sctp",
+				serverPod.Status.PodIP, int32(9003), true)
 			assert.True(t, success)
 			// luna-lovegood-1 is our client pod in ravenclaw namespace
 			// ensure egress is DENIED to hufflepuff from ravenclaw for rest of the traffic; egressRule at index6 should take effect
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "sctp",
-				serverPod.Status.PodIP, int32(9005), s.TimeoutConfig.RequestTimeout, false)
+			success = s.PokeServer(t, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "sctp",
+				serverPod.Status.PodIP, int32(9005), false)
 			assert.True(t, success)
 		})
 
@@ -117,12 +116,12 @@ var AdminNetworkPolicyEgressSCTP = suite.ConformanceTest{
 			// luna-lovegood-0 is our client pod in gryffindor namespace
 			// ensure egress is DENIED to gryffindor from ravenclaw
 			// egressRule at index0 will take precedence over egressRule at index1; thus DENY takes precedence over ALLOW since rules are ordered
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "sctp",
-				serverPod.Status.PodIP, int32(9003), s.TimeoutConfig.RequestTimeout, false)
+			success := s.PokeServer(t, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "sctp",
+				serverPod.Status.PodIP, int32(9003), false)
 			assert.True(t, success)
 			// luna-lovegood-1 is our client pod in ravenclaw namespace
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "sctp",
-				serverPod.Status.PodIP, int32(9005), s.TimeoutConfig.RequestTimeout, false)
+			success = s.PokeServer(t, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "sctp",
+				serverPod.Status.PodIP, int32(9005), false)
 			assert.True(t, success)
 		})
 
@@ -139,13 +138,13 @@ var AdminNetworkPolicyEgressSCTP = suite.ConformanceTest{
 			require.NoErrorf(t, err, "unable to fetch the server pod")
 			// luna-lovegood-0 is our client pod in ravenclaw namespace
 			// ensure egress to slytherin is DENIED from ravenclaw at port 9003; egressRule at index3 should take effect
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "sctp",
-				serverPod.Status.PodIP, int32(9003), s.TimeoutConfig.RequestTimeout, false)
+			success := s.PokeServer(t, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "sctp",
+				serverPod.Status.PodIP, int32(9003), false)
 			assert.True(t, success)
 			// luna-lovegood-1 is our client pod in ravenclaw namespace
 			// ensure egress to slytherin is ALLOWED from ravenclaw for rest of the traffic; matches no rules hence allowed
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "sctp",
-				serverPod.Status.PodIP, int32(9005), s.TimeoutConfig.RequestTimeout, true)
+			success = s.PokeServer(t, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "sctp",
+				serverPod.Status.PodIP, int32(9005), true)
 			assert.True(t, success)
 		})
 
@@ -175,12 +174,12 @@ var AdminNetworkPolicyEgressSCTP = suite.ConformanceTest{
 			// luna-lovegood-0 is our client pod in ravenclaw namespace
 			// ensure egress is PASSED from gryffindor to ravenclaw
 			// egressRule at index0 will take precedence over egressRule at index1&index2; thus PASS takes precedence over ALLOW/DENY since rules are ordered
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "sctp",
-				serverPod.Status.PodIP, int32(9003), s.TimeoutConfig.RequestTimeout, true)
+			success := s.PokeServer(t, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "sctp",
+				serverPod.Status.PodIP, int32(9003), true)
 			assert.True(t, success)
 			// luna-lovegood-1 is our client pod in ravenclaw namespace
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "sctp",
-				serverPod.Status.PodIP, int32(9005), s.TimeoutConfig.RequestTimeout, true)
+			success = s.PokeServer(t, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "sctp",
+				serverPod.Status.PodIP, int32(9005), true)
 			assert.True(t, success)
 		})
 
@@ -209,13 +208,13 @@ var AdminNetworkPolicyEgressSCTP = suite.ConformanceTest{
 			require.NoErrorf(t, err, "unable to patch the admin network policy")
 			// luna-lovegood-0 is our client pod in ravenclaw namespace
 			// ensure egress to slytherin is PASSED from ravenclaw at port 9003; egressRule at index3 should take effect
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "sctp",
-				serverPod.Status.PodIP, int32(9003), s.TimeoutConfig.RequestTimeout, true)
+			success := s.PokeServer(t, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "sctp",
+				serverPod.Status.PodIP, int32(9003), true)
 			assert.True(t, success)
 			// luna-lovegood-1 is our client pod in ravenclaw namespace
 			// ensure egress to slytherin is ALLOWED from ravenclaw for rest of the traffic; matches no rules hence allowed
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "sctp",
-				serverPod.Status.PodIP, int32(9005), s.TimeoutConfig.RequestTimeout, true)
+			success = s.PokeServer(t, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "sctp",
+				serverPod.Status.PodIP, int32(9005), true)
 			assert.True(t, success)
 		})
 	},
@@ -26,7 +26,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
-	"sigs.k8s.io/network-policy-api/conformance/utils/kubernetes"
 	"sigs.k8s.io/network-policy-api/conformance/utils/suite"
 )
 
@@ -59,12 +58,12 @@ var AdminNetworkPolicyEgressUDP = suite.ConformanceTest{
 			// cedric-diggory-0 is our client pod in hufflepuff namespace
 			// ensure egress is ALLOWED to ravenclaw from hufflepuff
 			// egressRule at index0 will take precedence over egressRule at index1; thus ALLOW takes precedence over DENY since rules are ordered
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-hufflepuff", "cedric-diggory-0", "udp",
-				serverPod.Status.PodIP, int32(53), s.TimeoutConfig.RequestTimeout, true)
+			success := s.PokeServer(t, "network-policy-conformance-hufflepuff", "cedric-diggory-0", "udp",
+				serverPod.Status.PodIP, int32(53), true)
 			assert.True(t, success)
 			// cedric-diggory-1 is our client pod in hufflepuff namespace
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-hufflepuff", "cedric-diggory-1", "udp",
-				serverPod.Status.PodIP, int32(5353), s.TimeoutConfig.RequestTimeout, true)
+			success = s.PokeServer(t, "network-policy-conformance-hufflepuff", "cedric-diggory-1", "udp",
+				serverPod.Status.PodIP, int32(5353), true)
 			assert.True(t, success)
 		})
 
@@ -81,13 +80,13 @@ var AdminNetworkPolicyEgressUDP = suite.ConformanceTest{
 			require.NoErrorf(t, err, "unable to fetch the server pod")
 			// cedric-diggory-0 is our client pod in hufflepuff namespace
 			// ensure egress is ALLOWED to gryffindor from hufflepuff at port 53; egressRule at index5
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-hufflepuff", "cedric-diggory-0", "udp",
-				serverPod.Status.PodIP, int32(53), s.TimeoutConfig.RequestTimeout, true)
+			success := s.PokeServer(t, "network-policy-conformance-hufflepuff", "cedric-diggory-0", "udp",
+				serverPod.Status.PodIP, int32(53), true)
 			assert.True(t, success)
 			// cedric-diggory-1 is our client pod in hufflepuff namespace
 			// ensure egress is DENIED to gryffindor from hufflepuff for rest of the traffic; egressRule at index6
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-hufflepuff", "cedric-diggory-1", "udp",
-				serverPod.Status.PodIP, int32(5353), s.TimeoutConfig.RequestTimeout, false)
+			success = s.PokeServer(t, "network-policy-conformance-hufflepuff", "cedric-diggory-1", "udp",
+				serverPod.Status.PodIP, int32(5353), false)
 			assert.True(t, success)
 		})
 
@@ -117,12 +116,12 @@ var AdminNetworkPolicyEgressUDP = suite.ConformanceTest{
 			// cedric-diggory-0 is our client pod in hufflepuff namespace
 			// ensure egress is DENIED to ravenclaw to hufflepuff
 			// egressRule at index0 will take precedence over egressRule at index1; thus DENY takes precedence over ALLOW since rules are ordered
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-hufflepuff", "cedric-diggory-0", "udp",
-				serverPod.Status.PodIP, int32(53), s.TimeoutConfig.RequestTimeout, false)
+			success := s.PokeServer(t, "network-policy-conformance-hufflepuff", "cedric-diggory-0", "udp",
+				serverPod.Status.PodIP, int32(53), false)
 			assert.True(t, success)
 			// cedric-diggory-1 is our client pod in hufflepuff namespace
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-hufflepuff", "cedric-diggory-1", "udp",
-				serverPod.Status.PodIP, int32(5353), s.TimeoutConfig.RequestTimeout, false)
+			success = s.PokeServer(t, "network-policy-conformance-hufflepuff", "cedric-diggory-1", "udp",
+				serverPod.Status.PodIP, int32(5353), false)
 			assert.True(t, success)
 		})
 
@@ -139,13 +138,13 @@ var AdminNetworkPolicyEgressUDP = suite.ConformanceTest{
 			require.NoErrorf(t, err, "unable to fetch the server pod")
 			// cedric-diggory-0 is our client pod in hufflepuff namespace
 			// ensure egress to slytherin is DENIED from hufflepuff at port 80; egressRule at index3 should take effect
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-hufflepuff", "cedric-diggory-0", "udp",
-				serverPod.Status.PodIP, int32(5353), s.TimeoutConfig.RequestTimeout, false)
+			success := s.PokeServer(t, "network-policy-conformance-hufflepuff", "cedric-diggory-0", "udp",
+				serverPod.Status.PodIP, int32(5353), false)
 			assert.True(t, success)
 			// cedric-diggory-0 is our client pod in hufflepuff namespace
 			// ensure egress to slytherin is ALLOWED from hufflepuff for rest of the traffic; matches no rules hence allowed
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-hufflepuff", "cedric-diggory-1", "udp",
-				serverPod.Status.PodIP, int32(53), s.TimeoutConfig.RequestTimeout, true)
+			success = s.PokeServer(t, "network-policy-conformance-hufflepuff", "cedric-diggory-1", "udp",
+				serverPod.Status.PodIP, int32(53), true)
 			assert.True(t, success)
 		})
 
@@ -175,12 +174,12 @@ var AdminNetworkPolicyEgressUDP = suite.ConformanceTest{
 			// cedric-diggory-0 is our client pod in hufflepuff namespace
 			// ensure egress is PASSED to ravenclaw from hufflepuff
 			// egressRule at index0 will take precedence over egressRule at index1&index2; thus PASS takes precedence over ALLOW/DENY since rules are ordered
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-hufflepuff", "cedric-diggory-0", "udp",
-				serverPod.Status.PodIP, int32(5353), s.TimeoutConfig.RequestTimeout, true)
+			success := s.PokeServer(t, "network-policy-conformance-hufflepuff", "cedric-diggory-0", "udp",
+				serverPod.Status.PodIP, int32(5353), true)
 			assert.True(t, success)
 			// cedric-diggory-1 is our client pod in hufflepuff namespace
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-hufflepuff", "cedric-diggory-1", "udp",
-				serverPod.Status.PodIP, int32(53), s.TimeoutConfig.RequestTimeout, true)
+			success = s.PokeServer(t, "network-policy-conformance-hufflepuff", "cedric-diggory-1", "udp",
+				serverPod.Status.PodIP, int32(53), true)
 			assert.True(t, success)
 		})
 
@@ -209,13 +208,13 @@ var AdminNetworkPolicyEgressUDP = suite.ConformanceTest{
 			require.NoErrorf(t, err, "unable to patch the admin network policy")
 			// cedric-diggory-0 is our client pod in hufflepuff namespace
 			// ensure egress to slytherin is PASSED from hufflepuff at port 5353; egressRule at index3 should take effect
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-hufflepuff", "cedric-diggory-0", "udp",
-				serverPod.Status.PodIP, int32(5353), s.TimeoutConfig.RequestTimeout, true)
+			success := s.PokeServer(t, "network-policy-conformance-hufflepuff", "cedric-diggory-0", "udp",
+				serverPod.Status.PodIP, int32(5353), true)
 			assert.True(t, success)
 			// cedric-diggory-1 is our client pod in hufflepuff namespace
 			// ensure egress to slytherin is ALLOWED from hufflepuff for rest of the traffic; matches no rules hence allowed
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-hufflepuff", "cedric-diggory-1", "udp",
-				serverPod.Status.PodIP, int32(53), s.TimeoutConfig.RequestTimeout, true)
+			success = s.PokeServer(t, "network-policy-conformance-hufflepuff", "cedric-diggory-1", "udp",
+				serverPod.Status.PodIP, int32(53), true)
 			assert.True(t, success)
 		})
 	},
@@ -26,7 +26,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
-	"sigs.k8s.io/network-policy-api/conformance/utils/kubernetes"
 	"sigs.k8s.io/network-policy-api/conformance/utils/suite"
 )
 
@@ -42,54 +41,10 @@ var AdminNetworkPolicyIngressSCTP = suite.ConformanceTest{
 	Features: []suite.SupportedFeature{
 		suite.SupportAdminNetworkPolicy,
 	},
-	Manifests: []string{"base/admin_network_policy/core-ingress-sctp-rules.yaml"},
+	Manifests:          []string{"base/admin_network_policy/core-ingress-sctp-rules.yaml"},
+	ConnectivityMatrix: "base/admin_network_policy/core-ingress-sctp-rules-connectivity.yaml",
+	Parallel:           true,
 	Test: func(t *testing.T, s *suite.ConformanceTestSuite) {
-
-		t.Run("Should support an 'allow-ingress' policy for SCTP protocol; ensure rule ordering is respected", func(t *testing.T) {
-			ctx, cancel := context.WithTimeout(context.Background(), s.TimeoutConfig.GetTimeout)
-			defer cancel()
-			// This test uses `ingress-sctp` ANP
-			// luna-lovegood-0 is our server pod in ravenclaw namespace
-			serverPod := &v1.Pod{}
-			err := s.Client.Get(ctx, client.ObjectKey{
-				Namespace: "network-policy-conformance-ravenclaw",
-				Name:      "luna-lovegood-0",
-			}, serverPod)
-			require.NoErrorf(t, err, "unable to fetch the server pod")
-			// harry-potter-0 is our client pod in gryffindor namespace
-			// ensure ingress is ALLOWED from gryffindor to ravenclaw
-			// ingressRule at index0 will take precedence over ingressRule at index1; thus ALLOW takes precedence over DENY since rules are ordered
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-gryffindor", "harry-potter-0", "sctp",
-				serverPod.Status.PodIP, int32(9003), s.TimeoutConfig.RequestTimeout, true)
-			assert.True(t, success)
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-gryffindor", "harry-potter-1", "sctp",
-				serverPod.Status.PodIP, int32(9005), s.TimeoutConfig.RequestTimeout, true)
-			assert.True(t, success)
-		})
-
-		t.Run("Should support an 'allow-ingress' policy for SCTP protocol at the specified port", func(t *testing.T) {
-			ctx, cancel := context.WithTimeout(context.Background(), s.TimeoutConfig.GetTimeout)
-			defer cancel()
-			// This test uses `ingress-sctp` ANP
-			// luna-lovegood-1 is our server pod in ravenclaw namespace
-			serverPod := &v1.Pod{}
-			err := s.Client.Get(ctx, client.ObjectKey{
-				Namespace: "network-policy-conformance-ravenclaw",
-				Name:      "luna-lovegood-1",
-			}, serverPod)
-			require.NoErrorf(t, err, "unable to fetch the server pod")
-			// cedric-diggory-0 is our client pod in hufflepuff namespace
-			// ensure ingress is ALLOWED from hufflepuff to ravenclaw at port 9003; ingressRule at index5 should take effect
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-hufflepuff", "cedric-diggory-0", "sctp",
-				serverPod.Status.PodIP, int32(9003), s.TimeoutConfig.RequestTimeout, true)
-			assert.True(t, success)
-			// cedric-diggory-1 is our client pod in hufflepuff namespace
-			// ensure ingress is DENIED from hufflepuff to ravenclaw for rest of the traffic; ingressRule at index6 should take effect
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-hufflepuff", "cedric-diggory-1", "sctp",
-				serverPod.Status.PodIP, int32(9005), s.TimeoutConfig.RequestTimeout, false)
-			assert.True(t, success)
-		})
-
 		t.Run("Should support an 'deny-ingress' policy for SCTP protocol; ensure rule ordering is respected", func(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), s.TimeoutConfig.GetTimeout)
 			defer cancel()
@@ -97,13 +52,13 @@ var AdminNetworkPolicyIngressSCTP = suite.ConformanceTest{
 			// luna-lovegood-1 is our server pod in ravenclaw namespace
 			serverPod := &v1.Pod{}
 			err := s.Client.Get(ctx, client.ObjectKey{
-				Namespace: "network-policy-conformance-ravenclaw",
+				Namespace: s.Namespace("network-policy-conformance-ravenclaw"),
 				Name:      "luna-lovegood-1",
 			}, serverPod)
 			require.NoErrorf(t, err, "unable to fetch the server pod")
 			anp := &v1alpha1.AdminNetworkPolicy{}
 			err = s.Client.Get(ctx, client.ObjectKey{
-				Name: "ingress-sctp",
+				Name: s.ClusterScopedName("ingress-sctp"),
 			}, anp)
 			require.NoErrorf(t, err, "unable to fetch the admin network policy")
 			mutate := anp.DeepCopy()
@@ -116,35 +71,12 @@ var AdminNetworkPolicyIngressSCTP = suite.ConformanceTest{
 			// harry-potter-0 is our client pod in gryffindor namespace
 			// ensure ingress is DENIED from gryffindor to ravenclaw
 			// ingressRule at index0 will take precedence over ingressRule at index1; thus DENY takes precedence over ALLOW since rules are ordered
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-gryffindor", "harry-potter-0", "sctp",
-				serverPod.Status.PodIP, int32(9003), s.TimeoutConfig.RequestTimeout, false)
+			success := s.PokeServer(t, "network-policy-conformance-gryffindor", "harry-potter-0", "sctp",
+				serverPod.Status.PodIP, int32(9003), false)
 			assert.True(t, success)
 			// harry-potter-1 is our client pod in gryffindor namespace
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-gryffindor", "harry-potter-1", "sctp",
-				serverPod.Status.PodIP, int32(9005), s.TimeoutConfig.RequestTimeout, false)
-			assert.True(t, success)
-		})
-
-		t.Run("Should support a 'deny-ingress' policy for SCTP protocol at the specified port", func(t *testing.T) {
-			ctx, cancel := context.WithTimeout(context.Background(), s.TimeoutConfig.GetTimeout)
-			defer cancel()
-			// This test uses `ingress-sctp` ANP
-			// luna-lovegood-0 is our server pod in ravenclaw namespace
-			serverPod := &v1.Pod{}
-			err := s.Client.Get(ctx, client.ObjectKey{
-				Namespace: "network-policy-conformance-ravenclaw",
-				Name:      "luna-lovegood-0",
-			}, serverPod)
-			require.NoErrorf(t, err, "unable to fetch the server pod")
-			// draco-malfoy-0 is our client pod in slytherin namespace
-			// ensure ingress from slytherin is DENIED to ravenclaw at port 9003; ingressRule at index3 should take effect
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-slytherin", "draco-malfoy-0", "sctp",
-				serverPod.Status.PodIP, int32(9003), s.TimeoutConfig.RequestTimeout, false)
-			assert.True(t, success)
-			// draco-malfoy-1 is our client pod in slytherin namespace
-			// ensure ingress from slytherin is ALLOWED to ravenclaw for rest of the traffic; matches no rules hence allowed
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-slytherin", "draco-malfoy-1", "sctp",
-				serverPod.Status.PodIP, int32(9005), s.TimeoutConfig.RequestTimeout, true)
+			success = s.PokeServer(t, "network-policy-conformance-gryffindor", "harry-potter-1", "sctp",
+				serverPod.Status.PodIP, int32(9005), false)
 			assert.True(t, success)
 		})
 
@@ -155,13 +87,13 @@ var AdminNetworkPolicyIngressSCTP = suite.ConformanceTest{
 			// luna-lovegood-1 is our server pod in ravenclaw namespace
 			serverPod := &v1.Pod{}
 			err := s.Client.Get(ctx, client.ObjectKey{
-				Namespace: "network-policy-conformance-ravenclaw",
+				Namespace: s.Namespace("network-policy-conformance-ravenclaw"),
 				Name:      "luna-lovegood-1",
 			}, serverPod)
 			require.NoErrorf(t, err, "unable to fetch the server pod")
 			anp := &v1alpha1.AdminNetworkPolicy{}
 			err = s.Client.Get(ctx, client.ObjectKey{
-				Name: "ingress-sctp",
+				Name: s.ClusterScopedName("ingress-sctp"),
 			}, anp)
 			require.NoErrorf(t, err, "unable to fetch the admin network policy")
 			mutate := anp.DeepCopy()
@@ -174,12 +106,12 @@ var AdminNetworkPolicyIngressSCTP = suite.ConformanceTest{
 			// harry-potter-0 is our client pod in gryffindor namespace
 			// ensure ingress is PASSED from gryffindor to ravenclaw
 			// ingressRule at index0 will take precedence over ingressRule at index1&index2; thus PASS takes precedence over ALLOW/DENY since rules are ordered
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-gryffindor", "harry-potter-0", "sctp",
-				serverPod.Status.PodIP, int32(9003), s.TimeoutConfig.RequestTimeout, true)
+			success := s.PokeServer(t, "network-policy-conformance-gryffindor", "harry-potter-0", "sctp",
+				serverPod.Status.PodIP, int32(9003), true)
 			assert.True(t, success)
 			// harry-potter-1 is our client pod in gryffindor namespace
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-gryffindor", "harry-potter-1", "sctp",
-				serverPod.Status.PodIP, int32(9005), s.TimeoutConfig.RequestTimeout, true)
+			success = s.PokeServer(t, "network-policy-conformance-gryffindor", "harry-potter-1", "sctp",
+				serverPod.Status.PodIP, int32(9005), true)
 			assert.True(t, success)
 		})
 
@@ -190,13 +122,13 @@ var AdminNetworkPolicyIngressSCTP = suite.ConformanceTest{
 			// luna-lovegood-0 is our server pod in ravenclaw namespace
 			serverPod := &v1.Pod{}
 			err := s.Client.Get(ctx, client.ObjectKey{
-				Namespace: "network-policy-conformance-ravenclaw",
+				Namespace: s.Namespace("network-policy-conformance-ravenclaw"),
 				Name:      "luna-lovegood-0",
 			}, serverPod)
 			require.NoErrorf(t, err, "unable to fetch the server pod")
 			anp := &v1alpha1.AdminNetworkPolicy{}
 			err = s.Client.Get(ctx, client.ObjectKey{
-				Name: "ingress-sctp",
+				Name: s.ClusterScopedName("ingress-sctp"),
 			}, anp)
 			require.NoErrorf(t, err, "unable to fetch the admin network policy")
 			mutate := anp.DeepCopy()
@@ -208,13 +140,13 @@ var AdminNetworkPolicyIngressSCTP = suite.ConformanceTest{
 			require.NoErrorf(t, err, "unable to patch the admin network policy")
 			// draco-malfoy-0 is our client pod in slytherin namespace
 			// ensure ingress from slytherin is PASSED to ravenclaw at port 9003; ingressRule at index3 should take effect
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-slytherin", "draco-malfoy-0", "sctp",
-				serverPod.Status.PodIP, int32(9003), s.TimeoutConfig.RequestTimeout, true)
+			success := s.PokeServer(t, "network-policy-conformance-slytherin", "draco-malfoy-0", "sctp",
+				serverPod.Status.PodIP, int32(9003), true)
 			assert.True(t, success)
 			// draco-malfoy-1 is our client pod in slytherin namespace
 			// ensure ingress from slytherin is ALLOWED to ravenclaw for rest of the traffic; matches no rules hence allowed
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-slytherin", "draco-malfoy-1", "sctp",
-				serverPod.Status.PodIP, int32(9005), s.TimeoutConfig.RequestTimeout, true)
+			success = s.PokeServer(t, "network-policy-conformance-slytherin", "draco-malfoy-1", "sctp",
+				serverPod.Status.PodIP, int32(9005), true)
 			assert.True(t, success)
 		})
 	},
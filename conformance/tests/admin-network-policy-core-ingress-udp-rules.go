@@ -26,7 +26,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"sigs.k8s.io/network-policy-api/apis/v1alpha1"
-	"sigs.k8s.io/network-policy-api/conformance/utils/kubernetes"
 	"sigs.k8s.io/network-policy-api/conformance/utils/suite"
 )
 
@@ -43,54 +42,10 @@ var AdminNetworkPolicyIngressUDP = suite.ConformanceTest{
 	Features: []suite.SupportedFeature{
 		suite.SupportAdminNetworkPolicy,
 	},
-	Manifests: []string{"base/admin_network_policy/core-ingress-udp-rules.yaml"},
+	Manifests:          []string{"base/admin_network_policy/core-ingress-udp-rules.yaml"},
+	ConnectivityMatrix: "base/admin_network_policy/core-ingress-udp-rules-connectivity.yaml",
+	Parallel:           true,
 	Test: func(t *testing.T, s *suite.ConformanceTestSuite) {
-
-		t.Run("Should support an 'allow-ingress' policy for UDP protocol; ensure rule ordering is respected", func(t *testing.T) {
-			ctx, cancel := context.WithTimeout(context.Background(), s.TimeoutConfig.GetTimeout)
-			defer cancel()
-			// This test uses `ingress-udp` ANP
-			// cedric-diggory-0 is our server pod in hufflepuff namespace
-			serverPod := &v1.Pod{}
-			err := s.Client.Get(ctx, client.ObjectKey{
-				Namespace: "network-policy-conformance-hufflepuff",
-				Name:      "cedric-diggory-0",
-			}, serverPod)
-			require.NoErrorf(t, err, "unable to fetch the server pod")
-			// luna-lovegood-0 is our client pod in ravenclaw namespace
-			// ensure ingress is ALLOWED from ravenclaw to hufflepuff
-			// ingressRule at index0 will take precedence over ingressRule at index1; thus ALLOW takes precedence over DENY since rules are ordered
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "udp",
-				serverPod.Status.PodIP, int32(53), s.TimeoutConfig.RequestTimeout, true)
-			assert.True(t, success)
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "udp",
-				serverPod.Status.PodIP, int32(5353), s.TimeoutConfig.RequestTimeout, true)
-			assert.True(t, success)
-		})
-
-		t.Run("Should support an 'allow-ingress' policy for UDP protocol at the specified port", func(t *testing.T) {
-			ctx, cancel := context.WithTimeout(context.Background(), s.TimeoutConfig.GetTimeout)
-			defer cancel()
-			// This test uses `ingress-udp` ANP
-			// cedric-diggory-1 is our server pod in hufflepuff namespace
-			serverPod := &v1.Pod{}
-			err := s.Client.Get(ctx, client.ObjectKey{
-				Namespace: "network-policy-conformance-hufflepuff",
-				Name:      "cedric-diggory-1",
-			}, serverPod)
-			require.NoErrorf(t, err, "unable to fetch the server pod")
-			// harry-potter-0 is our client pod in gryffindor namespace
-			// ensure ingress is ALLOWED from gryffindor to hufflepuff at port 53; ingressRule at index5
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-gryffindor", "harry-potter-0", "udp",
-				serverPod.Status.PodIP, int32(53), s.TimeoutConfig.RequestTimeout, true)
-			assert.True(t, success)
-			// harry-potter-1 is our client pod in gryfindor namespace
-			// ensure ingress is DENIED from gryffindor to hufflepuff for rest of the traffic; ingressRule at index6 should take effect
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-gryffindor", "harry-potter-1", "udp",
-				serverPod.Status.PodIP, int32(5353), s.TimeoutConfig.RequestTimeout, false)
-			assert.True(t, success)
-		})
-
 		t.Run("Should support an 'deny-ingress' policy for UDP protocol; ensure rule ordering is respected", func(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), s.TimeoutConfig.GetTimeout)
 			defer cancel()
@@ -98,13 +53,13 @@ var AdminNetworkPolicyIngressUDP = suite.ConformanceTest{
 			// cedric-diggory-1 is our server pod in hufflepuff namespace
 			serverPod := &v1.Pod{}
 			err := s.Client.Get(ctx, client.ObjectKey{
-				Namespace: "network-policy-conformance-hufflepuff",
+				Namespace: s.Namespace("network-policy-conformance-hufflepuff"),
 				Name:      "cedric-diggory-1",
 			}, serverPod)
 			require.NoErrorf(t, err, "unable to fetch the server pod")
 			anp := &v1alpha1.AdminNetworkPolicy{}
 			err = s.Client.Get(ctx, client.ObjectKey{
-				Name: "ingress-udp",
+				Name: s.ClusterScopedName("ingress-udp"),
 			}, anp)
 			require.NoErrorf(t, err, "unable to fetch the admin network policy")
 			mutate := anp.DeepCopy()
@@ -117,35 +72,12 @@ var AdminNetworkPolicyIngressUDP = suite.ConformanceTest{
 			// luna-lovegood-0 is our client pod in ravenclaw namespace
 			// ensure ingress is DENIED from ravenclaw to hufflepuff
 			// ingressRule at index0 will take precedence over ingressRule at index1; thus DENY takes precedence over ALLOW since rules are ordered
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "udp",
-				serverPod.Status.PodIP, int32(53), s.TimeoutConfig.RequestTimeout, false)
+			success := s.PokeServer(t, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "udp",
+				serverPod.Status.PodIP, int32(53), false)
 			assert.True(t, success)
 			// luna-lovegood-1 is our client pod in ravenclaw namespace
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "udp",
-				serverPod.Status.PodIP, int32(5353), s.TimeoutConfig.RequestTimeout, false)
-			assert.True(t, success)
-		})
-
-		t.Run("Should support a 'deny-ingress' policy for UDP protocol at the specified port", func(t *testing.T) {
-			ctx, cancel := context.WithTimeout(context.Background(), s.TimeoutConfig.GetTimeout)
-			defer cancel()
-			// This test uses `ingress-udp` ANP
-			// cedric-diggory-0 is our server pod in hufflepuff namespace
-			serverPod := &v1.Pod{}
-			err := s.Client.Get(ctx, client.ObjectKey{
-				Namespace: "network-policy-conformance-hufflepuff",
-				Name:      "cedric-diggory-0",
-			}, serverPod)
-			require.NoErrorf(t, err, "unable to fetch the server pod")
-			// draco-malfoy-0 is our client pod in slytherin namespace
-			// ensure ingress from slytherin is DENIED to hufflepuff at port 80; ingressRule at index3 should take effect
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-slytherin", "draco-malfoy-0", "udp",
-				serverPod.Status.PodIP, int32(5353), s.TimeoutConfig.RequestTimeout, false)
-			assert.True(t, success)
-			// draco-malfoy-1 is our client pod in slytherin namespace
-			// ensure ingress from slytherin is ALLOWED to hufflepuff for rest of the traffic; matches no rules hence allowed
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-slytherin", "draco-malfoy-1", "udp",
-				serverPod.Status.PodIP, int32(53), s.TimeoutConfig.RequestTimeout, true)
+			success = s.PokeServer(t, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "udp",
+				serverPod.Status.PodIP, int32(5353), false)
 			assert.True(t, success)
 		})
 
@@ -156,13 +88,13 @@ var AdminNetworkPolicyIngressUDP = suite.ConformanceTest{
 			// cedric-diggory-1 is our server pod in hufflepuff namespace
 			serverPod := &v1.Pod{}
 			err := s.Client.Get(ctx, client.ObjectKey{
-				Namespace: "network-policy-conformance-hufflepuff",
+				Namespace: s.Namespace("network-policy-conformance-hufflepuff"),
 				Name:      "cedric-diggory-1",
 			}, serverPod)
 			require.NoErrorf(t, err, "unable to fetch the server pod")
 			anp := &v1alpha1.AdminNetworkPolicy{}
 			err = s.Client.Get(ctx, client.ObjectKey{
-				Name: "ingress-udp",
+				Name: s.ClusterScopedName("ingress-udp"),
 			}, anp)
 			require.NoErrorf(t, err, "unable to fetch the admin network policy")
 			mutate := anp.DeepCopy()
@@ -175,12 +107,12 @@ var AdminNetworkPolicyIngressUDP = suite.ConformanceTest{
 			// luna-lovegood-0 is our client pod in ravenclaw namespace
 			// ensure ingress is PASSED from ravenclaw to hufflepuff
 			// ingressRule at index0 will take precedence over ingressRule at index1&index2; thus PASS takes precedence over ALLOW/DENY since rules are ordered
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "udp",
-				serverPod.Status.PodIP, int32(5353), s.TimeoutConfig.RequestTimeout, true)
+			success := s.PokeServer(t, "network-policy-conformance-ravenclaw", "luna-lovegood-0", "udp",
+				serverPod.Status.PodIP, int32(5353), true)
 			assert.True(t, success)
 			// luna-lovegood-1 is our client pod in ravenclaw namespace
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "udp",
-				serverPod.Status.PodIP, int32(53), s.TimeoutConfig.RequestTimeout, true)
+			success = s.PokeServer(t, "network-policy-conformance-ravenclaw", "luna-lovegood-1", "udp",
+				serverPod.Status.PodIP, int32(53), true)
 			assert.True(t, success)
 		})
 
@@ -191,13 +123,13 @@ var AdminNetworkPolicyIngressUDP = suite.ConformanceTest{
 			// cedric-diggory-0 is our server pod in hufflepuff namespace
 			serverPod := &v1.Pod{}
 			err := s.Client.Get(ctx, client.ObjectKey{
-				Namespace: "network-policy-conformance-hufflepuff",
+				Namespace: s.Namespace("network-policy-conformance-hufflepuff"),
 				Name:      "cedric-diggory-0",
 			}, serverPod)
 			require.NoErrorf(t, err, "unable to fetch the server pod")
 			anp := &v1alpha1.AdminNetworkPolicy{}
 			err = s.Client.Get(ctx, client.ObjectKey{
-				Name: "ingress-udp",
+				Name: s.ClusterScopedName("ingress-udp"),
 			}, anp)
 			require.NoErrorf(t, err, "unable to fetch the admin network policy")
 			mutate := anp.DeepCopy()
@@ -209,13 +141,13 @@ var AdminNetworkPolicyIngressUDP = suite.ConformanceTest{
 			require.NoErrorf(t, err, "unable to patch the admin network policy")
 			// draco-malfoy-0 is our client pod in slytherin namespace
 			// ensure ingress from slytherin is PASSED to hufflepuff at port 5353; ingressRule at index3 should take effect
-			success := kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-slytherin", "draco-malfoy-0", "udp",
-				serverPod.Status.PodIP, int32(5353), s.TimeoutConfig.RequestTimeout, true)
+			success := s.PokeServer(t, "network-policy-conformance-slytherin", "draco-malfoy-0", "udp",
+				serverPod.Status.PodIP, int32(5353), true)
 			assert.True(t, success)
 			// draco-malfoy-1 is our client pod in slytherin namespace
 			// ensure ingress from slytherin is ALLOWED to hufflepuff for rest of the traffic; matches no rules hence allowed
-			success = kubernetes.PokeServer(t, s.ClientSet, &s.KubeConfig, "network-policy-conformance-slytherin", "draco-malfoy-1", "udp",
-				serverPod.Status.PodIP, int32(53), s.TimeoutConfig.RequestTimeout, true)
+			success = s.PokeServer(t, "network-policy-conformance-slytherin", "draco-malfoy-1", "udp",
+				serverPod.Status.PodIP, int32(53), true)
 			assert.True(t, success)
 		})
 	},
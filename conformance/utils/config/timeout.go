@@ -44,6 +44,12 @@ type TimeoutConfig struct {
 	// RequestTimeout represents the maximum time before which the connection attempt from client to server will timeout.
 	// Max value for conformant implementation: None
 	RequestTimeout time.Duration
+
+	// PolicyConvergence represents the maximum time for connectivity between a client and a server to match
+	// expectations, after the policies which decide it are created or changed.  Implementations may program
+	// policies asynchronously, so connectivity is retried until then.
+	// Max value for conformant implementation: None
+	PolicyConvergence time.Duration
 }
 
 // DefaultTimeoutConfig populates a TimeoutConfig with the default values.
@@ -55,6 +61,7 @@ func DefaultTimeoutConfig() TimeoutConfig {
 		ManifestFetchTimeout:  10 * time.Second,
 		NamespacesMustBeReady: 300 * time.Second,
 		RequestTimeout:        3 * time.Second,
+		PolicyConvergence:     30 * time.Second,
 	}
 }
 
@@ -78,4 +85,7 @@ func SetupTimeoutConfig(timeoutConfig *TimeoutConfig) {
 	if timeoutConfig.RequestTimeout == 0 {
 		timeoutConfig.RequestTimeout = defaultTimeoutConfig.RequestTimeout
 	}
+	if timeoutConfig.PolicyConvergence == 0 {
+		timeoutConfig.PolicyConvergence = defaultTimeoutConfig.PolicyConvergence
+	}
 }
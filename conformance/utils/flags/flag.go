@@ -29,6 +29,7 @@ var (
 	SupportedFeatures          = flag.String("supported-features", "", "Supported features included in conformance tests suites")
 	ExemptFeatures             = flag.String("exempt-features", "", "Exempt Features excluded from conformance tests suites")
 	EnableAllSupportedFeatures = flag.Bool("all-features", false, "Whether to enable all supported features for conformance tests")
+	PolicyConvergenceTimeout   = flag.Duration("policy-convergence-timeout", 0, "How long to keep probing for connectivity to match expectations after policies change (defaults to 30s)")
 )
 
 // Required for Conformance Profiles
@@ -41,4 +42,5 @@ var (
 	ImplementationAdditionalInformation = flag.String("additional-info", "", "Link to implementation's CI integration that shows how the report was generated")
 	ConformanceProfiles                 = flag.String("conformance-profiles", "", "Comma-separated list of the conformance profiles to run")
 	ReportOutput                        = flag.String("report-output", "", "The file where to write the conformance report")
+	JUnitOutput                         = flag.String("junit-output", "", "The file where to write the results of the conformance report's tests as JUnit XML")
 )
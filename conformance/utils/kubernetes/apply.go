@@ -44,6 +44,10 @@ type Applier struct {
 
 	// FS is the filesystem to use when reading manifests.
 	FS embed.FS
+
+	// NamespaceSet, if set, renames and scopes resources so that they only
+	// affect the namespaces of the set.
+	NamespaceSet *NamespaceSet
 }
 
 // prepareNamespace adjusts the Namespace labels.
@@ -87,6 +91,10 @@ func (a Applier) prepareResources(t *testing.T, decoder *yaml.YAMLOrJSONDecoder)
 			prepareNamespace(t, &uObj, a.NamespaceLabels)
 		}
 
+		if a.NamespaceSet != nil && !a.NamespaceSet.prepare(t, &uObj) {
+			continue
+		}
+
 		resources = append(resources, uObj)
 	}
 
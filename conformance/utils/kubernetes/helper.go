@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"math"
 	"net"
 	"strings"
 	"testing"
@@ -64,6 +65,15 @@ func RunCommandFromPod(client k8sclient.Interface, kubeConfig *rest.Config, podN
 // using the provided protocol can be established or not and returns the result based on if the expectation is shouldConnect or !shouldConnect
 func PokeServer(t *testing.T, client k8sclient.Interface, kubeConfig *rest.Config, clientNamespace, clientPod, protocol, targetHost string, targetPort int32, timeout time.Duration, shouldConnect bool) bool {
 	t.Helper()
+	success, failure := pokeServer(client, kubeConfig, clientNamespace, clientPod, protocol, targetHost, targetPort, timeout, shouldConnect)
+	if !success {
+		t.Log(failure)
+	}
+	return success
+}
+
+// pokeServer runs a single connection attempt for PokeServer, returning whether it matched shouldConnect, and why not if it didn't
+func pokeServer(client k8sclient.Interface, kubeConfig *rest.Config, clientNamespace, clientPod, protocol, targetHost string, targetPort int32, timeout time.Duration, shouldConnect bool) (bool, string) {
 	timeoutArg := fmt.Sprintf("--timeout=%v", timeout)
 	protocolArg := fmt.Sprintf("--protocol=%s", protocol)
 	ipPortArg := net.JoinHostPort(targetHost, fmt.Sprintf("%d", targetPort))
@@ -75,33 +85,104 @@ func PokeServer(t *testing.T, client k8sclient.Interface, kubeConfig *rest.Confi
 		ipPortArg), " ")
 
 	stdout, stderr, err := RunCommandFromPod(client, kubeConfig, clientNamespace, clientPod, connectCommand)
-	// TODO(tssurya): See if we need to add a wait&retry mechanism to test connectivity
-	// See https://github.com/kubernetes-sigs/network-policy-api/issues/108 for details.
 	if err != nil && stderr == "" {
 		// If err != nil and stderr == "", then it means this probe failed because of the command instead of connectivity.
-		t.Logf("FAILED to execute command %s on pod %s/%s: %v", connectCommand, clientNamespace, clientPod, err.Error())
-		return false
+		return false, fmt.Sprintf("FAILED to execute command %s on pod %s/%s: %v", connectCommand, clientNamespace, clientPod, err.Error())
 	}
 	if shouldConnect && len(stderr) > 0 {
-		t.Logf("FAILED Command was %s", connectCommand)
-		t.Logf("Expected connection to succeed from %s/%s to %s, but instead it miserably failed. stderr: %v",
-			clientNamespace, clientPod, targetHost, stderr)
-		return false
+		return false, fmt.Sprintf("FAILED Command was %s\nExpected connection to succeed from %s/%s to %s, but instead it miserably failed. stderr: %v",
+			connectCommand, clientNamespace, clientPod, targetHost, stderr)
 	} else if !shouldConnect {
 		if stdout == "" && stderr == "" {
-			t.Logf("FAILED Command was %s", connectCommand)
-			t.Logf("Expected connection to fail from %s/%s to %s, but instead it successfully connected.",
-				clientNamespace, clientPod, targetHost)
-			return false
+			return false, fmt.Sprintf("FAILED Command was %s\nExpected connection to fail from %s/%s to %s, but instead it successfully connected.",
+				connectCommand, clientNamespace, clientPod, targetHost)
 		} else if !strings.Contains(stderr, "TIMEOUT") {
-			t.Logf("FAILED Command was %s", connectCommand)
 			// Other possible results include "REFUSED" for example, signaling the connection is rejected.
-			t.Logf("Expected connection to be dropped from %s/%s to %s, but instead it returned a different status: %s",
-				clientNamespace, clientPod, targetHost, stderr)
-			return false
+			return false, fmt.Sprintf("FAILED Command was %s\nExpected connection to be dropped from %s/%s to %s, but instead it returned a different status: %s",
+				connectCommand, clientNamespace, clientPod, targetHost, stderr)
+		}
+	}
+	return true, ""
+}
+
+// Convergence records how long it took for the connectivity from a client pod to a server to match expectations.
+type Convergence struct {
+	// Test is the name of the test which expected the connectivity.
+	Test            string
+	ClientNamespace string
+	ClientPod       string
+	Protocol        string
+	// Target is the host:port of the server.
+	Target        string
+	ShouldConnect bool
+	// Attempts is the number of connections which were attempted.
+	Attempts int
+	// Duration is the time from the first attempt until the connectivity matched expectations, or until giving up.
+	Duration  time.Duration
+	Converged bool
+	// Failure describes the last attempt, if the connectivity never matched expectations.
+	Failure string
+}
+
+// pokeServerBackoff spaces out the connection attempts of PokeServerUntilConverged; the policy convergence timeout
+// limits how long they go on for.
+var pokeServerBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    math.MaxInt32,
+	Cap:      5 * time.Second,
+}
+
+// PokeServerUntilConverged is like PokeServer, but since implementations may program policies asynchronously,
+// it retries with backoff until the connectivity matches shouldConnect or timeoutConfig.PolicyConvergence expires.
+// Only the last failure is logged.
+func PokeServerUntilConverged(t *testing.T, client k8sclient.Interface, kubeConfig *rest.Config, clientNamespace, clientPod, protocol, targetHost string, targetPort int32, timeoutConfig config.TimeoutConfig, shouldConnect bool) Convergence {
+	t.Helper()
+	convergence := Convergence{
+		Test:            t.Name(),
+		ClientNamespace: clientNamespace,
+		ClientPod:       clientPod,
+		Protocol:        protocol,
+		Target:          net.JoinHostPort(targetHost, fmt.Sprintf("%d", targetPort)),
+		ShouldConnect:   shouldConnect,
+	}
+	var failure string
+	convergence.Converged, convergence.Attempts, convergence.Duration, failure = pollUntilConverged(timeoutConfig.PolicyConvergence, pokeServerBackoff, func() (bool, string) {
+		return pokeServer(client, kubeConfig, clientNamespace, clientPod, protocol, targetHost, targetPort, timeoutConfig.RequestTimeout, shouldConnect)
+	})
+	if !convergence.Converged {
+		convergence.Failure = fmt.Sprintf("%s\nGave up after %d attempts in %v", failure, convergence.Attempts, convergence.Duration)
+		t.Log(convergence.Failure)
+	} else if convergence.Attempts > 1 {
+		t.Logf("Connectivity from %s/%s to %s converged after %d attempts in %v", clientNamespace, clientPod, convergence.Target, convergence.Attempts, convergence.Duration)
+	}
+	return convergence
+}
+
+// pollUntilConverged runs probe until it succeeds, waiting between attempts according to backoff, with a last attempt
+// when timeout expires.  It returns whether probe succeeded, the number of attempts and how long they took,
+// and the last failure.
+func pollUntilConverged(timeout time.Duration, backoff wait.Backoff, probe func() (bool, string)) (bool, int, time.Duration, string) {
+	start := time.Now()
+	deadline := start.Add(timeout)
+	attempts := 0
+	for {
+		attempts++
+		success, failure := probe()
+		if success {
+			return true, attempts, time.Since(start), ""
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return false, attempts, time.Since(start), failure
+		}
+		delay := backoff.Step()
+		if delay > remaining {
+			delay = remaining
 		}
+		time.Sleep(delay)
 	}
-	return true
 }
 
 // NamespacesMustBeReady waits until all Pods are marked Ready. This will
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestPollUntilConverged(t *testing.T) {
+	backoff := wait.Backoff{Duration: time.Millisecond, Factor: 2, Steps: 100, Cap: 10 * time.Millisecond}
+
+	tests := []struct {
+		name              string
+		timeout           time.Duration
+		succeedOnAttempt  int
+		expectedConverged bool
+		expectedAttempts  int
+		expectedFailure   string
+	}{{
+		name:              "succeeds immediately",
+		timeout:           time.Second,
+		succeedOnAttempt:  1,
+		expectedConverged: true,
+		expectedAttempts:  1,
+	}, {
+		name:              "succeeds after retries",
+		timeout:           time.Second,
+		succeedOnAttempt:  4,
+		expectedConverged: true,
+		expectedAttempts:  4,
+	}, {
+		name:              "no timeout means a single attempt",
+		succeedOnAttempt:  2,
+		expectedConverged: false,
+		expectedAttempts:  1,
+		expectedFailure:   "attempt 1 failed",
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts := 0
+			converged, actualAttempts, duration, failure := pollUntilConverged(tc.timeout, backoff, func() (bool, string) {
+				attempts++
+				if attempts >= tc.succeedOnAttempt {
+					return true, ""
+				}
+				return false, fmt.Sprintf("attempt %d failed", attempts)
+			})
+			require.Equal(t, tc.expectedConverged, converged)
+			require.Equal(t, tc.expectedAttempts, actualAttempts)
+			require.Equal(t, tc.expectedFailure, failure)
+			require.Less(t, duration, time.Second)
+		})
+	}
+}
+
+func TestPollUntilConvergedGivesUp(t *testing.T) {
+	backoff := wait.Backoff{Duration: time.Millisecond, Factor: 2, Steps: 100, Cap: 10 * time.Millisecond}
+	timeout := 50 * time.Millisecond
+
+	attempts := 0
+	converged, actualAttempts, duration, failure := pollUntilConverged(timeout, backoff, func() (bool, string) {
+		attempts++
+		return false, fmt.Sprintf("attempt %d failed", attempts)
+	})
+	require.False(t, converged)
+	require.Equal(t, attempts, actualAttempts)
+	require.Greater(t, actualAttempts, 1)
+	require.Equal(t, fmt.Sprintf("attempt %d failed", attempts), failure)
+	require.GreaterOrEqual(t, duration, timeout)
+}
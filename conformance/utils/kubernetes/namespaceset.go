@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+	"hash/fnv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// NamespaceSetLabel labels the namespaces of a NamespaceSet with its ID.
+const NamespaceSetLabel = "network-policy-conformance-namespace-set"
+
+// NamespaceSet is a copy of the base namespaces for a single test, so that
+// tests can run in parallel on one cluster without their policies affecting
+// each other.
+//
+// When an Applier has a NamespaceSet, the namespaces it applies are renamed
+// with the ID as a suffix and labeled with NamespaceSetLabel, and so are
+// references to them in metadata.namespace and in kubernetes.io/metadata.name
+// namespace selectors. AdminNetworkPolicies are renamed
+// too, since they're cluster-scoped, and the namespace selectors of
+// AdminNetworkPolicies and NetworkPolicies are scoped to the NamespaceSet's
+// namespaces.
+//
+// Tests with networks or nodes peers can't have a NamespaceSet, since those
+// peers can't be scoped, and neither can tests with a
+// BaselineAdminNetworkPolicy, since there's only one per cluster. Nor can tests
+// which need host-networked pods, which are left out of NamespaceSets.
+type NamespaceSet struct {
+	ID string
+
+	// namespaces are the names of the namespaces applied so far, before
+	// they were renamed.
+	namespaces map[string]bool
+}
+
+// NewNamespaceSet creates a NamespaceSet whose ID is derived from the name of
+// the test it's for.
+func NewNamespaceSet(testName string) *NamespaceSet {
+	hash := fnv.New32a()
+	hash.Write([]byte(testName))
+	return &NamespaceSet{
+		ID:         fmt.Sprintf("%08x", hash.Sum32()),
+		namespaces: map[string]bool{},
+	}
+}
+
+// Namespace returns the name of a namespace in the NamespaceSet.
+func (n *NamespaceSet) Namespace(name string) string {
+	return fmt.Sprintf("%s-%s", name, n.ID)
+}
+
+// ClusterScopedName returns the name of a cluster-scoped resource applied
+// for the NamespaceSet.
+func (n *NamespaceSet) ClusterScopedName(name string) string {
+	return fmt.Sprintf("%s-%s", name, n.ID)
+}
+
+// prepare renames and scopes a resource for the NamespaceSet, returning false
+// if it should be left out: host-networked StatefulSets would clash with those
+// of the base namespaces over host ports.
+func (n *NamespaceSet) prepare(t *testing.T, uObj *unstructured.Unstructured) bool {
+	switch uObj.GetKind() {
+	case "StatefulSet":
+		hostNetwork, _, err := unstructured.NestedBool(uObj.Object, "spec", "template", "spec", "hostNetwork")
+		require.NoErrorf(t, err, "error getting hostNetwork of StatefulSet %s", uObj.GetName())
+		if hostNetwork {
+			return false
+		}
+	case "Namespace":
+		n.namespaces[uObj.GetName()] = true
+		labels := uObj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[NamespaceSetLabel] = n.ID
+		uObj.SetLabels(labels)
+		uObj.SetName(n.Namespace(uObj.GetName()))
+	case "AdminNetworkPolicy":
+		uObj.SetName(n.ClusterScopedName(uObj.GetName()))
+		n.scopeSelectors(uObj.Object["spec"], "namespaces", "namespaceSelector")
+	case "NetworkPolicy":
+		n.scopeSelectors(uObj.Object["spec"], "namespaceSelector")
+	case "BaselineAdminNetworkPolicy":
+		require.FailNow(t, "a BaselineAdminNetworkPolicy can't be applied for a NamespaceSet, since there's only one per cluster")
+	}
+	if uObj.GetNamespace() != "" {
+		uObj.SetNamespace(n.rename(uObj.GetNamespace()))
+	}
+	return true
+}
+
+// scopeSelectors scopes the label selectors under the given keys, wherever
+// they are in obj, to the namespaces of the NamespaceSet: NamespaceSetLabel is
+// added to them, and the namespaces which they select by
+// kubernetes.io/metadata.name are renamed.
+func (n *NamespaceSet) scopeSelectors(obj interface{}, selectorKeys ...string) {
+	switch o := obj.(type) {
+	case map[string]interface{}:
+		for key, value := range o {
+			selector, isMap := value.(map[string]interface{})
+			isSelector := false
+			for _, selectorKey := range selectorKeys {
+				isSelector = isSelector || key == selectorKey
+			}
+			if isSelector && isMap {
+				n.scopeSelector(selector)
+				continue
+			}
+			n.scopeSelectors(value, selectorKeys...)
+		}
+	case []interface{}:
+		for _, value := range o {
+			n.scopeSelectors(value, selectorKeys...)
+		}
+	}
+}
+
+func (n *NamespaceSet) scopeSelector(selector map[string]interface{}) {
+	matchLabels, _ := selector["matchLabels"].(map[string]interface{})
+	if matchLabels == nil {
+		matchLabels = map[string]interface{}{}
+	}
+	if name, ok := matchLabels[corev1.LabelMetadataName].(string); ok {
+		matchLabels[corev1.LabelMetadataName] = n.rename(name)
+	}
+	matchLabels[NamespaceSetLabel] = n.ID
+	selector["matchLabels"] = matchLabels
+
+	matchExpressions, _ := selector["matchExpressions"].([]interface{})
+	for _, expression := range matchExpressions {
+		expression, _ := expression.(map[string]interface{})
+		if expression == nil || expression["key"] != corev1.LabelMetadataName {
+			continue
+		}
+		values, _ := expression["values"].([]interface{})
+		for i, value := range values {
+			if name, ok := value.(string); ok {
+				values[i] = n.rename(name)
+			}
+		}
+	}
+}
+
+// rename renames a namespace if it's one of the NamespaceSet's.
+func (n *NamespaceSet) rename(namespace string) string {
+	if n.namespaces[namespace] {
+		return n.Namespace(namespace)
+	}
+	return namespace
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+func TestNamespaceSet(t *testing.T) {
+	set := NewNamespaceSet("AdminNetworkPolicyIngressTCP")
+	require.Len(t, set.ID, 8)
+	require.Equal(t, set.ID, NewNamespaceSet("AdminNetworkPolicyIngressTCP").ID)
+	require.NotEqual(t, set.ID, NewNamespaceSet("AdminNetworkPolicyIngressUDP").ID)
+
+	applier := Applier{NamespaceSet: set}
+	base := `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: gryffindor
+  labels:
+    conformance-house: gryffindor
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: harry-potter
+  namespace: gryffindor
+spec:
+  replicas: 2
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: centaur
+  namespace: gryffindor
+spec:
+  template:
+    spec:
+      hostNetwork: true
+`
+	resources, err := applier.prepareResources(t, yaml.NewYAMLOrJSONDecoder(strings.NewReader(base), 4096))
+	require.NoError(t, err)
+	require.EqualValues(t, []unstructured.Unstructured{{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata": map[string]interface{}{
+				"name": "gryffindor-" + set.ID,
+				"labels": map[string]interface{}{
+					"conformance-house": "gryffindor",
+					NamespaceSetLabel:   set.ID,
+				},
+			},
+		},
+	}, {
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "StatefulSet",
+			"metadata": map[string]interface{}{
+				"name":      "harry-potter",
+				"namespace": "gryffindor-" + set.ID,
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(2),
+			},
+		},
+	}}, resources)
+
+	policies := `
+apiVersion: policy.networking.k8s.io/v1alpha1
+kind: AdminNetworkPolicy
+metadata:
+  name: ingress-tcp
+spec:
+  priority: 3
+  subject:
+    namespaces:
+      matchLabels:
+        kubernetes.io/metadata.name: gryffindor
+  ingress:
+  - action: Allow
+    from:
+    - namespaces: {}
+    - pods:
+        namespaceSelector:
+          matchExpressions:
+          - key: kubernetes.io/metadata.name
+            operator: In
+            values: [gryffindor, slytherin]
+        podSelector: {}
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: allow-from-all-namespaces
+  namespace: gryffindor
+spec:
+  podSelector: {}
+  ingress:
+  - from:
+    - namespaceSelector: {}
+`
+	resources, err = applier.prepareResources(t, yaml.NewYAMLOrJSONDecoder(strings.NewReader(policies), 4096))
+	require.NoError(t, err)
+	require.EqualValues(t, []unstructured.Unstructured{{
+		Object: map[string]interface{}{
+			"apiVersion": "policy.networking.k8s.io/v1alpha1",
+			"kind":       "AdminNetworkPolicy",
+			"metadata": map[string]interface{}{
+				"name": "ingress-tcp-" + set.ID,
+			},
+			"spec": map[string]interface{}{
+				"priority": int64(3),
+				"subject": map[string]interface{}{
+					"namespaces": map[string]interface{}{
+						"matchLabels": map[string]interface{}{
+							"kubernetes.io/metadata.name": "gryffindor-" + set.ID,
+							NamespaceSetLabel:             set.ID,
+						},
+					},
+				},
+				"ingress": []interface{}{
+					map[string]interface{}{
+						"action": "Allow",
+						"from": []interface{}{
+							map[string]interface{}{
+								"namespaces": map[string]interface{}{
+									"matchLabels": map[string]interface{}{NamespaceSetLabel: set.ID},
+								},
+							},
+							map[string]interface{}{
+								"pods": map[string]interface{}{
+									"namespaceSelector": map[string]interface{}{
+										"matchExpressions": []interface{}{
+											map[string]interface{}{
+												"key":      "kubernetes.io/metadata.name",
+												"operator": "In",
+												// slytherin wasn't applied for the set, so isn't renamed
+												"values": []interface{}{"gryffindor-" + set.ID, "slytherin"},
+											},
+										},
+										"matchLabels": map[string]interface{}{NamespaceSetLabel: set.ID},
+									},
+									"podSelector": map[string]interface{}{},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		Object: map[string]interface{}{
+			"apiVersion": "networking.k8s.io/v1",
+			"kind":       "NetworkPolicy",
+			"metadata": map[string]interface{}{
+				"name":      "allow-from-all-namespaces",
+				"namespace": "gryffindor-" + set.ID,
+			},
+			"spec": map[string]interface{}{
+				"podSelector": map[string]interface{}{},
+				"ingress": []interface{}{
+					map[string]interface{}{
+						"from": []interface{}{
+							map[string]interface{}{
+								"namespaceSelector": map[string]interface{}{
+									"matchLabels": map[string]interface{}{NamespaceSetLabel: set.ID},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}, resources)
+}
@@ -17,9 +17,17 @@ limitations under the License.
 package suite
 
 import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	confv1a1 "sigs.k8s.io/network-policy-api/conformance/apis/v1alpha1"
+	"sigs.k8s.io/network-policy-api/conformance/utils/kubernetes"
 )
 
 // -----------------------------------------------------------------------------
@@ -27,8 +35,14 @@ import (
 // -----------------------------------------------------------------------------
 
 type testResult struct {
-	test   ConformanceTest
-	result resultType
+	test     ConformanceTest
+	result   resultType
+	duration time.Duration
+	// failures is set if the test failed.
+	failures []confv1a1.TestFailure
+	// convergences are the connectivity checks which the test and its
+	// subtests made.
+	convergences []kubernetes.Convergence
 }
 
 type resultType string
@@ -57,6 +71,13 @@ func (p profileReportsMap) addTestResults(conformanceProfile ConformanceProfile,
 	testIsExtended := isTestExtended(conformanceProfile, result.test)
 	report := p[conformanceProfile.Name]
 
+	testReport := confv1a1.TestReport{
+		Name:        result.test.ShortName,
+		Duration:    metav1.Duration{Duration: result.duration.Round(time.Millisecond)},
+		Failures:    result.failures,
+		Convergence: addConvergences(nil, result.convergences),
+	}
+
 	switch result.result {
 	case testSucceeded:
 		if testIsExtended {
@@ -64,9 +85,12 @@ func (p profileReportsMap) addTestResults(conformanceProfile ConformanceProfile,
 				report.Extended = &confv1a1.ExtendedStatus{}
 			}
 			report.Extended.Statistics.Passed++
-
+			testReport.Result = confv1a1.TestPassed
+			report.Extended.Tests = append(report.Extended.Tests, testReport)
 		} else {
 			report.Core.Statistics.Passed++
+			testReport.Result = confv1a1.TestPassed
+			report.Core.Tests = append(report.Core.Tests, testReport)
 		}
 	case testFailed:
 		if testIsExtended {
@@ -78,12 +102,16 @@ func (p profileReportsMap) addTestResults(conformanceProfile ConformanceProfile,
 			}
 			report.Extended.FailedTests = append(report.Extended.FailedTests, result.test.ShortName)
 			report.Extended.Statistics.Failed++
+			testReport.Result = confv1a1.TestFailed
+			report.Extended.Tests = append(report.Extended.Tests, testReport)
 		} else {
 			report.Core.Statistics.Failed++
 			if report.Core.FailedTests == nil {
 				report.Core.FailedTests = []string{}
 			}
 			report.Core.FailedTests = append(report.Core.FailedTests, result.test.ShortName)
+			testReport.Result = confv1a1.TestFailed
+			report.Core.Tests = append(report.Core.Tests, testReport)
 		}
 	case testSkipped:
 		if testIsExtended {
@@ -95,12 +123,26 @@ func (p profileReportsMap) addTestResults(conformanceProfile ConformanceProfile,
 				report.Extended.SkippedTests = []string{}
 			}
 			report.Extended.SkippedTests = append(report.Extended.SkippedTests, result.test.ShortName)
+			testReport.Result = confv1a1.TestSkipped
+			report.Extended.Tests = append(report.Extended.Tests, testReport)
 		} else {
 			report.Core.Statistics.Skipped++
 			if report.Core.SkippedTests == nil {
 				report.Core.SkippedTests = []string{}
 			}
 			report.Core.SkippedTests = append(report.Core.SkippedTests, result.test.ShortName)
+			testReport.Result = confv1a1.TestSkipped
+			report.Core.Tests = append(report.Core.Tests, testReport)
+		}
+	}
+	if len(result.convergences) > 0 {
+		if testIsExtended {
+			if report.Extended == nil {
+				report.Extended = &confv1a1.ExtendedStatus{}
+			}
+			report.Extended.Convergence = addConvergences(report.Extended.Convergence, result.convergences)
+		} else {
+			report.Core.Convergence = addConvergences(report.Core.Convergence, result.convergences)
 		}
 	}
 	p[conformanceProfile.Name] = report
@@ -110,6 +152,9 @@ func (p profileReportsMap) list() (profileReports []confv1a1.ProfileReport) {
 	for _, profileReport := range p {
 		profileReports = append(profileReports, profileReport)
 	}
+	sort.Slice(profileReports, func(i, j int) bool {
+		return profileReports[i].Name < profileReports[j].Name
+	})
 	return
 }
 
@@ -134,6 +179,10 @@ func (p profileReportsMap) compileResults(supportedFeaturesMap map[ConformancePr
 				report.Extended.Result = confv1a1.Success
 			}
 		}
+		sortTestReports(report.Core.Tests)
+		if report.Extended != nil {
+			sortTestReports(report.Extended.Tests)
+		}
 		p[key] = report
 
 		supportedFeatures := supportedFeaturesMap[ConformanceProfileName(report.Name)]
@@ -186,3 +235,162 @@ func isTestExtended(profile ConformanceProfile, test ConformanceTest) bool {
 	}
 	return false
 }
+
+// testConvergences filters the connectivity checks which the test named
+// testName and its subtests made.
+func testConvergences(testName string, convergences []kubernetes.Convergence) []kubernetes.Convergence {
+	var filtered []kubernetes.Convergence
+	for _, convergence := range convergences {
+		if convergence.Test == testName || strings.HasPrefix(convergence.Test, testName+"/") {
+			filtered = append(filtered, convergence)
+		}
+	}
+	return filtered
+}
+
+// addConvergences adds connectivity checks to the statistics, which are
+// initialized if they're nil and there are any checks.
+func addConvergences(statistics *confv1a1.ConvergenceStatistics, convergences []kubernetes.Convergence) *confv1a1.ConvergenceStatistics {
+	if len(convergences) == 0 {
+		return statistics
+	}
+	if statistics == nil {
+		statistics = &confv1a1.ConvergenceStatistics{}
+	}
+	for _, convergence := range convergences {
+		statistics.Checks++
+		if convergence.Converged {
+			statistics.Converged++
+		}
+		if attempts := uint32(convergence.Attempts); attempts > statistics.MaxAttempts {
+			statistics.MaxAttempts = attempts
+		}
+		if duration := convergence.Duration.Round(time.Millisecond); duration > statistics.MaxDuration.Duration {
+			statistics.MaxDuration = metav1.Duration{Duration: duration}
+		}
+	}
+	return statistics
+}
+
+// testFailures finds why the test named testName failed, from the connectivity
+// checks which it and its subtests made that never matched expectations.
+func testFailures(testName string, convergences []kubernetes.Convergence) []confv1a1.TestFailure {
+	var failures []confv1a1.TestFailure
+	for _, convergence := range convergences {
+		if convergence.Converged {
+			continue
+		}
+		if convergence.Test == testName {
+			failures = append(failures, confv1a1.TestFailure{Message: convergence.Failure})
+		} else if subtest, ok := strings.CutPrefix(convergence.Test, testName+"/"); ok {
+			failures = append(failures, confv1a1.TestFailure{Subtest: subtest, Message: convergence.Failure})
+		}
+	}
+	if len(failures) == 0 {
+		failures = append(failures, confv1a1.TestFailure{Message: "see the test log for details"})
+	}
+	return failures
+}
+
+func sortTestReports(tests []confv1a1.TestReport) {
+	sort.Slice(tests, func(i, j int) bool {
+		return tests[i].Name < tests[j].Name
+	})
+}
+
+// -----------------------------------------------------------------------------
+// ConformanceReport - JUnit
+// -----------------------------------------------------------------------------
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// MarshalJUnit renders the per-test results of a ConformanceReport as JUnit
+// XML, for CI systems which display test results. Each support level of each
+// conformance profile is a test suite, e.g. "AdminNetworkPolicy/Core".
+func MarshalJUnit(report confv1a1.ConformanceReport) ([]byte, error) {
+	suites := junitTestSuites{}
+	for _, profile := range report.ProfileReports {
+		suites.Suites = append(suites.Suites, junitSuite(profile.Name, "Core", report.Date, profile.Core))
+		if profile.Extended != nil {
+			suites.Suites = append(suites.Suites, junitSuite(profile.Name, "Extended", report.Date, profile.Extended.Status))
+		}
+	}
+	rawReport, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(rawReport, '\n')...), nil
+}
+
+func junitSuite(profile, level, date string, status confv1a1.Status) junitTestSuite {
+	suite := junitTestSuite{
+		Name:      fmt.Sprintf("%s/%s", profile, level),
+		Tests:     len(status.Tests),
+		Timestamp: date,
+	}
+	var total time.Duration
+	for _, test := range status.Tests {
+		total += test.Duration.Duration
+		testCase := junitTestCase{
+			Name:      test.Name,
+			ClassName: profile,
+			Time:      junitTime(test.Duration.Duration),
+		}
+		switch test.Result {
+		case confv1a1.TestFailed:
+			suite.Failures++
+			var contents []string
+			for _, failure := range test.Failures {
+				if failure.Subtest == "" {
+					contents = append(contents, failure.Message)
+				} else {
+					contents = append(contents, fmt.Sprintf("%s: %s", failure.Subtest, failure.Message))
+				}
+			}
+			testCase.Failure = &junitFailure{
+				Message:  fmt.Sprintf("%d failure(s)", len(test.Failures)),
+				Contents: strings.Join(contents, "\n\n"),
+			}
+		case confv1a1.TestSkipped:
+			suite.Skipped++
+			testCase.Skipped = &junitSkipped{Message: "skipped by the test suite"}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	suite.Time = junitTime(total)
+	return suite
+}
+
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
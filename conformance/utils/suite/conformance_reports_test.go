@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package suite
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	confv1a1 "sigs.k8s.io/network-policy-api/conformance/apis/v1alpha1"
+	"sigs.k8s.io/network-policy-api/conformance/utils/kubernetes"
+)
+
+func TestTestConvergences(t *testing.T) {
+	convergences := []kubernetes.Convergence{
+		{Test: "TestConformanceProfiles/ANPIngressTCP/connectivity/allow_80", Converged: true},
+		{Test: "TestConformanceProfiles/ANPIngressTCP"},
+		{Test: "TestConformanceProfiles/ANPIngressTCPSomethingElse/deny"},
+	}
+
+	assert.Equal(t, convergences[:2], testConvergences("TestConformanceProfiles/ANPIngressTCP", convergences))
+	assert.Empty(t, testConvergences("TestConformanceProfiles/ANPEgressUDP", convergences))
+}
+
+func TestProfileReportsConvergence(t *testing.T) {
+	reports := newReports()
+	reports.addTestResults(ANPConformanceProfile, testResult{
+		test:   ConformanceTest{ShortName: "A", Features: []SupportedFeature{SupportAdminNetworkPolicy}},
+		result: testSucceeded,
+		convergences: []kubernetes.Convergence{
+			{Converged: true, Attempts: 1, Duration: 100 * time.Millisecond},
+			{Converged: true, Attempts: 4, Duration: 3*time.Second + 200*time.Microsecond},
+		},
+	})
+	reports.addTestResults(ANPConformanceProfile, testResult{
+		test:   ConformanceTest{ShortName: "B", Features: []SupportedFeature{SupportAdminNetworkPolicy}},
+		result: testFailed,
+		convergences: []kubernetes.Convergence{
+			{Attempts: 9, Duration: 30 * time.Second},
+		},
+	})
+	reports.addTestResults(ANPConformanceProfile, testResult{
+		test:   ConformanceTest{ShortName: "C", Features: []SupportedFeature{SupportAdminNetworkPolicy, SupportAdminNetworkPolicyNamedPorts}},
+		result: testSucceeded,
+	})
+
+	list := reports.list()
+	require.Len(t, list, 1)
+	assert.Equal(t, &confv1a1.ConvergenceStatistics{
+		Checks:      3,
+		Converged:   2,
+		MaxAttempts: 9,
+		MaxDuration: metav1.Duration{Duration: 30 * time.Second},
+	}, list[0].Core.Convergence)
+	require.NotNil(t, list[0].Extended)
+	assert.Nil(t, list[0].Extended.Convergence)
+}
+
+func TestTestFailures(t *testing.T) {
+	convergences := []kubernetes.Convergence{
+		{Test: "TestConformanceProfiles/Parallel/ANPIngressTCP/connectivity/allow_80", Converged: true},
+		{Test: "TestConformanceProfiles/Parallel/ANPIngressTCP/connectivity/deny_8080", Failure: "expected connection to be dropped"},
+		{Test: "TestConformanceProfiles/Parallel/ANPIngressTCP", Failure: "expected connection to succeed"},
+		{Test: "TestConformanceProfiles/Parallel/ANPIngressTCPSomethingElse/deny", Failure: "other test"},
+	}
+
+	failures := testFailures("TestConformanceProfiles/Parallel/ANPIngressTCP", convergences)
+	assert.Equal(t, []confv1a1.TestFailure{
+		{Subtest: "connectivity/deny_8080", Message: "expected connection to be dropped"},
+		{Message: "expected connection to succeed"},
+	}, failures)
+
+	failures = testFailures("TestConformanceProfiles/ANPEgressUDP", convergences)
+	assert.Equal(t, []confv1a1.TestFailure{{Message: "see the test log for details"}}, failures)
+}
+
+func TestProfileReportsTests(t *testing.T) {
+	reports := newReports()
+	failures := []confv1a1.TestFailure{{Subtest: "deny", Message: "expected connection to be dropped"}}
+	reports.addTestResults(ANPConformanceProfile, testResult{
+		test:     ConformanceTest{ShortName: "B", Features: []SupportedFeature{SupportAdminNetworkPolicy}},
+		result:   testFailed,
+		duration: 1500*time.Millisecond + 700*time.Microsecond,
+		failures: failures,
+	})
+	reports.addTestResults(ANPConformanceProfile, testResult{
+		test:     ConformanceTest{ShortName: "A", Features: []SupportedFeature{SupportAdminNetworkPolicy}},
+		result:   testSucceeded,
+		duration: 2 * time.Second,
+		convergences: []kubernetes.Convergence{
+			{Converged: true, Attempts: 2, Duration: time.Second},
+		},
+	})
+	reports.addTestResults(ANPConformanceProfile, testResult{
+		test:   ConformanceTest{ShortName: "C", Features: []SupportedFeature{SupportAdminNetworkPolicy, SupportAdminNetworkPolicyNamedPorts}},
+		result: testSkipped,
+	})
+	reports.addTestResults(ANPConformanceProfile, testResult{
+		test:   ConformanceTest{ShortName: "D", Features: []SupportedFeature{SupportAdminNetworkPolicy}},
+		result: testNotSupported,
+	})
+	reports.compileResults(nil, nil)
+
+	list := reports.list()
+	require.Len(t, list, 1)
+	assert.Equal(t, []confv1a1.TestReport{
+		{Name: "A", Result: confv1a1.TestPassed, Duration: metav1.Duration{Duration: 2 * time.Second}, Convergence: &confv1a1.ConvergenceStatistics{
+			Checks: 1, Converged: 1, MaxAttempts: 2, MaxDuration: metav1.Duration{Duration: time.Second},
+		}},
+		{Name: "B", Result: confv1a1.TestFailed, Duration: metav1.Duration{Duration: 1501 * time.Millisecond}, Failures: failures},
+	}, list[0].Core.Tests)
+	require.NotNil(t, list[0].Extended)
+	assert.Equal(t, []confv1a1.TestReport{
+		{Name: "C", Result: confv1a1.TestSkipped},
+	}, list[0].Extended.Tests)
+
+	rawJUnit, err := MarshalJUnit(confv1a1.ConformanceReport{Date: "2024-01-01T00:00:00Z", ProfileReports: list})
+	require.NoError(t, err)
+	junit := string(rawJUnit)
+	for _, expected := range []string{
+		`<testsuite name="AdminNetworkPolicy/Core" tests="2" failures="1" skipped="0" time="3.501" timestamp="2024-01-01T00:00:00Z">`,
+		`<testcase name="A" classname="AdminNetworkPolicy" time="2.000"></testcase>`,
+		`<failure message="1 failure(s)">deny: expected connection to be dropped</failure>`,
+		`<testsuite name="AdminNetworkPolicy/Extended" tests="1" failures="0" skipped="1" time="0.000" timestamp="2024-01-01T00:00:00Z">`,
+		`<skipped message="skipped by the test suite"></skipped>`,
+	} {
+		assert.Truef(t, strings.Contains(junit, expected), "expected JUnit to contain %s, got:\n%s", expected, junit)
+	}
+}
@@ -150,26 +150,58 @@ func (suite *ConformanceProfileTestSuite) Run(t *testing.T, tests []ConformanceT
 
 	// run all tests and collect the test results for conformance reporting
 	results := make(map[string]testResult)
-	for _, test := range tests {
-		succeeded := t.Run(test.ShortName, func(t *testing.T) {
+	var resultsLock sync.Mutex
+	runTest := func(t *testing.T, test ConformanceTest) {
+		t.Run(test.ShortName, func(t *testing.T) {
+			start := time.Now()
+			// registered first so that it runs last, once the test and its
+			// cleanup have finished, even if the test runs in parallel
+			t.Cleanup(func() {
+				res := testSucceeded
+				if suite.SkipTests.Has(test.ShortName) {
+					res = testSkipped
+				}
+				if !suite.SupportedFeatures.HasAll(test.Features...) {
+					res = testNotSupported
+				}
+
+				convergences := testConvergences(t.Name(), suite.Convergences())
+				var failures []confv1a1.TestFailure
+				if t.Failed() {
+					res = testFailed
+					failures = testFailures(t.Name(), convergences)
+				}
+
+				resultsLock.Lock()
+				defer resultsLock.Unlock()
+				results[test.ShortName] = testResult{
+					test:         test,
+					result:       res,
+					duration:     time.Since(start),
+					failures:     failures,
+					convergences: convergences,
+				}
+			})
 			test.Run(t, &suite.ConformanceTestSuite)
 		})
-		res := testSucceeded
-		if suite.SkipTests.Has(test.ShortName) {
-			res = testSkipped
-		}
-		if !suite.SupportedFeatures.HasAll(test.Features...) {
-			res = testNotSupported
-		}
-
-		if !succeeded {
-			res = testFailed
-		}
+	}
 
-		results[test.ShortName] = testResult{
-			test:   test,
-			result: res,
+	var parallelTests []ConformanceTest
+	for _, test := range tests {
+		if test.Parallel {
+			parallelTests = append(parallelTests, test)
+			continue
 		}
+		runTest(t, test)
+	}
+	// parallel tests wait for the function of their parent test to return, so
+	// they're grouped to have finished by the time the results are reported
+	if len(parallelTests) > 0 {
+		t.Run("Parallel", func(t *testing.T) {
+			for _, test := range parallelTests {
+				runTest(t, test)
+			}
+		})
 	}
 
 	// now that the tests have completed, mark the test suite as not running
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package suite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// ConnectivityMatrix declares the connectivity expected between the base
+// pods once the manifests of a test are applied, so that tests can be
+// written as data rather than as PokeServer calls.
+type ConnectivityMatrix struct {
+	Connectivity []ConnectivityCell `json:"connectivity"`
+}
+
+// ConnectivityCell is the expected verdict for connections from a client
+// pod to a server pod over a protocol and port.
+type ConnectivityCell struct {
+	// Name describes what the cell checks, and names its subtest.
+	Name string `json:"name"`
+	// Client is the namespace/name of the pod which connects.
+	Client string `json:"client"`
+	// Server is the namespace/name of the pod which is connected to.
+	Server   string              `json:"server"`
+	Protocol v1.Protocol         `json:"protocol"`
+	Port     int32               `json:"port"`
+	Verdict  ConnectivityVerdict `json:"verdict"`
+}
+
+// ConnectivityVerdict is whether a connection should succeed or be dropped.
+type ConnectivityVerdict string
+
+const (
+	ConnectivityAllow ConnectivityVerdict = "Allow"
+	ConnectivityDeny  ConnectivityVerdict = "Deny"
+)
+
+// ParseConnectivityMatrix parses a YAML connectivity matrix, and checks that
+// every cell is complete.
+func ParseConnectivityMatrix(data []byte) (*ConnectivityMatrix, error) {
+	matrix := &ConnectivityMatrix{}
+	if err := yaml.UnmarshalStrict(data, matrix); err != nil {
+		return nil, err
+	}
+	if len(matrix.Connectivity) == 0 {
+		return nil, fmt.Errorf("connectivity matrix has no cells")
+	}
+	names := sets.New[string]()
+	for i, cell := range matrix.Connectivity {
+		if cell.Name == "" {
+			return nil, fmt.Errorf("connectivity[%d]: name is required", i)
+		}
+		if names.Has(cell.Name) {
+			return nil, fmt.Errorf("connectivity[%d]: duplicate name %q", i, cell.Name)
+		}
+		names.Insert(cell.Name)
+		if _, _, err := splitPodName(cell.Client); err != nil {
+			return nil, fmt.Errorf("connectivity[%d] %q: invalid client: %w", i, cell.Name, err)
+		}
+		if _, _, err := splitPodName(cell.Server); err != nil {
+			return nil, fmt.Errorf("connectivity[%d] %q: invalid server: %w", i, cell.Name, err)
+		}
+		switch cell.Protocol {
+		case v1.ProtocolTCP, v1.ProtocolUDP, v1.ProtocolSCTP:
+		default:
+			return nil, fmt.Errorf("connectivity[%d] %q: protocol must be one of TCP, UDP or SCTP, got %q", i, cell.Name, cell.Protocol)
+		}
+		if cell.Port < 1 || cell.Port > 65535 {
+			return nil, fmt.Errorf("connectivity[%d] %q: port must be between 1 and 65535, got %d", i, cell.Name, cell.Port)
+		}
+		switch cell.Verdict {
+		case ConnectivityAllow, ConnectivityDeny:
+		default:
+			return nil, fmt.Errorf("connectivity[%d] %q: verdict must be %s or %s, got %q", i, cell.Name, ConnectivityAllow, ConnectivityDeny, cell.Verdict)
+		}
+	}
+	return matrix, nil
+}
+
+// splitPodName splits a namespace/name pod reference.
+func splitPodName(pod string) (string, string, error) {
+	namespace, name, found := strings.Cut(pod, "/")
+	if !found || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("expected namespace/name, got %q", pod)
+	}
+	return namespace, name, nil
+}
+
+// RunConnectivityMatrix checks every cell of the connectivity matrix at
+// location in the suite's FS. The cells are run in parallel, each as a
+// subtest of a "connectivity" subtest of t, and RunConnectivityMatrix
+// returns once all of them have finished.
+func (suite *ConformanceTestSuite) RunConnectivityMatrix(t *testing.T, location string) {
+	t.Helper()
+	data, err := suite.FS.ReadFile(location)
+	require.NoErrorf(t, err, "unable to read the connectivity matrix %s", location)
+	matrix, err := ParseConnectivityMatrix(data)
+	require.NoErrorf(t, err, "invalid connectivity matrix %s", location)
+
+	ctx, cancel := context.WithTimeout(context.Background(), suite.TimeoutConfig.GetTimeout)
+	defer cancel()
+	serverIPs := map[string]string{}
+	for _, cell := range matrix.Connectivity {
+		if _, ok := serverIPs[cell.Server]; ok {
+			continue
+		}
+		namespace, name, _ := splitPodName(cell.Server)
+		serverPod := &v1.Pod{}
+		err := suite.Client.Get(ctx, client.ObjectKey{Namespace: suite.Namespace(namespace), Name: name}, serverPod)
+		require.NoErrorf(t, err, "unable to fetch the server pod %s", cell.Server)
+		serverIPs[cell.Server] = serverPod.Status.PodIP
+	}
+
+	t.Run("connectivity", func(t *testing.T) {
+		for _, cell := range matrix.Connectivity {
+			cell := cell
+			t.Run(cell.Name, func(t *testing.T) {
+				t.Parallel()
+				namespace, name, _ := splitPodName(cell.Client)
+				success := suite.PokeServer(t, namespace, name, strings.ToLower(string(cell.Protocol)),
+					serverIPs[cell.Server], cell.Port, cell.Verdict == ConnectivityAllow)
+				assert.True(t, success)
+			})
+		}
+	})
+}
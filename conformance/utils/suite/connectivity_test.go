@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package suite
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/network-policy-api/conformance"
+)
+
+func TestParseConnectivityMatrix(t *testing.T) {
+	tests := []struct {
+		name          string
+		given         string
+		expected      *ConnectivityMatrix
+		expectedError string
+	}{{
+		name: "valid",
+		given: `
+connectivity:
+- name: allow from ravenclaw
+  client: network-policy-conformance-ravenclaw/luna-lovegood-0
+  server: network-policy-conformance-gryffindor/harry-potter-0
+  protocol: TCP
+  port: 80
+  verdict: Allow
+`,
+		expected: &ConnectivityMatrix{Connectivity: []ConnectivityCell{{
+			Name:     "allow from ravenclaw",
+			Client:   "network-policy-conformance-ravenclaw/luna-lovegood-0",
+			Server:   "network-policy-conformance-gryffindor/harry-potter-0",
+			Protocol: v1.ProtocolTCP,
+			Port:     80,
+			Verdict:  ConnectivityAllow,
+		}}},
+	}, {
+		name:          "empty",
+		given:         `connectivity: []`,
+		expectedError: "connectivity matrix has no cells",
+	}, {
+		name: "unknown field",
+		given: `
+connectivity:
+- name: allow from ravenclaw
+  client: network-policy-conformance-ravenclaw/luna-lovegood-0
+  server: network-policy-conformance-gryffindor/harry-potter-0
+  protocol: TCP
+  port: 80
+  expect: Allow
+`,
+		expectedError: `unknown field "expect"`,
+	}, {
+		name: "client without namespace",
+		given: `
+connectivity:
+- name: allow from ravenclaw
+  client: luna-lovegood-0
+  server: network-policy-conformance-gryffindor/harry-potter-0
+  protocol: TCP
+  port: 80
+  verdict: Allow
+`,
+		expectedError: `connectivity[0] "allow from ravenclaw": invalid client: expected namespace/name, got "luna-lovegood-0"`,
+	}, {
+		name: "lowercase protocol",
+		given: `
+connectivity:
+- name: allow from ravenclaw
+  client: network-policy-conformance-ravenclaw/luna-lovegood-0
+  server: network-policy-conformance-gryffindor/harry-potter-0
+  protocol: tcp
+  port: 80
+  verdict: Allow
+`,
+		expectedError: `connectivity[0] "allow from ravenclaw": protocol must be one of TCP, UDP or SCTP, got "tcp"`,
+	}, {
+		name: "duplicate name",
+		given: `
+connectivity:
+- name: allow from ravenclaw
+  client: network-policy-conformance-ravenclaw/luna-lovegood-0
+  server: network-policy-conformance-gryffindor/harry-potter-0
+  protocol: TCP
+  port: 80
+  verdict: Allow
+- name: allow from ravenclaw
+  client: network-policy-conformance-ravenclaw/luna-lovegood-1
+  server: network-policy-conformance-gryffindor/harry-potter-0
+  protocol: TCP
+  port: 8080
+  verdict: Deny
+`,
+		expectedError: `connectivity[1]: duplicate name "allow from ravenclaw"`,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			matrix, err := ParseConnectivityMatrix([]byte(tc.given))
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, matrix)
+		})
+	}
+}
+
+// TestConnectivityMatrices checks that the connectivity matrices shipped with
+// the conformance tests are valid, without needing a cluster.
+func TestConnectivityMatrices(t *testing.T) {
+	found := 0
+	err := fs.WalkDir(conformance.Manifests, "base", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, "-connectivity.yaml") {
+			return err
+		}
+		found++
+		data, err := conformance.Manifests.ReadFile(path)
+		require.NoError(t, err)
+		_, err = ParseConnectivityMatrix(data)
+		require.NoErrorf(t, err, "invalid connectivity matrix %s", path)
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotZero(t, found)
+}
@@ -19,6 +19,7 @@ package suite
 import (
 	"embed"
 	"strings"
+	"sync"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -31,6 +32,25 @@ import (
 	"sigs.k8s.io/network-policy-api/conformance/utils/kubernetes"
 )
 
+// baseNamespaces are the namespaces of the base manifests, and baseStatefulSets
+// the StatefulSet in each of them.
+var (
+	baseNamespaces = []string{
+		"network-policy-conformance-gryffindor",
+		"network-policy-conformance-slytherin",
+		"network-policy-conformance-hufflepuff",
+		"network-policy-conformance-ravenclaw",
+		"network-policy-conformance-forbidden-forrest",
+	}
+	baseStatefulSets = []string{
+		"harry-potter",
+		"draco-malfoy",
+		"cedric-diggory",
+		"luna-lovegood",
+		"centaur",
+	}
+)
+
 // ConformanceTestSuite defines the test suite used to run network-policy API
 // conformance tests.
 type ConformanceTestSuite struct {
@@ -45,6 +65,19 @@ type ConformanceTestSuite struct {
 	TimeoutConfig     config.TimeoutConfig
 	SkipTests         sets.Set[string]
 	FS                embed.FS
+
+	// convergences stores how long connectivity took to match expectations for
+	// each PokeServer assertion; it is a pointer so that copies of the suite share it.
+	convergences *convergenceRecorder
+
+	// namespaceSet is set for the copy of the suite given to a parallel test,
+	// see ConformanceTest.Parallel.
+	namespaceSet *kubernetes.NamespaceSet
+}
+
+type convergenceRecorder struct {
+	mutex        sync.Mutex
+	convergences []kubernetes.Convergence
 }
 
 // Options can be used to initialize a ConformanceTestSuite.
@@ -106,6 +139,7 @@ func New(s Options) *ConformanceTestSuite {
 		TimeoutConfig:     s.TimeoutConfig,
 		SkipTests:         sets.New(s.SkipTests...),
 		FS:                *s.FS,
+		convergences:      &convergenceRecorder{},
 	}
 
 	// apply defaults
@@ -126,42 +160,83 @@ func (suite *ConformanceTestSuite) Setup(t *testing.T) {
 		suite.Applier.MustApplyWithCleanup(t, suite.Client, suite.TimeoutConfig, suite.BaseManifests, suite.Cleanup)
 
 		t.Logf("Test Setup: Ensuring Namespaces and Pods from base manifests are ready")
-		namespaces := []string{
-			"network-policy-conformance-gryffindor",
-			"network-policy-conformance-slytherin",
-			"network-policy-conformance-hufflepuff",
-			"network-policy-conformance-ravenclaw",
-			"network-policy-conformance-forbidden-forrest",
-		}
-		statefulSets := []string{
-			"harry-potter",
-			"draco-malfoy",
-			"cedric-diggory",
-			"luna-lovegood",
-			"centaur",
-		}
-		kubernetes.NamespacesMustBeReady(t, suite.Client, suite.TimeoutConfig, namespaces, statefulSets)
+		kubernetes.NamespacesMustBeReady(t, suite.Client, suite.TimeoutConfig, baseNamespaces, baseStatefulSets)
 	}
 }
 
 // Run runs the provided set of conformance tests.
 func (suite *ConformanceTestSuite) Run(t *testing.T, tests []ConformanceTest) {
 	for _, test := range tests {
+		test := test
 		t.Run(test.ShortName, func(t *testing.T) {
 			test.Run(t, suite)
 		})
 	}
 }
 
+// PokeServer checks that the connectivity from clientPod in clientNamespace to targetHost:targetPort over protocol
+// matches shouldConnect, retrying until it converges or the suite's policy convergence timeout expires.
+// The time it took to converge is recorded, see Convergences.
+// clientNamespace is the name of a base namespace; see Namespace.
+func (suite *ConformanceTestSuite) PokeServer(t *testing.T, clientNamespace, clientPod, protocol, targetHost string, targetPort int32, shouldConnect bool) bool {
+	t.Helper()
+	convergence := kubernetes.PokeServerUntilConverged(t, suite.ClientSet, &suite.KubeConfig, suite.Namespace(clientNamespace), clientPod, protocol, targetHost, targetPort, suite.TimeoutConfig, shouldConnect)
+	if suite.convergences != nil {
+		suite.convergences.mutex.Lock()
+		suite.convergences.convergences = append(suite.convergences.convergences, convergence)
+		suite.convergences.mutex.Unlock()
+	}
+	return convergence.Converged
+}
+
+// Namespace returns the name of a base namespace for the running test: a
+// parallel test has its own copy of each base namespace.
+func (suite *ConformanceTestSuite) Namespace(name string) string {
+	if suite.namespaceSet == nil {
+		return name
+	}
+	return suite.namespaceSet.Namespace(name)
+}
+
+// ClusterScopedName returns the name of a cluster-scoped resource, such as an
+// AdminNetworkPolicy, applied from the manifests of the running test: those of
+// a parallel test are renamed so as not to clash with other tests.
+func (suite *ConformanceTestSuite) ClusterScopedName(name string) string {
+	if suite.namespaceSet == nil {
+		return name
+	}
+	return suite.namespaceSet.ClusterScopedName(name)
+}
+
+// Convergences returns how long connectivity took to match expectations for each PokeServer assertion so far,
+// in the order they were made.
+func (suite *ConformanceTestSuite) Convergences() []kubernetes.Convergence {
+	if suite.convergences == nil {
+		return nil
+	}
+	suite.convergences.mutex.Lock()
+	defer suite.convergences.mutex.Unlock()
+	return append([]kubernetes.Convergence(nil), suite.convergences.convergences...)
+}
+
 // ConformanceTest is used to define each individual conformance test.
 type ConformanceTest struct {
 	ShortName   string
 	Description string
 	Features    []SupportedFeature
 	Manifests   []string
-	Slow        bool
-	Parallel    bool
-	Test        func(*testing.T, *ConformanceTestSuite)
+	// ConnectivityMatrix is the location of a ConnectivityMatrix which is
+	// checked once Manifests are applied, before Test is called.
+	ConnectivityMatrix string
+	Slow               bool
+	// Parallel tests run at the same time as each other, each with its own
+	// copy of the base namespaces and pods, and with their manifests scoped to
+	// them; see kubernetes.NamespaceSet for which tests can be parallel.
+	// Tests refer to namespaces and cluster-scoped resources through
+	// ConformanceTestSuite.Namespace and ConformanceTestSuite.ClusterScopedName.
+	Parallel bool
+	// Test may be nil for tests which only check a ConnectivityMatrix.
+	Test func(*testing.T, *ConformanceTestSuite)
 }
 
 // Run runs an individual tests, applying and cleaning up the required manifests
@@ -185,12 +260,46 @@ func (test *ConformanceTest) Run(t *testing.T, suite *ConformanceTestSuite) {
 		return
 	}
 
+	if test.Parallel {
+		suite = suite.withNamespaceSet(t, test.ShortName)
+	}
+
 	for _, manifestLocation := range test.Manifests {
 		t.Logf("Applying %s", manifestLocation)
 		suite.Applier.MustApplyWithCleanup(t, suite.Client, suite.TimeoutConfig, manifestLocation, true)
 	}
 
-	test.Test(t, suite)
+	if test.ConnectivityMatrix != "" {
+		suite.RunConnectivityMatrix(t, test.ConnectivityMatrix)
+	}
+
+	if test.Test != nil {
+		test.Test(t, suite)
+	}
+}
+
+// withNamespaceSet returns a copy of the suite for a parallel test, with its
+// own copy of the base namespaces and pods, which are cleaned up after the test.
+func (suite *ConformanceTestSuite) withNamespaceSet(t *testing.T, testName string) *ConformanceTestSuite {
+	set := kubernetes.NewNamespaceSet(testName)
+	copied := *suite
+	copied.namespaceSet = set
+	copied.Applier.FS = suite.FS
+	copied.Applier.NamespaceSet = set
+
+	t.Logf("Applying base manifests for namespace set %s", set.ID)
+	copied.Applier.MustApplyWithCleanup(t, copied.Client, copied.TimeoutConfig, copied.BaseManifests, true)
+	var namespaces, statefulSets []string
+	for i, namespace := range baseNamespaces {
+		// host-networked pods are left out of namespace sets
+		if baseStatefulSets[i] == "centaur" {
+			continue
+		}
+		namespaces = append(namespaces, set.Namespace(namespace))
+		statefulSets = append(statefulSets, baseStatefulSets[i])
+	}
+	kubernetes.NamespacesMustBeReady(t, copied.Client, copied.TimeoutConfig, namespaces, statefulSets)
+	return &copied
 }
 
 // ParseSupportedFeatures parses flag arguments and converts the string to